@@ -0,0 +1,10 @@
+// Package api содержит контракты внешних API сервиса: OpenAPI спецификацию REST API
+// и protobuf схемы в api/proto.
+package api
+
+import _ "embed"
+
+// OpenAPI — спецификация REST API в формате OpenAPI 3 (JSON).
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,69 @@
+// Команда backfill шифрует персональные данные delivery, записанные открытым текстом
+// или не основным ключом из keyring, и заполняет blind index по email.
+// Запускается после миграции 00002_delivery_encryption и после каждой ротации ключа.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"order_service/config"
+	"order_service/internal/infrastructure/encryption"
+	"order_service/internal/infrastructure/monitoring"
+	"order_service/internal/logger"
+	"order_service/internal/request/repositoriy/postgres"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to the config file (default $ORDER_CONFIG or ./config/config.yaml)")
+	batchSize := flag.Int("batch-size", 500, "number of delivery rows updated per transaction")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fatal(slog.Default(), "Error config", err)
+	}
+
+	log, _, err := logger.NewFromConfig(os.Stdout, cfg)
+	if err != nil {
+		fatal(slog.Default(), "Error logger", err)
+	}
+
+	keyring, err := encryption.LoadKeyring(cfg.Encryption.KeyringFile)
+	if err != nil {
+		fatal(log, "Error encryption", err)
+	}
+
+	db, err := postgres.Connect(ctx, cfg)
+	if err != nil {
+		fatal(log, "Error database", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	repoMetrics, err := monitoring.NewPrometheusRepositoryMetrics()
+	if err != nil {
+		fatal(log, "Error monitoring", err)
+	}
+
+	repo := postgres.NewRequestRepositoryPostgres(db, keyring, repoMetrics, log)
+
+	log.Info("Starting encryption backfill", slog.String("primary_key", keyring.Primary()), slog.Int("batch_size", *batchSize))
+	updated, err := repo.BackfillEncryption(ctx, *batchSize)
+	if err != nil {
+		fatal(log, "Error backfill", err)
+	}
+	log.Info("Encryption backfill finished", slog.Int("updated", updated))
+}
+
+// fatal логирует ошибку и завершает процесс.
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
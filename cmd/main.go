@@ -2,76 +2,221 @@ package main
 
 import (
 	"context"
+	"flag"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"order_service/api"
 	"order_service/config"
+	"order_service/internal/delivery/grpcapi"
 	"order_service/internal/delivery/rest"
 	"order_service/internal/domain"
+	"order_service/internal/infrastructure/auth"
 	"order_service/internal/infrastructure/cache"
+	"order_service/internal/infrastructure/encryption"
 	"order_service/internal/infrastructure/kafka/consumer"
+	"order_service/internal/infrastructure/kafka/publisher"
 	"order_service/internal/infrastructure/monitoring"
+	"order_service/internal/infrastructure/ratelimit"
+	"order_service/internal/infrastructure/schema"
+	"order_service/internal/infrastructure/tracing"
 	"order_service/internal/logger"
+	"order_service/internal/request/repositoriy/migrations"
 	"order_service/internal/request/repositoriy/postgres"
 	"order_service/internal/usecase"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/jmoiron/sqlx"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	configPath := flag.String("config", "", "path to the config file (default $ORDER_CONFIG or ./config/config.yaml)")
+	flag.Parse()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
-		logger.ErrorLogger.Fatalln("Ошибка загрузки internal/config/config.yaml файла:", err)
+		fatal(slog.Default(), "Ошибка загрузки конфигурации", err)
 	}
 
-	logger.InitLogger(cfg)
+	log, logLevel, err := logger.NewFromConfig(os.Stdout, cfg)
+	if err != nil {
+		fatal(slog.Default(), "Error logger", err)
+	}
 
-	db, err := sqlx.ConnectContext(ctx, "pgx", config.GetDbConnString(cfg))
+	tp, err := tracing.NewTracerProvider(ctx, cfg)
 	if err != nil {
-		logger.ErrorLogger.Fatalln("Не удалось подключиться к базе данных:", err)
+		fatal(log, "Error tracing", err)
+	}
+	defer func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Error("Error shutting down tracer provider", slog.Any("error", err))
+		}
+	}()
+
+	db, err := postgres.Connect(ctx, cfg)
+	if err != nil {
+		fatal(log, "Не удалось подключиться к базе данных", err)
 	}
 	defer db.Close() //nolint:errcheck
 
-	cache := cache.NewLRUCache(cfg)
-	repo := postgres.NewRequestRepositoryPostgres(db)
-	service := usecase.NewOrderRequestService(cache, repo)
+	if err := migrations.Run(ctx, db.DB, cfg.Db.Migrations, log); err != nil {
+		fatal(log, "Error migrations", err)
+	}
+
+	if err := monitoring.RegisterDBStats(db.DB, cfg.Db.Database); err != nil {
+		fatal(log, "Error monitoring", err)
+	}
+	cacheMetrics, err := monitoring.NewPrometheusCacheMetrics()
+	if err != nil {
+		fatal(log, "Error monitoring", err)
+	}
+	repoMetrics, err := monitoring.NewPrometheusRepositoryMetrics()
+	if err != nil {
+		fatal(log, "Error monitoring", err)
+	}
+
+	keyring, err := encryption.LoadKeyring(cfg.Encryption.KeyringFile)
+	if err != nil {
+		fatal(log, "Error encryption", err)
+	}
+
+	cache := cache.NewLRUCache(cfg, cacheMetrics)
+	defer cache.Close()
+	repo := postgres.NewRequestRepositoryPostgres(db, keyring, repoMetrics, log)
+	// Журнал сообщений и outbox relay всегда работают через database/sql, pgxpool заменяет
+	// только репозиторий заказов
+	var orderRepo domain.OrderRepository = repo
+	if cfg.Db.Repository == config.RepositoryPgxPool {
+		pool, err := postgres.NewPool(ctx, cfg)
+		if err != nil {
+			fatal(log, "Не удалось подключиться к базе данных", err)
+		}
+		defer pool.Close()
+		orderRepo = postgres.NewOrderRepositoryPgx(pool, keyring, repoMetrics, log)
+	}
+	service := usecase.NewOrderRequestService(cache, orderRepo, log)
 	httpMetrics, err := monitoring.NewPrometheusMetrics()
 	if err != nil {
-		logger.ErrorLogger.Fatalln("Error monitoring:", err)
+		fatal(log, "Error monitoring", err)
+	}
+	consumerMetrics, err := monitoring.NewPrometheusConsumerMetrics()
+	if err != nil {
+		fatal(log, "Error monitoring", err)
+	}
+	var authenticator domain.Authenticator
+	if cfg.Auth.Enabled {
+		a, err := auth.NewAuthenticator(cfg)
+		if err != nil {
+			fatal(log, "Error auth", err)
+		}
+		authenticator = a
+	}
+
+	openAPI, err := rest.NewOpenAPI(api.OpenAPI)
+	if err != nil {
+		fatal(log, "Error openapi", err)
+	}
+
+	handler := rest.NewHandler(service, httpMetrics, log)
+	adminHandler := rest.NewAdminHandler(logLevel, log)
+	registry, err := schema.LoadRegistry(cfg.Schema, log)
+	if err != nil {
+		fatal(log, "Error schema registry", err)
 	}
-	handler := rest.NewHandler(service, httpMetrics)
-	consumer := consumer.NewConsumer(cfg)
+	consumer := consumer.NewConsumer(cfg, registry, consumerMetrics, log)
+
+	orderLimiter := ratelimit.NewReloadableLimiter(cfg.RateLimit.MaxClients)
+	adminLimiter := ratelimit.NewReloadableLimiter(cfg.RateLimit.MaxClients)
+	ipLimiter := ratelimit.NewReloadableLimiter(cfg.RateLimit.MaxClients)
+	setRouteLimit(orderLimiter, cfg, "order")
+	setRouteLimit(adminLimiter, cfg, "admin")
+	setRouteLimit(ipLimiter, cfg, "ip")
+
+	watcher := config.NewWatcher(*configPath, cfg, log)
+	watcher.Subscribe("log", []string{"log.level"}, func(cfg *config.Config) error {
+		level, err := logger.LevelFromConfig(cfg)
+		if err != nil {
+			return err
+		}
+		logLevel.Set(level)
+		return nil
+	})
+	watcher.Subscribe("rate_limit", []string{"rate_limit.enabled", "rate_limit.routes"}, func(cfg *config.Config) error {
+		setRouteLimit(orderLimiter, cfg, "order")
+		setRouteLimit(adminLimiter, cfg, "admin")
+		setRouteLimit(ipLimiter, cfg, "ip")
+		return nil
+	})
+	watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+		cache.SetTTL(cfg.TTL)
+		return nil
+	})
+	watcher.Subscribe("schema", []string{"schema.strict"}, func(cfg *config.Config) error {
+		registry.SetStrict(cfg.Schema.Strict)
+		return nil
+	})
 
 	mux := http.NewServeMux()
 	mux.Handle("/", http.FileServer(http.Dir("ui")))
-	mux.Handle("/metrics", promhttp.Handler())
-	mux.HandleFunc("GET /api/v1/order/{order_uid}", handler.GetOrders())
+	rest.RegisterRoutes(mux, handler, adminHandler, openAPI, authenticator, rest.Limiters{
+		Order: orderLimiter,
+		Admin: adminLimiter,
+		IP:    ipLimiter,
+	}, log)
+
+	maxConcurrent, maxStreams := 0, 0
+	if cfg.RateLimit.Enabled {
+		maxConcurrent, maxStreams = cfg.RateLimit.MaxConcurrent, cfg.RateLimit.MaxStreams
+	}
+	routes := rest.Chain(mux, openAPI.ValidateRequests(log), rest.Compress())
+	// Поток событий открыт все время подписки и занимал бы слот max_concurrent,
+	// поэтому у потоков свой лимит
+	limited := http.NewServeMux()
+	limited.Handle("GET /api/v1/orders/stream", rest.Chain(routes, rest.ConcurrencyLimit(maxStreams, log)))
+	limited.Handle("/", rest.Chain(routes, rest.ConcurrencyLimit(maxConcurrent, log)))
+	httpHandler := rest.Chain(limited, rest.RequestID(), rest.AccessLog(log), rest.Recovery(log))
 
 	serv := &http.Server{
 		Addr:         config.GetServerAddr(cfg),
-		Handler:      mux,
+		Handler:      otelhttp.NewHandler(httpHandler, "order_service"),
 		ReadTimeout:  time.Duration(cfg.Serv.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.Serv.WriteTimeout) * time.Second,
 		IdleTimeout:  time.Duration(cfg.Serv.IdleTimeout) * time.Second,
 	}
+	serv.RegisterOnShutdown(handler.StopStreams)
+
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcMetrics, err := monitoring.NewPrometheusGRPCMetrics()
+		if err != nil {
+			fatal(log, "Error monitoring", err)
+		}
+		grpcServer = grpcapi.NewServer(grpcapi.NewOrderServer(service, log), authenticator, grpcMetrics, cfg.GRPC.Reflection, log)
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	if err := service.RestoreCache(ctx, cfg); err != nil {
-		logger.ErrorLogger.Println("Error restoring cache:", err)
+		log.Error("Error restoring cache", slog.Any("error", err))
 	}
 
+	deadLetters := publisher.NewDeadLetterQueue(publisher.NewDLQWriter(cfg), cfg.DLQTopic, log)
+	defer deadLetters.Close() //nolint:errcheck
+	ingester := usecase.NewOrderIngester(service, repo, deadLetters, consumerMetrics, cfg, log)
+
+	go ingester.RunCleanup(ctx)
+	go consumer.ReportLag(ctx)
 	go func() {
-		logger.InfoLogger.Println("Starting Kafka consumer...")
+		log.Info("Starting Kafka consumer...")
 
 		ticker := time.NewTicker(time.Duration(cfg.PollTimeout) * time.Millisecond)
 		defer ticker.Stop()
@@ -80,31 +225,75 @@ func main() {
 		for {
 			select {
 			case <-ctx.Done():
-				logger.InfoLogger.Println("Kafka consumer is stopped")
+				log.Info("Kafka consumer is stopped")
 				return
 			case <-ticker.C:
-				order, err := consumer.ReadMessage(ctx)
+				msgCtx, msg, end, err := consumer.ReadMessage(ctx)
 				if err != nil {
-					logger.ErrorLogger.Println("Error reading message:", err)
+					log.ErrorContext(msgCtx, "Error reading message", slog.Any("error", err))
 					continue
 				}
 
-				if domain.ValidateOrder(order) != nil {
-					logger.ErrorLogger.Println("Invalid order:", err)
+				err = domain.ValidateOrder(msg.Order)
+				if err != nil {
+					consumerMetrics.IncValidationFailure(domain.ValidationRule(err))
+					log.ErrorContext(msgCtx, "Invalid order", slog.Any("error", err), slog.Any("order", msg.Order))
+				} else if _, err = ingester.IngestWithRetry(msgCtx, msg); err != nil {
+					// Сервис останавливается: смещение не коммитим, сообщение прочитается заново
+					end(err)
 					continue
 				}
 
-				if err := service.SaveOrder(ctx, order); err != nil {
-					logger.ErrorLogger.Println("Error saving order:", err)
+				// Смещение коммитится только после того, как заказ сохранен, пропущен как повтор
+				// или отправлен в DLQ: упавший до коммита сервис прочитает сообщение заново,
+				// а журнал обработанных сообщений отсечет повтор
+				if commitErr := consumer.Commit(msgCtx, msg); commitErr != nil {
+					log.ErrorContext(msgCtx, "Error committing message", slog.Any("error", commitErr))
+					if err == nil {
+						err = commitErr
+					}
 				}
+				end(err)
 			}
 		}
 	}()
 
+	go func() {
+		if err := watcher.Run(ctx); err != nil {
+			log.Error("Error config watcher", slog.Any("error", err))
+		}
+	}()
+
+	if cfg.Outbox.Enabled {
+		outboxMetrics, err := monitoring.NewPrometheusOutboxMetrics()
+		if err != nil {
+			fatal(log, "Error monitoring", err)
+		}
+		eventPublisher := publisher.NewPublisher(publisher.NewKafkaWriter(cfg), cfg.Outbox.Topic, log)
+		defer eventPublisher.Close() //nolint:errcheck
+
+		relay := usecase.NewOutboxRelay(repo, eventPublisher, outboxMetrics, cfg, log)
+		go relay.Run(ctx)
+	}
+
+	if grpcServer != nil {
+		listener, err := net.Listen("tcp", config.GetGRPCAddr(cfg))
+		if err != nil {
+			fatal(log, "gRPC server listen error", err)
+		}
+
+		go func() {
+			log.Info("gRPC server is running...", slog.String("addr", listener.Addr().String()))
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal(log, "gRPC server error", err)
+			}
+		}()
+	}
+
 	go func() {
 		<-quit
 
-		logger.InfoLogger.Println("Order Service is stopping...")
+		log.Info("Order Service is stopping...")
 
 		timeoutCtx, timeoutCtxCancel := context.WithTimeout(
 			ctx,
@@ -112,15 +301,50 @@ func main() {
 		)
 		defer timeoutCtxCancel()
 
+		if grpcServer != nil {
+			stopGRPC(timeoutCtx, grpcServer)
+		}
 		if err := serv.Shutdown(timeoutCtx); err != nil {
-			logger.ErrorLogger.Fatalln("Order Service shutdown error:", err)
+			fatal(log, "Order Service shutdown error", err)
 		}
-		logger.InfoLogger.Println("Order Service is stopped")
+		log.Info("Order Service is stopped")
 	}()
 
-	logger.InfoLogger.Println("Order Service is running...")
+	log.Info("Order Service is running...")
 
 	if err := serv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		logger.ErrorLogger.Fatalln("Order Service start error:", err)
+		fatal(log, "Order Service start error", err)
+	}
+}
+
+// setRouteLimit задает лимит маршрута из секции rate_limit. Если лимиты выключены
+// или для маршрута не заданы, лимит снимается.
+func setRouteLimit(limiter *ratelimit.ReloadableLimiter, cfg *config.Config, route string) {
+	limit := cfg.RateLimit.Routes[route]
+	if !cfg.RateLimit.Enabled {
+		limit = config.RouteRateLimit{}
 	}
+	limiter.SetLimit(limit.RPS, limit.Burst)
+}
+
+// stopGRPC дожидается завершения текущих вызовов, но не дольше ctx: потоки WatchOrders
+// сами не заканчиваются, поэтому по истечении времени соединения закрываются принудительно.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// fatal логирует ошибку и завершает процесс.
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/infrastructure/kafka/consumer"
+)
+
+// loadKafkaConfig загружает конфигурацию и подставляет топик и группу из флагов, если они заданы.
+func loadKafkaConfig(topic, groupID string) (*config.Config, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if topic != "" {
+		cfg.Topic = topic
+	}
+	if groupID != "" {
+		cfg.GroupID = groupID
+	}
+	return cfg, nil
+}
+
+// runLag печатает отставание consumer group по партициям.
+func runLag(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("lag", "", "Prints committed and end offsets of every partition and how many messages the\n"+
+		"consumer group has yet to read.")
+	topic := flags.String("topic", "", "topic (default kafka.topic from config)")
+	groupID := flags.String("group", "", "consumer group (default kafka.group_id from config)")
+	if err := parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := loadKafkaConfig(*topic, *groupID)
+	if err != nil {
+		return err
+	}
+	lags, err := consumer.NewGroupAdmin(cfg, log).Lag(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("group %s, topic %s\n", cfg.GroupID, cfg.Topic)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PARTITION\tCOMMITTED\tEND\tLAG\t")
+	var total int64
+	for _, lag := range lags {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t\n", lag.Partition, offset(lag.Committed), lag.End, lag.Lag)
+		total += lag.Lag
+	}
+	fmt.Fprintf(w, "total\t\t\t%d\t\n", total)
+	return w.Flush()
+}
+
+// runOffsets сдвигает смещения consumer group на момент времени.
+func runOffsets(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("offsets", "-to <time>", "Moves the consumer group in every partition to the first message written at or\n"+
+		"after -to, so the service re-reads messages from that moment (or skips everything before it).\n"+
+		"The service must be stopped: Kafka rejects commits while the group has members.")
+	to := flags.String("to", "", "RFC 3339 time (2024-05-01T10:00:00Z) or a duration ago (90m, 24h)")
+	topic := flags.String("topic", "", "topic (default kafka.topic from config)")
+	groupID := flags.String("group", "", "consumer group (default kafka.group_id from config)")
+	dryRun := flags.Bool("dry-run", false, "print new offsets without committing them")
+	if err := parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *to == "" {
+		flags.Usage()
+		return errUsage
+	}
+	at, err := parseTime(*to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadKafkaConfig(*topic, *groupID)
+	if err != nil {
+		return err
+	}
+	resets, err := consumer.NewGroupAdmin(cfg, log).ResetOffsets(ctx, at, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	action := "reset"
+	if *dryRun {
+		action = "would reset (dry run)"
+	}
+	fmt.Printf("group %s, topic %s: %s to %s\n", cfg.GroupID, cfg.Topic, action, at.Format(time.RFC3339))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PARTITION\tFROM\tTO\t")
+	for _, reset := range resets {
+		fmt.Fprintf(w, "%d\t%s\t%d\t\n", reset.Partition, offset(reset.From), reset.To)
+	}
+	return w.Flush()
+}
+
+// parseTime разбирает время в RFC 3339 или длительность, отсчитываемую назад от now.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	ago, err := time.ParseDuration(strings.TrimPrefix(value, "-"))
+	if err != nil || ago < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339 or a duration like 24h", value)
+	}
+	return now.Add(-ago), nil
+}
+
+// offset форматирует смещение, -1 — нет коммита.
+func offset(value int64) string {
+	if value < 0 {
+		return "-"
+	}
+	return fmt.Sprint(value)
+}
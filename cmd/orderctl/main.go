@@ -0,0 +1,140 @@
+// Команда orderctl — инструменты для эксплуатации сервиса заказов:
+//
+//	orderctl get <order_uid>                 напечатать заказ
+//	orderctl export [-o file]                выгрузить заказы в NDJSON
+//	orderctl import <files...>               загрузить заказы из NDJSON
+//	orderctl validate <files...>             проверить заказы из файлов по правилам domain.ValidateOrder
+//	orderctl migrate up|down|status|version  управлять миграциями БД
+//	orderctl lag                             показать отставание consumer group
+//	orderctl offsets -to <time>              сдвинуть смещения consumer group на момент времени
+//	orderctl produce [files...]              отправить синтетические заказы или повторить сообщения из файлов
+//
+// Настройки берутся из файла -config (по умолчанию $ORDER_CONFIG или ./config/config.yaml)
+// и переменных окружения ORDER_*, флаги команд их переопределяют:
+//
+//	orderctl -config /etc/order/config.yaml lag
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"order_service/internal/logger"
+)
+
+// configPath — путь к файлу конфигурации из флага -config.
+var configPath string
+
+// errUsage — неверные аргументы команды; справка по команде уже напечатана.
+var errUsage = errors.New("invalid usage")
+
+// command — подкоманда orderctl.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, log *slog.Logger) error
+}
+
+// commands — подкоманды в порядке справки.
+var commands = []command{
+	{name: "get", summary: "print an order as JSON", run: runGet},
+	{name: "export", summary: "export all orders as NDJSON", run: runExport},
+	{name: "import", summary: "import orders from NDJSON or JSON files", run: runImport},
+	{name: "validate", summary: "validate orders in JSON files against the domain rules", run: runValidate},
+	{name: "migrate", summary: "apply, roll back or inspect database migrations", run: runMigrate},
+	{name: "lag", summary: "show consumer group lag per partition", run: runLag},
+	{name: "offsets", summary: "reset consumer group offsets to a point in time", run: runOffsets},
+	{name: "produce", summary: "send synthetic orders or replay NDJSON files and DLQ dumps to Kafka", run: runProduce},
+}
+
+func main() {
+	global := flag.NewFlagSet("orderctl", flag.ContinueOnError)
+	global.Usage = usage
+	global.StringVar(&configPath, "config", "", "path to the config file")
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	args := global.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	level := &slog.LevelVar{}
+	log, err := logger.New(os.Stderr, logger.FormatText, level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	err = cmd.run(ctx, args[1:], log)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		log.Error("orderctl "+cmd.name+" failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: orderctl [-config file] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nThe config file defaults to $ORDER_CONFIG or ./config/config.yaml; ORDER_* variables override its keys.")
+	fmt.Fprintln(os.Stderr, "Run 'orderctl <command> -h' for command flags.")
+}
+
+// newFlagSet создает набор флагов команды со справкой: строкой использования и описанием.
+func newFlagSet(name, args, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: orderctl %s [flags] %s\n\n%s\n", name, args, description)
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(flags.Output(), "\nFlags:")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseFlags разбирает флаги и проверяет, что позиционных аргументов не меньше minArgs
+// и не больше maxArgs (maxArgs < 0 — без ограничения).
+func parseFlags(flags *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if flags.NArg() < minArgs || (maxArgs >= 0 && flags.NArg() > maxArgs) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"order_service/config"
+	"order_service/internal/request/repositoriy/migrations"
+	"order_service/internal/request/repositoriy/postgres"
+
+	"github.com/pressly/goose/v3"
+)
+
+// runMigrate применяет, откатывает и показывает миграции goose.
+func runMigrate(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("migrate", "up|down|status|version",
+		"up       applies all pending migrations\n"+
+			"down     rolls back the last applied migration\n"+
+			"status   lists migrations with their state\n"+
+			"version  prints the database and latest migration versions")
+	dir := flags.String("dir", "", "migrations directory (default the migrations built into orderctl)")
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	action := flags.Arg(0)
+	if action != "up" && action != "down" && action != "status" && action != "version" {
+		flags.Usage()
+		return errUsage
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	db, err := postgres.Connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	provider, err := migrations.NewProvider(db.DB)
+	if *dir != "" {
+		provider, err = goose.NewProvider(goose.DialectPostgres, db.DB, os.DirFS(*dir))
+	}
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		results, err := provider.Up(ctx)
+		for _, result := range results {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		result, err := provider.Down(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return err
+		}
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "-"
+			if status.State == goose.StateApplied {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-8s %-20s %s\n", status.State, applied, status.Source.Path)
+		}
+	case "version":
+		current, latest, err := provider.GetVersions(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("database: %d, latest: %d\n", current, latest)
+	}
+	log.Debug("Migrate finished", slog.String("action", action))
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"order_service/config"
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/encryption"
+	"order_service/internal/infrastructure/monitoring"
+	"order_service/internal/request/repositoriy/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// errInvalidOrders — часть заказов не прошла проверку или не сохранилась; подробности в выводе.
+var errInvalidOrders = errors.New("some orders were rejected")
+
+// openRepository подключается к базе и создает репозиторий заказов с ключами шифрования из конфигурации.
+func openRepository(ctx context.Context, cfg *config.Config, log *slog.Logger) (*postgres.RequestRepositoryPostgres, *sqlx.DB, error) {
+	keyring, err := encryption.LoadKeyring(cfg.Encryption.KeyringFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	repoMetrics, err := monitoring.NewPrometheusRepositoryMetrics()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := postgres.Connect(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return postgres.NewRequestRepositoryPostgres(db, keyring, repoMetrics, log), db, nil
+}
+
+// runGet печатает заказ в JSON.
+func runGet(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("get", "<order_uid>", "Prints the order with all its items as indented JSON.")
+	redact := flags.Bool("redact", false, "mask the recipient's personal data as the API does")
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	repo, db, err := openRepository(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	order, err := repo.GetOrder(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *redact {
+		order = order.Redacted()
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(order)
+}
+
+// runExport выгружает все заказы в NDJSON в порядке order_uid.
+func runExport(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("export", "", "Writes every order as one JSON line, in order_uid order. The output can be\n"+
+		"loaded back with 'orderctl import' or replayed with 'orderctl produce'.")
+	output := flags.String("o", "-", "output file, - for stdout")
+	batchSize := flags.Int("batch-size", 500, "orders read per query")
+	if err := parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size must be positive, got %d", *batchSize)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	repo, db, err := openRepository(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	out := os.Stdout
+	if *output != "-" {
+		if out, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer out.Close() //nolint:errcheck
+	}
+	writer := bufio.NewWriter(out)
+	encoder := json.NewEncoder(writer)
+
+	exported := 0
+	lastUID := ""
+	for {
+		orders, err := repo.ListOrdersAfter(ctx, lastUID, *batchSize)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			break
+		}
+		for _, order := range orders {
+			if err := encoder.Encode(order); err != nil {
+				return err
+			}
+		}
+		exported += len(orders)
+		lastUID = orders[len(orders)-1].OrderUID
+		log.Debug("Exported batch", slog.Int("orders", len(orders)), slog.Int("total", exported))
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Orders exported", slog.Int("orders", exported), slog.String("output", *output))
+	return nil
+}
+
+// runImport сохраняет заказы из файлов. Заказы, не прошедшие domain.ValidateOrder, пропускаются.
+func runImport(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("import", "<files...>", "Saves orders from NDJSON or JSON files (- for stdin). Every order is checked\n"+
+		"with the same rules as orders from Kafka; invalid ones are reported and skipped. Orders that\n"+
+		"already exist are left as they are, new ones get an order.created event in the outbox.")
+	if err := parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	repo, db, err := openRepository(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	imported, existing, rejected := 0, 0, 0
+	for _, path := range flags.Args() {
+		err := readOrders(path, func(n int, order *domain.Order) error {
+			if err := domain.ValidateOrder(order); err != nil {
+				rejected++
+				fmt.Printf("%s #%d %s: invalid: %v\n", path, n, order.OrderUID, err)
+				return nil
+			}
+			inserted, err := repo.SaveOrder(ctx, order)
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				rejected++
+				fmt.Printf("%s #%d %s: not saved: %v\n", path, n, order.OrderUID, err)
+				return nil
+			}
+			if !inserted {
+				existing++
+				return nil
+			}
+			imported++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("imported: %d, existing: %d, rejected: %d\n", imported, existing, rejected)
+	if rejected > 0 {
+		return errInvalidOrders
+	}
+	return nil
+}
+
+// runValidate проверяет заказы из файлов правилами domain.ValidateOrder.
+func runValidate(_ context.Context, args []string, _ *slog.Logger) error {
+	flags := newFlagSet("validate", "<files...>", "Checks orders in NDJSON or JSON files (- for stdin) with the rules the service\n"+
+		"applies to orders from Kafka. Exits with status 1 if any order is invalid.")
+	quiet := flags.Bool("q", false, "print invalid orders only")
+	if err := parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	valid, invalid := 0, 0
+	for _, path := range flags.Args() {
+		err := readOrders(path, func(n int, order *domain.Order) error {
+			if err := domain.ValidateOrder(order); err != nil {
+				invalid++
+				fmt.Printf("%s #%d %s: %s: %v\n", path, n, order.OrderUID, domain.ValidationRule(err), err)
+				return nil
+			}
+			valid++
+			if !*quiet {
+				fmt.Printf("%s #%d %s: ok\n", path, n, order.OrderUID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("valid: %d, invalid: %d\n", valid, invalid)
+	if invalid > 0 {
+		return errInvalidOrders
+	}
+	return nil
+}
+
+// readOrders читает заказы из файла path (- — stdin) и передает их fn с номером заказа в файле,
+// начиная с 1. В файле может быть один JSON-заказ или несколько подряд, например NDJSON.
+func readOrders(path string, fn func(n int, order *domain.Order) error) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path) // #nosec G304 -- файлы указывает оператор
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		in = file
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(in))
+	for n := 1; ; n++ {
+		order := &domain.Order{}
+		err := decoder.Decode(order)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s #%d: %w", path, n, err)
+		}
+		if err := fn(n, order); err != nil {
+			return err
+		}
+	}
+}
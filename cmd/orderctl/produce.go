@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/infrastructure/kafka/producer"
+	"order_service/internal/infrastructure/schema"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// errProduceFailed — часть сообщений не отправлена; подробности в отчете.
+var errProduceFailed = errors.New("some messages were not sent")
+
+// runProduce отправляет синтетические заказы, а если переданы файлы — сообщения из них.
+func runProduce(ctx context.Context, args []string, log *slog.Logger) error {
+	flags := newFlagSet("produce", "[files...]",
+		"Without files, generates synthetic orders. With files, replays them: NDJSON or JSON orders\n"+
+			"(-format orders) or a `kcat -C -J` dump of the DLQ topic (-format dlq). Message keys are order_uid.")
+	topic := flags.String("topic", "", "target topic (default kafka.topic from config)")
+	count := flags.Int("count", 100, "synthetic orders to send, 0 — until interrupted")
+	rate := flags.Float64("rate", 0, "target rate in messages per second, 0 — as fast as possible")
+	seed := flags.Uint64("seed", 1, "faker seed; the same seed generates the same orders")
+	invalidRatio := flags.Float64("invalid-ratio", 0, "fraction of synthetic orders with a random defect (0..1)")
+	format := flags.String("format", string(producer.ReplayOrders), "replay file format: orders | dlq")
+	schemaVersion := flags.String("schema-version", "", "schema-version header for JSON messages; empty — unversioned")
+	batchSize := flags.Int("batch-size", 100, "messages per Kafka write")
+	reportEvery := flags.Duration("report-every", 5*time.Second, "progress report interval, 0 — final report only")
+	if err := parseFlags(flags, args, 0, -1); err != nil {
+		return err
+	}
+	if *invalidRatio < 0 || *invalidRatio > 1 {
+		return fmt.Errorf("invalid-ratio must be within 0..1, got %v", *invalidRatio)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if *topic == "" {
+		*topic = cfg.Topic
+	}
+
+	headers := []kafka.Header{{Key: schema.HeaderContentType, Value: []byte(schema.ContentTypeJSON)}}
+	if *schemaVersion != "" {
+		headers = append(headers, kafka.Header{Key: schema.HeaderSchemaVersion, Value: []byte(*schemaVersion)})
+	}
+
+	var (
+		source    producer.Source
+		generator *producer.GeneratorSource
+	)
+	if files := flags.Args(); len(files) > 0 {
+		replay, err := producer.NewReplaySource(files, producer.ReplayFormat(*format), headers)
+		if err != nil {
+			return err
+		}
+		defer replay.Close() //nolint:errcheck
+		source = replay
+	} else {
+		generator = producer.NewGeneratorSource(producer.NewFaker(*seed), *count, *invalidRatio, headers)
+		source = generator
+	}
+
+	writer := producer.NewKafkaWriter(cfg, *topic)
+	defer writer.Close() //nolint:errcheck
+
+	p := producer.NewProducer(writer, producer.Options{Rate: *rate, BatchSize: *batchSize, ReportEvery: *reportEvery}, log)
+	log.Info("Producing orders", slog.String("topic", *topic), slog.Float64("rate", *rate), slog.Int("files", flags.NArg()))
+
+	report, err := p.Run(ctx, source, func(r producer.Report) {
+		fmt.Printf("progress: sent=%d failed=%d elapsed=%s rate=%.1f msg/s\n",
+			r.Sent, r.Failed, r.Elapsed.Round(time.Millisecond), r.Throughput())
+	})
+	printReport(os.Stdout, report, generator)
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return errProduceFailed
+	}
+	return nil
+}
+
+// printReport печатает итоговый отчет.
+func printReport(w io.Writer, report producer.Report, generator *producer.GeneratorSource) {
+	fmt.Fprintf(w, "sent:       %d messages, %d bytes\n", report.Sent, report.Bytes)
+	fmt.Fprintf(w, "failed:     %d messages\n", report.Failed)
+	fmt.Fprintf(w, "elapsed:    %s\n", report.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "throughput: %.1f msg/s\n", report.Throughput())
+
+	if generator != nil && len(generator.Defects()) > 0 {
+		fmt.Fprintln(w, "invalid orders:")
+		defects := generator.Defects()
+		for _, defect := range slices.Sorted(maps.Keys(defects)) {
+			fmt.Fprintf(w, "  %-20s %d\n", defect, defects[defect])
+		}
+	}
+	if len(report.Errors) > 0 {
+		fmt.Fprintln(w, "errors:")
+		for _, msg := range slices.Sorted(maps.Keys(report.Errors)) {
+			fmt.Fprintf(w, "  %6d  %s\n", report.Errors[msg], strings.TrimSpace(msg))
+		}
+	}
+}
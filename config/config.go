@@ -2,11 +2,18 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
-const cfgPath = "./config"
+const defaultCfgFile = "./config/config.yaml"
 
 type Server struct {
 	Host            string `mapstructure:"host"`
@@ -18,60 +25,216 @@ type Server struct {
 	Debug           bool   `mapstructure:"debug"`
 }
 
+// Реализации репозитория заказов, postgres.repository.
+const (
+	RepositorySQLX    = "sqlx"
+	RepositoryPgxPool = "pgxpool"
+)
+
+type Pool struct {
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	MinIdleConns    int           `mapstructure:"min_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+}
+
 type Postgres struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Database string `mapstructure:"database"`
-	SSLMode  string `mapstructure:"ssl_mode"`
+	Host             string        `mapstructure:"host"`
+	Port             int           `mapstructure:"port"`
+	User             string        `mapstructure:"user"`
+	Password         string        `mapstructure:"password"`
+	Database         string        `mapstructure:"database"`
+	SSLMode          string        `mapstructure:"ssl_mode"`
+	Migrations       string        `mapstructure:"migrations"`
+	Repository       string        `mapstructure:"repository"`
+	ApplicationName  string        `mapstructure:"application_name"`
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	Pool             Pool          `mapstructure:"pool"`
 }
 
 type Kafka struct {
-	Network     string   `mapstructure:"network"`
-	Brokers     []string `mapstructure:"brokers"`
-	Topic       string   `mapstructure:"topic"`
-	GroupID     string   `mapstructure:"group_id"`
-	PollTimeout int      `mapstructure:"poll_timeout"`
+	Network         string        `mapstructure:"network"`
+	Brokers         []string      `mapstructure:"brokers"`
+	Topic           string        `mapstructure:"topic"`
+	GroupID         string        `mapstructure:"group_id"`
+	PollTimeout     int           `mapstructure:"poll_timeout"`
+	DLQTopic        string        `mapstructure:"dlq_topic"`
+	LedgerRetention time.Duration `mapstructure:"ledger_retention"`
 }
 
 type Cache struct {
-	Capacity int `mapstructure:"capacity"`
-	Ttl      int `mapstructure:"ttl"`
+	Capacity int           `mapstructure:"capacity"`
+	TTL      time.Duration `mapstructure:"ttl"`
+}
+
+type Log struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+type APIKey struct {
+	Name   string   `mapstructure:"name"`
+	Hash   string   `mapstructure:"hash"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+type JWT struct {
+	JWKSFile string `mapstructure:"jwks_file"`
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+}
+
+type Auth struct {
+	Enabled bool     `mapstructure:"enabled"`
+	APIKeys []APIKey `mapstructure:"api_keys"`
+	JWT     JWT      `mapstructure:"jwt"`
 }
+
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type RouteRateLimit struct {
+	RPS   float64 `mapstructure:"rps"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type RateLimit struct {
+	Enabled       bool                      `mapstructure:"enabled"`
+	MaxConcurrent int                       `mapstructure:"max_concurrent"`
+	MaxStreams    int                       `mapstructure:"max_streams"`
+	MaxClients    int                       `mapstructure:"max_clients"`
+	Routes        map[string]RouteRateLimit `mapstructure:"routes"`
+}
+
+type Encryption struct {
+	KeyringFile string `mapstructure:"keyring_file"`
+}
+
+type GRPC struct {
+	Enabled    bool `mapstructure:"enabled"`
+	Port       int  `mapstructure:"port"`
+	Reflection bool `mapstructure:"reflection"`
+}
+
+type Outbox struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Topic         string        `mapstructure:"topic"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+	MaxBackoff    time.Duration `mapstructure:"max_backoff"`
+	WriteAttempts int           `mapstructure:"write_attempts"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	Retention     time.Duration `mapstructure:"retention"`
+}
+
+type StrictDecoding struct {
+	Enabled        bool `mapstructure:"enabled"`
+	MaxPayloadSize int  `mapstructure:"max_payload_size"`
+	MaxItems       int  `mapstructure:"max_items"`
+}
+
+type Schema struct {
+	RegistryFile string         `mapstructure:"registry_file"`
+	Strict       StrictDecoding `mapstructure:"strict"`
+}
+
 type Config struct {
-	Serv  Server   `mapstructure:"server"`
-	Db    Postgres `mapstructure:"postgres"`
-	Kafka `mapstructure:"kafka"`
-	Cache `mapstructure:"cache"`
+	Serv       Server   `mapstructure:"server"`
+	Db         Postgres `mapstructure:"postgres"`
+	Kafka      `mapstructure:"kafka"`
+	Cache      `mapstructure:"cache"`
+	Log        Log        `mapstructure:"log"`
+	Tracing    Tracing    `mapstructure:"tracing"`
+	Auth       Auth       `mapstructure:"auth"`
+	Encryption Encryption `mapstructure:"encryption"`
+	RateLimit  RateLimit  `mapstructure:"rate_limit"`
+	GRPC       GRPC       `mapstructure:"grpc"`
+	Outbox     Outbox     `mapstructure:"outbox"`
+	Schema     Schema     `mapstructure:"schema"`
 }
 
-func LoadConfig() (*Config, error) {
-	v := viper.New()
+// LoadConfig читает конфигурацию из файла path. Если path пуст, берется путь из переменной
+// ORDER_CONFIG, а без нее — ./config/config.yaml. Любой ключ можно переопределить переменной
+// окружения ORDER_<КЛЮЧ> (postgres.password — ORDER_POSTGRES_PASSWORD), а секрет — прочитать
+// из файла, указанного в ORDER_<КЛЮЧ>_FILE. Загруженная конфигурация проверяется Validate.
+func LoadConfig(path string) (*Config, error) {
+	path = configFile(path)
 
-	v.AddConfigPath(cfgPath)
+	v := viper.New()
+	v.SetConfigFile(path)
 	v.SetConfigType("yaml")
-	v.SetConfigName("config")
 
 	err := v.ReadInConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
 
+	if err := bindEnv(v, reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, err
+	}
+
 	var cfg Config
 
-	if err := v.Unmarshal(&cfg); err != nil {
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(decodeHook)); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config file: %w", err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
 	return &cfg, nil
 }
 
+// configFile возвращает путь к файлу конфигурации: path, $ORDER_CONFIG или путь по умолчанию.
+func configFile(path string) string {
+	if path == "" {
+		path = os.Getenv(envConfigPath)
+	}
+	if path == "" {
+		path = defaultCfgFile
+	}
+	return path
+}
+
+// GetDbConnString возвращает DSN базы. application_name и statement_timeout передаются
+// как параметры сессии и действуют для всех соединений пула.
 func GetDbConnString(cfg *Config) string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.Db.User, cfg.Db.Password, cfg.Db.Host, cfg.Db.Port, cfg.Db.Database, cfg.Db.SSLMode)
+	query := url.Values{"sslmode": {cfg.Db.SSLMode}}
+	if cfg.Db.ApplicationName != "" {
+		query.Set("application_name", cfg.Db.ApplicationName)
+	}
+	if cfg.Db.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt(cfg.Db.StatementTimeout.Milliseconds(), 10))
+	}
+
+	// Пароль из секрета может содержать символы, которые в URL нужно экранировать
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Db.User, cfg.Db.Password),
+		Host:     net.JoinHostPort(cfg.Db.Host, strconv.Itoa(cfg.Db.Port)),
+		Path:     "/" + cfg.Db.Database,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
 }
 
 func GetServerAddr(cfg *Config) string {
 	return fmt.Sprintf("%s:%d", cfg.Serv.Host, cfg.Serv.Port)
 }
+
+// GetGRPCAddr возвращает адрес gRPC сервера: тот же хост, что у HTTP, и порт из секции grpc.
+func GetGRPCAddr(cfg *Config) string {
+	return fmt.Sprintf("%s:%d", cfg.Serv.Host, cfg.GRPC.Port)
+}
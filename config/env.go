@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// envPrefix — префикс переменных окружения, переопределяющих ключи конфигурации.
+	envPrefix = "ORDER"
+	// envFileSuffix — суффикс переменной с путем к файлу, из которого читается значение ключа.
+	envFileSuffix = "_FILE"
+	// envConfigPath — переменная с путем к файлу конфигурации.
+	envConfigPath = envPrefix + "_CONFIG"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvName возвращает имя переменной окружения для ключа конфигурации, например
+// postgres.password — ORDER_POSTGRES_PASSWORD.
+func EnvName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// bindEnv привязывает к переменным окружения все ключи структуры t, включая отсутствующие
+// в файле конфигурации. Значения из файлов по ORDER_<КЛЮЧ>_FILE подставляются сразу.
+func bindEnv(v *viper.Viper, t reflect.Type, prefix string) error {
+	var errs []error
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		// Вложенные секции обходим рекурсивно; списки и словари задаются только целиком
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			if err := bindEnv(v, field.Type, key); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		name := EnvName(key)
+		if err := v.BindEnv(key, name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := readSecretFile(v, key, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readSecretFile подставляет в ключ содержимое файла из переменной name_FILE, если она задана.
+// Завершающий перевод строки отбрасывается: его оставляют echo и большинство редакторов.
+func readSecretFile(v *viper.Viper, key, name string) error {
+	path, ok := os.LookupEnv(name + envFileSuffix)
+	if !ok {
+		return nil
+	}
+	if _, set := os.LookupEnv(name); set {
+		return fmt.Errorf("%s: both %s and %s are set", key, name, name+envFileSuffix)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- путь задает оператор
+	if err != nil {
+		return fmt.Errorf("%s: failed to read %s: %w", key, name+envFileSuffix, err)
+	}
+	v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	return nil
+}
+
+// durationHook отклоняет числа без единиц измерения в полях-длительностях: раньше ttl задавался
+// числом, единица которого зависела от режима, и молча превращать 24 в 24ns нельзя.
+func durationHook(from, to reflect.Type, data any) (any, error) {
+	if to != durationType {
+		return data, nil
+	}
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil, fmt.Errorf("duration %v has no unit, use a string like \"24h\", \"15m\" or \"30s\"", data)
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу, каждую с ключом,
+// например "server.port: must be within 1..65535, got 0".
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+	port := func(key string, value int) {
+		check(value > 0 && value <= 65535, key, "must be within 1..65535, got %d", value)
+	}
+	positive := func(key string, value int) {
+		check(value > 0, key, "must be positive, got %d", value)
+	}
+
+	port("server.port", c.Serv.Port)
+	positive("server.shutdown_timeout", c.Serv.ShutdownTimeout)
+	positive("server.read_timeout", c.Serv.ReadTimeout)
+	positive("server.write_timeout", c.Serv.WriteTimeout)
+	positive("server.idle_timeout", c.Serv.IdleTimeout)
+
+	if c.GRPC.Enabled {
+		port("grpc.port", c.GRPC.Port)
+		check(c.GRPC.Port != c.Serv.Port, "grpc.port", "must differ from server.port %d", c.Serv.Port)
+	}
+
+	check(c.Db.Host != "", "postgres.host", "must not be empty")
+	port("postgres.port", c.Db.Port)
+	check(c.Db.Database != "", "postgres.database", "must not be empty")
+	check(c.Db.User != "", "postgres.user", "must not be empty")
+	check(c.Db.Repository == "" || c.Db.Repository == RepositorySQLX || c.Db.Repository == RepositoryPgxPool,
+		"postgres.repository", "must be %s or %s, got %q", RepositorySQLX, RepositoryPgxPool, c.Db.Repository)
+	check(c.Db.StatementTimeout >= 0, "postgres.statement_timeout", "must not be negative, got %s", c.Db.StatementTimeout)
+	check(c.Db.Pool.MaxOpenConns >= 0, "postgres.pool.max_open_conns", "must not be negative, got %d", c.Db.Pool.MaxOpenConns)
+	check(c.Db.Pool.MaxIdleConns >= 0, "postgres.pool.max_idle_conns", "must not be negative, got %d", c.Db.Pool.MaxIdleConns)
+	check(c.Db.Pool.MinIdleConns >= 0, "postgres.pool.min_idle_conns", "must not be negative, got %d", c.Db.Pool.MinIdleConns)
+	if c.Db.Pool.MaxOpenConns > 0 {
+		check(c.Db.Pool.MaxIdleConns <= c.Db.Pool.MaxOpenConns, "postgres.pool.max_idle_conns",
+			"must not exceed max_open_conns %d, got %d", c.Db.Pool.MaxOpenConns, c.Db.Pool.MaxIdleConns)
+		check(c.Db.Pool.MinIdleConns <= c.Db.Pool.MaxOpenConns, "postgres.pool.min_idle_conns",
+			"must not exceed max_open_conns %d, got %d", c.Db.Pool.MaxOpenConns, c.Db.Pool.MinIdleConns)
+	}
+	check(c.Db.Pool.ConnMaxLifetime >= 0, "postgres.pool.conn_max_lifetime", "must not be negative, got %s", c.Db.Pool.ConnMaxLifetime)
+	check(c.Db.Pool.ConnMaxIdleTime >= 0, "postgres.pool.conn_max_idle_time", "must not be negative, got %s", c.Db.Pool.ConnMaxIdleTime)
+
+	check(len(c.Brokers) > 0, "kafka.brokers", "must list at least one broker")
+	for i, broker := range c.Brokers {
+		check(strings.TrimSpace(broker) != "", fmt.Sprintf("kafka.brokers[%d]", i), "must not be empty")
+	}
+	check(c.Topic != "", "kafka.topic", "must not be empty")
+	check(c.GroupID != "", "kafka.group_id", "must not be empty")
+	positive("kafka.poll_timeout", c.PollTimeout)
+	check(c.LedgerRetention > 0, "kafka.ledger_retention", "must be positive, got %s", c.LedgerRetention)
+
+	positive("cache.capacity", c.Capacity)
+	check(c.TTL > 0, "cache.ttl", "must be positive, got %s", c.TTL)
+
+	if c.Outbox.Enabled {
+		check(c.Outbox.Topic != "", "outbox.topic", "must not be empty")
+		positive("outbox.batch_size", c.Outbox.BatchSize)
+		check(c.Outbox.PollInterval > 0, "outbox.poll_interval", "must be positive, got %s", c.Outbox.PollInterval)
+		check(c.Outbox.MaxBackoff >= c.Outbox.PollInterval, "outbox.max_backoff",
+			"must not be less than poll_interval %s, got %s", c.Outbox.PollInterval, c.Outbox.MaxBackoff)
+		positive("outbox.write_attempts", c.Outbox.WriteAttempts)
+		positive("outbox.max_attempts", c.Outbox.MaxAttempts)
+		check(c.Outbox.Retention > 0, "outbox.retention", "must be positive, got %s", c.Outbox.Retention)
+	}
+
+	check(c.Schema.Strict.MaxPayloadSize >= 0, "schema.strict.max_payload_size", "must not be negative, got %d", c.Schema.Strict.MaxPayloadSize)
+	check(c.Schema.Strict.MaxItems >= 0, "schema.strict.max_items", "must not be negative, got %d", c.Schema.Strict.MaxItems)
+
+	if c.RateLimit.Enabled {
+		check(c.RateLimit.MaxConcurrent >= 0, "rate_limit.max_concurrent", "must not be negative, got %d", c.RateLimit.MaxConcurrent)
+		check(c.RateLimit.MaxStreams >= 0, "rate_limit.max_streams", "must not be negative, got %d", c.RateLimit.MaxStreams)
+		positive("rate_limit.max_clients", c.RateLimit.MaxClients)
+		for _, route := range slices.Sorted(maps.Keys(c.RateLimit.Routes)) {
+			limit := c.RateLimit.Routes[route]
+			check(limit.RPS >= 0, "rate_limit.routes."+route+".rps", "must not be negative, got %v", limit.RPS)
+			check(limit.Burst >= 0, "rate_limit.routes."+route+".burst", "must not be negative, got %d", limit.Burst)
+		}
+	}
+
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be within 0..1, got %v", c.Tracing.SampleRatio)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay — пауза после изменения файла перед перечитыванием: редакторы сохраняют
+// файл несколькими операциями.
+const reloadDelay = 100 * time.Millisecond
+
+// Источники перечитывания конфигурации в журнале изменений.
+const (
+	ReloadSourceFile   = "file"
+	ReloadSourceSignal = "SIGHUP"
+)
+
+// reloadableKey — ключ, который применяется без перезапуска сервиса, и перенос его значения.
+type reloadableKey struct {
+	key  string
+	copy func(dst, src *Config)
+}
+
+// reloadable — ключи, изменения которых Watcher применяет. Изменения остальных отклоняются.
+var reloadable = []reloadableKey{
+	{"log.level", func(dst, src *Config) { dst.Log.Level = src.Log.Level }},
+	{"rate_limit.enabled", func(dst, src *Config) { dst.RateLimit.Enabled = src.RateLimit.Enabled }},
+	{"rate_limit.routes", func(dst, src *Config) { dst.RateLimit.Routes = src.RateLimit.Routes }},
+	{"cache.ttl", func(dst, src *Config) { dst.Cache.TTL = src.Cache.TTL }},
+	{"schema.strict", func(dst, src *Config) { dst.Schema.Strict = src.Schema.Strict }},
+}
+
+// errChangesRejected — часть изменений не применена: подписчик вернул ошибку.
+var errChangesRejected = errors.New("config changes rejected by subscribers")
+
+type subscriber struct {
+	name  string
+	keys  []string
+	apply func(cfg *Config) error
+}
+
+// Watcher перечитывает файл конфигурации при его изменении и по сигналу SIGHUP.
+// Изменения ключей из reloadable передаются подписчикам, изменения остальных ключей
+// отклоняются до перезапуска. Каждое примененное изменение записывается в журнал
+// с прежним и новым значением.
+type Watcher struct {
+	path        string
+	mu          sync.Mutex
+	current     *Config
+	subscribers []subscriber
+	log         *slog.Logger
+}
+
+// NewWatcher создает Watcher для файла path (пустой path выбирается так же, как в LoadConfig)
+// и уже загруженной из него конфигурации cfg.
+func NewWatcher(path string, cfg *Config, log *slog.Logger) *Watcher {
+	return &Watcher{path: filepath.Clean(configFile(path)), current: cfg, log: log}
+}
+
+// Subscribe регистрирует apply для изменений ключей keys и вложенных в них, например
+// rate_limit.routes. apply получает конфигурацию со всеми примененными изменениями
+// и вызывается последовательно с другими подписчиками. Если apply вернул ошибку,
+// изменения его ключей не применяются.
+func (w *Watcher) Subscribe(name string, keys []string, apply func(cfg *Config) error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, subscriber{name: name, keys: keys, apply: apply})
+}
+
+// Current возвращает действующую конфигурацию. Ее нельзя изменять.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Run перечитывает конфигурацию, пока не отменен ctx. Watcher следит за каталогом файла,
+// а не за самим файлом: редакторы и Kubernetes при сохранении заменяют файл новым.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	defer fsWatcher.Close() //nolint:errcheck
+	if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("failed to watch config %s: %w", w.path, err)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	debounce := time.NewTimer(reloadDelay)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	w.log.Info("Watching config for changes", slog.String("file", w.path))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			_ = w.Reload(ReloadSourceSignal)
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			if w.affects(event) {
+				debounce.Reset(reloadDelay)
+			}
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.log.Warn("Config watcher error", slog.Any("error", err))
+		case <-debounce.C:
+			_ = w.Reload(ReloadSourceFile)
+		}
+	}
+}
+
+// affects сообщает, меняет ли событие файл конфигурации. ..data — символическая ссылка,
+// которую Kubernetes переключает при обновлении ConfigMap.
+func (w *Watcher) affects(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	return filepath.Clean(event.Name) == w.path || filepath.Base(event.Name) == "..data"
+}
+
+// Reload перечитывает и проверяет конфигурацию и применяет изменения. Если новая конфигурация
+// не загружается или не проходит проверку, действующая остается без изменений.
+// source попадает в журнал изменений.
+func (w *Watcher) Reload(source string) error {
+	next, err := LoadConfig(w.path)
+	if err != nil {
+		w.log.Error("Config reload failed, keeping current config",
+			slog.String("source", source), slog.Any("error", err))
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	before, after := flattenConfig(w.current), flattenConfig(next)
+	// Ключи маршрутов rate_limit.routes могут появиться или исчезнуть
+	keys := maps.Clone(before)
+	maps.Copy(keys, after)
+
+	updated := *w.current
+	// changed — измененные ключи по индексу в reloadable
+	changed := map[int][]string{}
+	var rejected []string
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		if before[key] == after[key] {
+			continue
+		}
+		i := slices.IndexFunc(reloadable, func(entry reloadableKey) bool { return hasKey(entry.key, key) })
+		if i < 0 {
+			rejected = append(rejected, key)
+			continue
+		}
+		changed[i] = append(changed[i], key)
+	}
+
+	if len(rejected) > 0 {
+		// Значения не пишем: среди них могут быть секреты
+		w.log.Warn("Config changes require restart and are ignored",
+			slog.String("source", source), slog.Any("keys", rejected))
+	}
+	if len(changed) == 0 {
+		if len(rejected) == 0 {
+			w.log.Debug("Config unchanged", slog.String("source", source))
+		}
+		return nil
+	}
+
+	for i := range changed {
+		reloadable[i].copy(&updated, next)
+	}
+
+	failed := map[int]bool{}
+	for _, sub := range w.subscribers {
+		var entries []int
+		for i := range changed {
+			if slices.ContainsFunc(sub.keys, func(key string) bool { return overlaps(key, reloadable[i].key) }) {
+				entries = append(entries, i)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if err := sub.apply(&updated); err != nil {
+			w.log.Error("Config change rejected by subscriber",
+				slog.String("source", source), slog.String("subscriber", sub.name), slog.Any("error", err))
+			for _, i := range entries {
+				failed[i] = true
+			}
+		}
+	}
+
+	for _, i := range slices.Sorted(maps.Keys(changed)) {
+		if failed[i] {
+			reloadable[i].copy(&updated, w.current)
+			continue
+		}
+		for _, key := range changed[i] {
+			w.log.Info("Config change applied", slog.String("source", source),
+				slog.String("key", key), slog.String("old", before[key]), slog.String("new", after[key]))
+		}
+	}
+	w.current = &updated
+
+	if len(failed) > 0 {
+		return errChangesRejected
+	}
+	return nil
+}
+
+// hasKey сообщает, что key совпадает с prefix или вложен в него.
+func hasKey(prefix, key string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+".")
+}
+
+// overlaps сообщает, что один из ключей вложен в другой.
+func overlaps(a, b string) bool {
+	return hasKey(a, b) || hasKey(b, a)
+}
+
+// flattenConfig раскладывает конфигурацию в значения ключей вида rate_limit.routes.order.rps.
+func flattenConfig(cfg *Config) map[string]string {
+	values := map[string]string{}
+	flatten(reflect.ValueOf(*cfg), "", values)
+	return values
+}
+
+func flatten(v reflect.Value, key string, values map[string]string) {
+	join := func(name string) string {
+		if key == "" {
+			return name
+		}
+		return key + "." + name
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != durationType:
+		for i := range v.NumField() {
+			tag := v.Type().Field(i).Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			flatten(v.Field(i), join(tag), values)
+		}
+	case v.Kind() == reflect.Map && v.Type().Elem().Kind() == reflect.Struct:
+		for _, name := range v.MapKeys() {
+			flatten(v.MapIndex(name), join(fmt.Sprint(name.Interface())), values)
+		}
+	default:
+		values[key] = fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,174 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"order_service/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer — журнал, который можно читать, пока в него пишет Watcher.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newWatcher создает Watcher для копии cfgFile и возвращает путь к копии и журнал Watcher.
+func newWatcher(t *testing.T) (*config.Watcher, string, *syncBuffer) {
+	path := writeConfig(t, `host: "app"`, `host: "app"`)
+	cfg, err := config.LoadConfig(path)
+	require.NoError(t, err)
+
+	logs := &syncBuffer{}
+	return config.NewWatcher(path, cfg, slog.New(slog.NewTextHandler(logs, nil))), path, logs
+}
+
+// editConfig заменяет old на new в файле конфигурации path.
+func editConfig(t *testing.T, path, old, new string) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), old)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0o600))
+}
+
+func TestWatcherReload(t *testing.T) {
+	t.Run("reloadable_changes_applied", func(t *testing.T) {
+		watcher, path, logs := newWatcher(t)
+		var ttl time.Duration
+		var routes map[string]config.RouteRateLimit
+		watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+			ttl = cfg.TTL
+			return nil
+		})
+		watcher.Subscribe("rate_limit", []string{"rate_limit"}, func(cfg *config.Config) error {
+			routes = cfg.RateLimit.Routes
+			return nil
+		})
+
+		editConfig(t, path, `ttl: "24h"`, `ttl: "1h"`)
+		editConfig(t, path, "rps: 20", "rps: 5")
+		require.NoError(t, watcher.Reload("test"))
+
+		require.Equal(t, time.Hour, ttl)
+		require.Equal(t, 5.0, routes["order"].RPS)
+		require.Equal(t, time.Hour, watcher.Current().TTL)
+		require.Contains(t, logs.String(), `msg="Config change applied" source=test key=cache.ttl old=24h0m0s new=1h0m0s`)
+		require.Contains(t, logs.String(), `key=rate_limit.routes.order.rps old=20 new=5`)
+	})
+
+	t.Run("restart_required_changes_ignored", func(t *testing.T) {
+		watcher, path, logs := newWatcher(t)
+		watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+			t.Fatal("unexpected apply")
+			return nil
+		})
+
+		editConfig(t, path, "port: 8080", "port: 9090")
+		editConfig(t, path, `password: "password"`, `password: "new-secret"`)
+		require.NoError(t, watcher.Reload("test"))
+
+		require.Equal(t, 8080, watcher.Current().Serv.Port)
+		require.Contains(t, logs.String(), `keys="[postgres.password server.port]"`)
+		require.NotContains(t, logs.String(), "new-secret")
+	})
+
+	t.Run("invalid_config_kept", func(t *testing.T) {
+		watcher, path, _ := newWatcher(t)
+		current := watcher.Current()
+
+		editConfig(t, path, `ttl: "24h"`, `ttl: "0s"`)
+		require.ErrorContains(t, watcher.Reload("test"), "cache.ttl: must be positive")
+		require.Same(t, current, watcher.Current())
+	})
+
+	t.Run("subscriber_error_reverts_change", func(t *testing.T) {
+		watcher, path, logs := newWatcher(t)
+		watcher.Subscribe("log", []string{"log.level"}, func(cfg *config.Config) error {
+			return errors.New("unknown log level")
+		})
+		var ttl time.Duration
+		watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+			ttl = cfg.TTL
+			return nil
+		})
+
+		editConfig(t, path, `level: ""`, `level: "verbose"`)
+		editConfig(t, path, `ttl: "24h"`, `ttl: "1h"`)
+		require.Error(t, watcher.Reload("test"))
+
+		require.Empty(t, watcher.Current().Log.Level)
+		require.Equal(t, time.Hour, ttl)
+		require.Equal(t, time.Hour, watcher.Current().TTL)
+		require.Contains(t, logs.String(), "subscriber=log")
+		require.NotContains(t, logs.String(), "key=log.level")
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		watcher, _, _ := newWatcher(t)
+		watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+			t.Fatal("unexpected apply")
+			return nil
+		})
+		require.NoError(t, watcher.Reload("test"))
+	})
+}
+
+func TestWatcherRun(t *testing.T) {
+	watcher, path, logs := newWatcher(t)
+	ttls := make(chan time.Duration, 1)
+	watcher.Subscribe("cache", []string{"cache.ttl"}, func(cfg *config.Config) error {
+		ttls <- cfg.TTL
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "Watching config for changes")
+	}, time.Second, 10*time.Millisecond)
+
+	receive := func() time.Duration {
+		select {
+		case ttl := <-ttls:
+			return ttl
+		case <-time.After(3 * time.Second):
+			t.Fatal("config was not reloaded")
+			return 0
+		}
+	}
+
+	editConfig(t, path, `ttl: "24h"`, `ttl: "1h"`)
+	require.Equal(t, time.Hour, receive())
+
+	// Переменные окружения перечитываются вместе с файлом
+	t.Setenv("ORDER_CACHE_TTL", "2h")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Equal(t, 2*time.Hour, receive())
+	require.Contains(t, logs.String(), "source=SIGHUP key=cache.ttl")
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,42 @@
+package grpcapi
+
+import (
+	"log/slog"
+
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer собирает gRPC сервер с OrderService, health и, при reflection=true, reflection API.
+// Метрики снимаются до аутентификации, чтобы отказы тоже попадали в статистику.
+// Если authenticator равен nil, аутентификация выключена.
+func NewServer(
+	orders *OrderServer,
+	authenticator domain.Authenticator,
+	metrics domain.GRPCMetrics,
+	reflectionEnabled bool,
+	log *slog.Logger,
+) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryMetrics(metrics), UnaryAuth(authenticator, log)),
+		grpc.ChainStreamInterceptor(StreamMetrics(metrics), StreamAuth(authenticator, log)),
+	)
+
+	orderv1.RegisterOrderServiceServer(server, orders)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(orderv1.OrderService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	if reflectionEnabled {
+		reflection.Register(server)
+	}
+
+	return server
+}
@@ -0,0 +1,124 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataAPIKey — ключ метаданных со статическим API-ключом, аналог заголовка X-API-Key.
+const MetadataAPIKey = "x-api-key"
+
+// methodScopes — скоупы, которые требуют методы OrderService. Методы вне списка
+// (health, reflection) доступны без аутентификации.
+var methodScopes = map[string]string{
+	orderv1.OrderService_GetOrder_FullMethodName:    domain.ScopeOrdersRead,
+	orderv1.OrderService_ListOrders_FullMethodName:  domain.ScopeOrdersRead,
+	orderv1.OrderService_CreateOrder_FullMethodName: domain.ScopeOrdersWrite,
+	orderv1.OrderService_WatchOrders_FullMethodName: domain.ScopeOrdersRead,
+}
+
+// UnaryAuth аутентифицирует вызов по метаданным x-api-key или authorization: Bearer
+// и проверяет скоуп метода. Без учетных данных или с неверными возвращает Unauthenticated,
+// без нужного скоупа — PermissionDenied. Если authenticator равен nil, вызов пропускается.
+func UnaryAuth(authenticator domain.Authenticator, log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authenticator, info.FullMethod, log)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth — UnaryAuth для потоковых методов.
+func StreamAuth(authenticator domain.Authenticator, log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), authenticator, info.FullMethod, log)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryMetrics записывает количество и длительность вызовов с итоговым кодом.
+func UnaryMetrics(metrics domain.GRPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), start)
+		return resp, err
+	}
+}
+
+// StreamMetrics — UnaryMetrics для потоковых методов; длительность — все время жизни потока.
+func StreamMetrics(metrics domain.GRPCMetrics) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), start)
+		return err
+	}
+}
+
+func authorize(ctx context.Context, authenticator domain.Authenticator, method string, log *slog.Logger) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if authenticator == nil || !ok {
+		return ctx, nil
+	}
+
+	principal, err := authenticate(ctx, authenticator)
+	if err != nil {
+		log.WarnContext(ctx, "Authentication failed", slog.String("method", method), slog.Any("error", err))
+		return nil, status.Error(codes.Unauthenticated, domain.ErrUnauthenticated.Error())
+	}
+
+	if !principal.HasScope(scope) {
+		log.WarnContext(ctx, "Insufficient scope",
+			slog.String("method", method),
+			slog.String("subject", principal.Subject),
+			slog.String("scope", scope),
+		)
+		return nil, status.Errorf(codes.PermissionDenied, "%s: scope %s is required", domain.ErrForbidden, scope)
+	}
+
+	return domain.WithPrincipal(ctx, principal), nil
+}
+
+func authenticate(ctx context.Context, authenticator domain.Authenticator) (*domain.Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get(MetadataAPIKey); len(keys) > 0 && keys[0] != "" {
+		return authenticator.AuthenticateAPIKey(keys[0])
+	}
+
+	if values := md.Get("authorization"); len(values) > 0 {
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if ok && strings.EqualFold(scheme, "Bearer") && token != "" {
+			return authenticator.AuthenticateToken(strings.TrimSpace(token))
+		}
+	}
+
+	return nil, fmt.Errorf("no credentials: %w", domain.ErrUnauthenticated)
+}
+
+// contextStream подменяет контекст потока, чтобы обработчик видел аутентифицированного клиента.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
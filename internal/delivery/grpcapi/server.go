@@ -0,0 +1,202 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+	"order_service/internal/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Границы limit в ListOrders
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// OrderServer реализует order.v1.OrderService поверх domain.OrderService.
+type OrderServer struct {
+	orderv1.UnimplementedOrderServiceServer
+
+	service domain.OrderService
+	log     *slog.Logger
+}
+
+// NewOrderServer создает gRPC сервер заказов с внедренным сервисом заказов.
+func NewOrderServer(service domain.OrderService, log *slog.Logger) *OrderServer {
+	log.Debug("Initializing gRPC OrderServer")
+	return &OrderServer{
+		service: service,
+		log:     log,
+	}
+}
+
+func (s *OrderServer) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
+	ctx = logger.WithOrderUID(ctx, req.GetOrderUid())
+	if req.GetOrderUid() == "" {
+		return nil, status.Error(codes.InvalidArgument, domain.ErrOrderUIDRequired.Error())
+	}
+
+	order, err := s.service.GetOrder(ctx, req.GetOrderUid())
+	if err != nil {
+		return nil, s.toStatus(ctx, "Failed to get order", err)
+	}
+
+	if shouldRedact(ctx, req.GetRedact()) {
+		order = order.Redacted()
+	}
+	return &orderv1.GetOrderResponse{Order: protoconv.OrderToProto(order)}, nil
+}
+
+// ListOrders возвращает последние заказы или, если задан email, заказы получателя.
+// Поиск по email раскрывает связь адреса с заказами, поэтому требует скоуп orders:pii.
+func (s *OrderServer) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest) (*orderv1.ListOrdersResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit < 1 || limit > maxListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxListLimit)
+	}
+
+	var (
+		orders []*domain.Order
+		err    error
+	)
+	if req.GetEmail() != "" {
+		if principal, ok := domain.PrincipalFromContext(ctx); ok && !principal.HasScope(domain.ScopePIIRead) {
+			return nil, status.Errorf(codes.PermissionDenied, "scope %s is required to search by email", domain.ScopePIIRead)
+		}
+		orders, err = s.service.FindOrdersByEmail(ctx, req.GetEmail())
+	} else {
+		orders, err = s.service.ListOrders(ctx, limit)
+	}
+	if err != nil && !errors.Is(err, domain.ErrOrdersNotFound) {
+		return nil, s.toStatus(ctx, "Failed to list orders", err)
+	}
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+
+	redact := shouldRedact(ctx, req.GetRedact())
+	resp := &orderv1.ListOrdersResponse{Orders: make([]*orderv1.Order, 0, len(orders))}
+	for _, order := range orders {
+		if redact {
+			order = order.Redacted()
+		}
+		resp.Orders = append(resp.Orders, protoconv.OrderToProto(order))
+	}
+	return resp, nil
+}
+
+func (s *OrderServer) CreateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
+	if req.GetOrder() == nil {
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+
+	order := protoconv.OrderFromProto(req.GetOrder())
+	ctx = logger.WithOrderUID(ctx, order.OrderUID)
+
+	if err := domain.ValidateOrder(order); err != nil {
+		s.log.WarnContext(ctx, "Invalid order", slog.Any("error", err), slog.Any("order", order))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := s.service.SaveOrder(ctx, order); err != nil {
+		return nil, s.toStatus(ctx, "Failed to save order", err)
+	}
+
+	return &orderv1.CreateOrderResponse{OrderUid: order.OrderUID}, nil
+}
+
+// WatchOrders отправляет клиенту заказы, сохраненные после подписки и подходящие под фильтры.
+// Если клиент не успевает читать, сервис отключает подписку и поток завершается с ResourceExhausted.
+func (s *OrderServer) WatchOrders(req *orderv1.WatchOrdersRequest, stream orderv1.OrderService_WatchOrdersServer) error {
+	ctx := stream.Context()
+	redact := shouldRedact(ctx, req.GetRedact())
+
+	events, err := s.service.WatchOrderEvents(ctx, 0)
+	if err != nil {
+		return s.toStatus(ctx, "Failed to watch orders", err)
+	}
+
+	for event := range events {
+		order := event.Order
+		if !matchesWatch(req, order) {
+			continue
+		}
+		if redact {
+			order = order.Redacted()
+		}
+		if err := stream.Send(&orderv1.WatchOrdersResponse{Order: protoconv.OrderToProto(order)}); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	s.log.WarnContext(ctx, "Order watcher dropped: client is too slow")
+	return status.Error(codes.ResourceExhausted, "subscriber is too slow, resubscribe")
+}
+
+func matchesWatch(req *orderv1.WatchOrdersRequest, order *domain.Order) bool {
+	if req.GetCustomerId() != "" && req.GetCustomerId() != order.CustomerID {
+		return false
+	}
+	if req.GetDeliveryService() != "" && req.GetDeliveryService() != order.DeliveryService {
+		return false
+	}
+	return true
+}
+
+// shouldRedact повторяет правило REST API: клиенту без скоупа orders:pii данные маскируются
+// всегда, остальные могут запросить маскирование. Без аутентификации решает только запрос.
+func shouldRedact(ctx context.Context, requested bool) bool {
+	if principal, ok := domain.PrincipalFromContext(ctx); ok && !principal.HasScope(domain.ScopePIIRead) {
+		return true
+	}
+	return requested
+}
+
+// statusCodes сопоставляет классы ошибок domain с кодами gRPC.
+var statusCodes = map[domain.ErrorCode]codes.Code{
+	domain.CodeNotFound:        codes.NotFound,
+	domain.CodeValidation:      codes.InvalidArgument,
+	domain.CodeConflict:        codes.AlreadyExists,
+	domain.CodeUnavailable:     codes.Unavailable,
+	domain.CodeTimeout:         codes.DeadlineExceeded,
+	domain.CodeCanceled:        codes.Canceled,
+	domain.CodeUnauthenticated: codes.Unauthenticated,
+	domain.CodeForbidden:       codes.PermissionDenied,
+	domain.CodeRateLimited:     codes.ResourceExhausted,
+	domain.CodeOverloaded:      codes.Unavailable,
+	domain.CodeNotAcceptable:   codes.InvalidArgument,
+	domain.CodeInternal:        codes.Internal,
+}
+
+// toStatus переводит ошибку сервиса в gRPC статус по domain.CodeOf. Внутренние ошибки
+// логируются и отдаются клиенту без подробностей, как и в REST API.
+func (s *OrderServer) toStatus(ctx context.Context, msg string, err error) error {
+	code := domain.CodeOf(err)
+	switch code {
+	case domain.CodeInternal:
+		s.log.ErrorContext(ctx, msg, slog.Any("error", err))
+		return status.Error(codes.Internal, domain.ErrInternalServer.Error())
+	case domain.CodeUnavailable:
+		s.log.ErrorContext(ctx, msg, slog.Any("error", err))
+		return status.Error(codes.Unavailable, domain.ErrUnavailable.Error())
+	case domain.CodeTimeout:
+		return status.Error(codes.DeadlineExceeded, domain.ErrTimeout.Error())
+	case domain.CodeConflict:
+		return status.Error(codes.AlreadyExists, domain.ErrConflict.Error())
+	}
+	return status.Error(statusCodes[code], err.Error())
+}
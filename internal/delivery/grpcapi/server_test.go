@@ -0,0 +1,285 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"order_service/internal/delivery/grpcapi"
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var validOrder = &domain.Order{
+	OrderUID:        "b563feb7b2b84b6test",
+	TrackNumber:     "WBILMTESTTRACK",
+	Entry:           "WBIL",
+	Locale:          "en",
+	CustomerID:      "test",
+	DeliveryService: "meest",
+	ShardKey:        "9",
+	SmID:            99,
+	DateCreated:     "2024-01-07T06:22:08Z",
+	OofShard:        "1",
+	Delivery: domain.Delivery{
+		Name:    "Test Testov",
+		Phone:   "+9720000000",
+		Zip:     "2639809",
+		City:    "Kiryat Mozkin",
+		Address: "Ploshad Mira 15",
+		Region:  "Kraiot",
+		Email:   "test@gmail.com",
+	},
+	Payment: domain.Payment{
+		Transaction:  "b563feb7b2b84b6test",
+		Currency:     "USD",
+		Provider:     "wbpay",
+		Amount:       1817,
+		PaymentDt:    1234567890,
+		Bank:         "alpha",
+		DeliveryCost: 1500,
+		GoodsTotal:   317,
+	},
+	Items: []domain.Item{
+		{
+			ChrtID:      9934930,
+			TrackNumber: "WBILMTESTTRACK",
+			Price:       453,
+			Rid:         "ab4219087a764ae0btest",
+			Name:        "Mascaras",
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			NmID:        2389212,
+			Brand:       "Vivienne Sabo",
+			Status:      202,
+		},
+	},
+}
+
+// startServer поднимает gRPC сервер на bufconn и возвращает подключенное к нему соединение.
+func startServer(t *testing.T, service domain.OrderService, authenticator domain.Authenticator, metrics domain.GRPCMetrics) *grpc.ClientConn {
+	t.Helper()
+
+	if metrics == nil {
+		mockMetrics := mock.NewMockGRPCMetrics(gomock.NewController(t))
+		mockMetrics.EXPECT().ObserveRPC(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		metrics = mockMetrics
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(grpcapi.NewOrderServer(service, logger.Discard()), authenticator, metrics, true, logger.Discard())
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec
+
+	return conn
+}
+
+func TestGetOrder(t *testing.T) {
+	tbl := []struct {
+		name         string
+		orderUID     string
+		outputOrder  *domain.Order
+		outputErr    error
+		expectedCode codes.Code
+	}{
+		{name: "found", orderUID: validOrder.OrderUID, outputOrder: validOrder, expectedCode: codes.OK},
+		{name: "not_found", orderUID: "missing", outputErr: domain.ErrOrderNotFound, expectedCode: codes.NotFound},
+		{name: "internal", orderUID: "broken", outputErr: errors.New("connection refused"), expectedCode: codes.Internal},
+		{name: "unavailable", orderUID: "offline", outputErr: domain.ErrUnavailable, expectedCode: codes.Unavailable},
+		{name: "conflict", orderUID: "duplicate", outputErr: fmt.Errorf("%w: unique constraint \"orders_pkey\"", domain.ErrConflict), expectedCode: codes.AlreadyExists},
+		{name: "empty_uid", expectedCode: codes.InvalidArgument},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockOrderService := mock.NewMockOrderService(ctrl)
+			if testCase.orderUID != "" {
+				mockOrderService.EXPECT().GetOrder(gomock.Any(), testCase.orderUID).Return(testCase.outputOrder, testCase.outputErr)
+			}
+
+			client := orderv1.NewOrderServiceClient(startServer(t, mockOrderService, nil, nil))
+			resp, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: testCase.orderUID})
+
+			require.Equal(t, testCase.expectedCode, status.Code(err))
+			if testCase.expectedCode == codes.OK {
+				require.Equal(t, validOrder, protoconv.OrderFromProto(resp.GetOrder()))
+			}
+			if testCase.expectedCode == codes.Internal {
+				require.NotContains(t, err.Error(), "connection refused", "internal details must not leak")
+			}
+			if testCase.expectedCode == codes.AlreadyExists {
+				require.NotContains(t, err.Error(), "orders_pkey", "constraint names must not leak")
+			}
+		})
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	client := orderv1.NewOrderServiceClient(startServer(t, mockOrderService, nil, nil))
+	ctx := context.Background()
+
+	mockOrderService.EXPECT().ListOrders(gomock.Any(), 100).Return([]*domain.Order{validOrder}, nil)
+	resp, err := client.ListOrders(ctx, &orderv1.ListOrdersRequest{Redact: true})
+	require.NoError(t, err)
+	require.Len(t, resp.GetOrders(), 1)
+	require.Equal(t, "+972*****00", resp.GetOrders()[0].GetDelivery().GetPhone())
+
+	mockOrderService.EXPECT().ListOrders(gomock.Any(), 5).Return(nil, domain.ErrOrdersNotFound)
+	resp, err = client.ListOrders(ctx, &orderv1.ListOrdersRequest{Limit: 5})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetOrders())
+
+	mockOrderService.EXPECT().FindOrdersByEmail(gomock.Any(), validOrder.Delivery.Email).Return([]*domain.Order{validOrder, validOrder}, nil)
+	resp, err = client.ListOrders(ctx, &orderv1.ListOrdersRequest{Limit: 1, Email: validOrder.Delivery.Email})
+	require.NoError(t, err)
+	require.Len(t, resp.GetOrders(), 1)
+	require.Equal(t, validOrder.Delivery.Email, resp.GetOrders()[0].GetDelivery().GetEmail())
+
+	_, err = client.ListOrders(ctx, &orderv1.ListOrdersRequest{Limit: 1001})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	client := orderv1.NewOrderServiceClient(startServer(t, mockOrderService, nil, nil))
+	ctx := context.Background()
+
+	mockOrderService.EXPECT().SaveOrder(gomock.Any(), validOrder).Return(true, nil)
+	resp, err := client.CreateOrder(ctx, &orderv1.CreateOrderRequest{Order: protoconv.OrderToProto(validOrder)})
+	require.NoError(t, err)
+	require.Equal(t, validOrder.OrderUID, resp.GetOrderUid())
+
+	invalid := protoconv.OrderToProto(validOrder)
+	invalid.Items = nil
+	_, err = client.CreateOrder(ctx, &orderv1.CreateOrderRequest{Order: invalid})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateOrder(ctx, &orderv1.CreateOrderRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	client := orderv1.NewOrderServiceClient(startServer(t, mockOrderService, nil, nil))
+
+	events := make(chan domain.OrderEvent, 3)
+	mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).Return(events, nil)
+
+	other := *validOrder
+	other.OrderUID = "other"
+	other.CustomerID = "someone-else"
+	events <- domain.OrderEvent{ID: 1, Type: domain.EventOrderCreated, Order: &other}
+	events <- domain.OrderEvent{ID: 2, Type: domain.EventOrderCreated, Order: validOrder}
+	close(events) // подписка сброшена сервисом, как при медленном клиенте
+
+	stream, err := client.WatchOrders(context.Background(), &orderv1.WatchOrdersRequest{CustomerId: validOrder.CustomerID})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, validOrder.OrderUID, resp.GetOrder().GetOrderUid())
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestAuthInterceptors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	mockAuthenticator := mock.NewMockAuthenticator(ctrl)
+	mockMetrics := mock.NewMockGRPCMetrics(ctrl)
+	mockMetrics.EXPECT().ObserveRPC(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	conn := startServer(t, mockOrderService, mockAuthenticator, mockMetrics)
+	client := orderv1.NewOrderServiceClient(conn)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), grpcapi.MetadataAPIKey, key)
+	}
+
+	mockAuthenticator.EXPECT().AuthenticateAPIKey("reader").
+		Return(&domain.Principal{Subject: "reader", Scopes: []string{domain.ScopeOrdersRead}}, nil).AnyTimes()
+	mockAuthenticator.EXPECT().AuthenticateToken("admin-token").
+		Return(&domain.Principal{Subject: "admin", Scopes: []string{domain.ScopeAdmin}}, nil).AnyTimes()
+
+	t.Run("no_credentials", func(t *testing.T) {
+		_, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: validOrder.OrderUID})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		stream, err := client.WatchOrders(context.Background(), &orderv1.WatchOrdersRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("missing_scope", func(t *testing.T) {
+		_, err := client.CreateOrder(withKey("reader"), &orderv1.CreateOrderRequest{Order: protoconv.OrderToProto(validOrder)})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("reader_gets_redacted_order", func(t *testing.T) {
+		mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil)
+		resp, err := client.GetOrder(withKey("reader"), &orderv1.GetOrderRequest{OrderUid: validOrder.OrderUID})
+		require.NoError(t, err)
+		require.Equal(t, "t***@gmail.com", resp.GetOrder().GetDelivery().GetEmail())
+
+		_, err = client.ListOrders(withKey("reader"), &orderv1.ListOrdersRequest{Email: validOrder.Delivery.Email})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("bearer_admin", func(t *testing.T) {
+		mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil)
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer admin-token")
+		resp, err := client.GetOrder(ctx, &orderv1.GetOrderRequest{OrderUid: validOrder.OrderUID})
+		require.NoError(t, err)
+		require.Equal(t, validOrder.Delivery.Email, resp.GetOrder().GetDelivery().GetEmail())
+	})
+
+	t.Run("health_is_public", func(t *testing.T) {
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+			&healthpb.HealthCheckRequest{Service: orderv1.OrderService_ServiceDesc.ServiceName})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	mockMetrics := mock.NewMockGRPCMetrics(ctrl)
+
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), "missing").Return(nil, domain.ErrOrderNotFound)
+	mockMetrics.EXPECT().ObserveRPC(orderv1.OrderService_GetOrder_FullMethodName, codes.NotFound.String(), gomock.Any())
+
+	client := orderv1.NewOrderServiceClient(startServer(t, mockOrderService, nil, mockMetrics))
+	_, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderUid: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,123 @@
+// Package protoconv переводит заказы между domain и protobuf схемой order.v1.
+package protoconv
+
+import (
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+)
+
+// OrderToProto переводит domain.Order в сообщение order.v1.Order.
+func OrderToProto(o *domain.Order) *orderv1.Order {
+	items := make([]*orderv1.Item, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, &orderv1.Item{
+			ChrtId:      int64(item.ChrtID),
+			TrackNumber: item.TrackNumber,
+			Price:       int64(item.Price),
+			Rid:         item.Rid,
+			Name:        item.Name,
+			Sale:        int64(item.Sale),
+			Size:        item.Size,
+			TotalPrice:  int64(item.TotalPrice),
+			NmId:        int64(item.NmID),
+			Brand:       item.Brand,
+			Status:      int64(item.Status),
+			OrderUid:    item.OrderUID,
+		})
+	}
+
+	return &orderv1.Order{
+		OrderUid:    o.OrderUID,
+		TrackNumber: o.TrackNumber,
+		Entry:       o.Entry,
+		Delivery: &orderv1.Delivery{
+			Name:    o.Name,
+			Phone:   o.Phone,
+			Zip:     o.Zip,
+			City:    o.City,
+			Address: o.Address,
+			Region:  o.Region,
+			Email:   o.Email,
+		},
+		Payment: &orderv1.Payment{
+			Transaction:  o.Transaction,
+			RequestId:    o.RequestID,
+			Currency:     o.Currency,
+			Provider:     o.Provider,
+			Amount:       int64(o.Amount),
+			PaymentDt:    int64(o.PaymentDt),
+			Bank:         o.Bank,
+			DeliveryCost: int64(o.DeliveryCost),
+			GoodsTotal:   int64(o.GoodsTotal),
+			CustomFee:    int64(o.CustomFee),
+		},
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerId:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.ShardKey,
+		SmId:              int64(o.SmID),
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+	}
+}
+
+// OrderFromProto переводит сообщение order.v1.Order в domain.Order.
+// Отсутствующие delivery и payment дают нулевые значения.
+func OrderFromProto(o *orderv1.Order) *domain.Order {
+	items := make([]domain.Item, 0, len(o.GetItems()))
+	for _, item := range o.GetItems() {
+		items = append(items, domain.Item{
+			OrderUID:    item.GetOrderUid(),
+			ChrtID:      int(item.GetChrtId()),
+			TrackNumber: item.GetTrackNumber(),
+			Price:       int(item.GetPrice()),
+			Rid:         item.GetRid(),
+			Name:        item.GetName(),
+			Sale:        int(item.GetSale()),
+			Size:        item.GetSize(),
+			TotalPrice:  int(item.GetTotalPrice()),
+			NmID:        int(item.GetNmId()),
+			Brand:       item.GetBrand(),
+			Status:      int(item.GetStatus()),
+		})
+	}
+
+	delivery, payment := o.GetDelivery(), o.GetPayment()
+	return &domain.Order{
+		OrderUID:    o.GetOrderUid(),
+		TrackNumber: o.GetTrackNumber(),
+		Entry:       o.GetEntry(),
+		Delivery: domain.Delivery{
+			Name:    delivery.GetName(),
+			Phone:   delivery.GetPhone(),
+			Zip:     delivery.GetZip(),
+			City:    delivery.GetCity(),
+			Address: delivery.GetAddress(),
+			Region:  delivery.GetRegion(),
+			Email:   delivery.GetEmail(),
+		},
+		Payment: domain.Payment{
+			Transaction:  payment.GetTransaction(),
+			RequestID:    payment.GetRequestId(),
+			Currency:     payment.GetCurrency(),
+			Provider:     payment.GetProvider(),
+			Amount:       int(payment.GetAmount()),
+			PaymentDt:    int(payment.GetPaymentDt()),
+			Bank:         payment.GetBank(),
+			DeliveryCost: int(payment.GetDeliveryCost()),
+			GoodsTotal:   int(payment.GetGoodsTotal()),
+			CustomFee:    int(payment.GetCustomFee()),
+		},
+		Items:             items,
+		Locale:            o.GetLocale(),
+		InternalSignature: o.GetInternalSignature(),
+		CustomerID:        o.GetCustomerId(),
+		DeliveryService:   o.GetDeliveryService(),
+		ShardKey:          o.GetShardkey(),
+		SmID:              int(o.GetSmId()),
+		DateCreated:       o.GetDateCreated(),
+		OofShard:          o.GetOofShard(),
+	}
+}
@@ -0,0 +1,55 @@
+package protoconv_test
+
+import (
+	"testing"
+
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOrderRoundTrip(t *testing.T) {
+	order := &domain.Order{
+		OrderUID:          "b563feb7b2b84b6test",
+		TrackNumber:       "WBILMTESTTRACK",
+		Entry:             "WBIL",
+		Locale:            "en",
+		InternalSignature: "sig",
+		CustomerID:        "test",
+		DeliveryService:   "meest",
+		ShardKey:          "9",
+		SmID:              99,
+		DateCreated:       "2024-01-07T06:22:08Z",
+		OofShard:          "1",
+		Delivery: domain.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", City: "Kiryat Mozkin",
+			Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com",
+		},
+		Payment: domain.Payment{
+			Transaction: "b563feb7b2b84b6test", RequestID: "req", Currency: "USD", Provider: "wbpay",
+			Amount: 1817, PaymentDt: 1637907727, Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317, CustomFee: 5,
+		},
+		Items: []domain.Item{
+			{
+				OrderUID: "b563feb7b2b84b6test", ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453,
+				Rid: "ab4219087a764ae0btest", Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317,
+				NmID: 2389212, Brand: "Vivienne Sabo", Status: 202,
+			},
+		},
+	}
+
+	data, err := proto.Marshal(protoconv.OrderToProto(order))
+	require.NoError(t, err)
+
+	var decoded orderv1.Order
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	require.Equal(t, order, protoconv.OrderFromProto(&decoded))
+}
+
+func TestOrderFromProtoEmpty(t *testing.T) {
+	order := protoconv.OrderFromProto(&orderv1.Order{OrderUid: "uid"})
+	require.Equal(t, &domain.Order{OrderUID: "uid", Items: []domain.Item{}}, order)
+}
@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+)
+
+type AdminHandler struct {
+	level *slog.LevelVar
+	log   *slog.Logger
+}
+
+// NewAdminHandler создает обработчик служебных эндпоинтов.
+func NewAdminHandler(level *slog.LevelVar, log *slog.Logger) *AdminHandler {
+	log.Debug("Initializing AdminHandler")
+	return &AdminHandler{
+		level: level,
+		log:   log,
+	}
+}
+
+// GetLogLevel возвращает HTTP обработчик, отдающий текущий уровень логирования.
+func (h *AdminHandler) GetLogLevel() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LogLevelResponse{Level: h.level.Level().String()}) //nolint:errcheck,gosec
+	}
+}
+
+// SetLogLevel возвращает HTTP обработчик, меняющий уровень логирования без перезапуска.
+func (h *AdminHandler) SetLogLevel() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req LogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeProblem(w, r, fmt.Errorf("%w: invalid request body", domain.ErrInvalidRequest))
+			return
+		}
+
+		level, err := logger.ParseLevel(req.Level)
+		if err != nil {
+			writeProblem(w, r, fmt.Errorf("%w: %w", domain.ErrInvalidRequest, err))
+			return
+		}
+
+		previous := h.level.Level()
+		h.level.Set(level)
+		h.log.InfoContext(r.Context(), "Log level changed",
+			slog.String("from", previous.String()),
+			slog.String("to", level.String()),
+		)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LogLevelResponse{Level: level.String()}) //nolint:errcheck,gosec
+	}
+}
@@ -0,0 +1,75 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevel(t *testing.T) {
+	level := new(slog.LevelVar)
+	adminHandler := rest.NewAdminHandler(level, logger.Discard())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/log-level", adminHandler.GetLogLevel())
+	mux.HandleFunc("PUT /admin/log-level", adminHandler.SetLogLevel())
+
+	tbl := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+		expectedLevel      slog.Level
+	}{
+		{
+			name:               "set_debug",
+			body:               `{"level":"debug"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedLevel:      slog.LevelDebug,
+		},
+		{
+			name:               "invalid_level",
+			body:               `{"level":"verbose"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedLevel:      slog.LevelDebug,
+		},
+		{
+			name:               "invalid_body",
+			body:               `level=error`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedLevel:      slog.LevelDebug,
+		},
+		{
+			name:               "set_error",
+			body:               `{"level":"ERROR"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedLevel:      slog.LevelError,
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(testCase.body))
+			respRec := httptest.NewRecorder()
+			mux.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			require.Equal(t, testCase.expectedLevel, level.Level())
+
+			req = httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+			respRec = httptest.NewRecorder()
+			mux.ServeHTTP(respRec, req)
+
+			var resp rest.LogLevelResponse
+			require.NoError(t, json.NewDecoder(respRec.Body).Decode(&resp))
+			require.Equal(t, testCase.expectedLevel.String(), resp.Level)
+		})
+	}
+}
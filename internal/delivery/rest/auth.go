@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"order_service/internal/domain"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// RequireScope возвращает middleware, который аутентифицирует запрос по заголовку
+// X-API-Key или Authorization: Bearer и проверяет наличие скоупа у клиента.
+// Без учетных данных или с неверными отвечает 401, без нужного скоупа — 403.
+// Если authenticator равен nil (аутентификация выключена), запрос пропускается как есть.
+func RequireScope(authenticator domain.Authenticator, scope string, log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		if authenticator == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticate(authenticator, r)
+			if err != nil {
+				log.WarnContext(r.Context(), "Authentication failed", slog.Any("error", err))
+				w.Header().Set("WWW-Authenticate", `Bearer realm="order_service"`)
+				writeProblem(w, r, domain.ErrUnauthenticated)
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				log.WarnContext(r.Context(), "Insufficient scope",
+					slog.String("subject", principal.Subject),
+					slog.String("scope", scope),
+				)
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, scope))
+				writeProblem(w, r, domain.ErrForbidden)
+				return
+			}
+
+			ctx := domain.WithPrincipal(r.Context(), principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func authenticate(authenticator domain.Authenticator, r *http.Request) (*domain.Principal, error) {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return authenticator.AuthenticateAPIKey(key)
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") && token != "" {
+		return authenticator.AuthenticateToken(strings.TrimSpace(token))
+	}
+
+	return nil, fmt.Errorf("no credentials: %w", domain.ErrUnauthenticated)
+}
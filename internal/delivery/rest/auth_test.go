@@ -0,0 +1,120 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequireScope(t *testing.T) {
+	tbl := []struct {
+		name               string
+		headers            map[string]string
+		setup              func(m *mock.MockAuthenticator)
+		expectedStatusCode int
+		expectedError      string
+	}{
+		{
+			name:               "no_credentials",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      domain.ErrUnauthenticated.Error(),
+		},
+		{
+			name:    "invalid_api_key",
+			headers: map[string]string{rest.HeaderAPIKey: "bad"},
+			setup: func(m *mock.MockAuthenticator) {
+				m.EXPECT().AuthenticateAPIKey("bad").Return(nil, domain.ErrUnauthenticated)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      domain.ErrUnauthenticated.Error(),
+		},
+		{
+			name:    "api_key_without_scope",
+			headers: map[string]string{rest.HeaderAPIKey: "writer"},
+			setup: func(m *mock.MockAuthenticator) {
+				m.EXPECT().AuthenticateAPIKey("writer").
+					Return(&domain.Principal{Subject: "writer", Scopes: []string{domain.ScopeOrdersWrite}}, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      domain.ErrForbidden.Error(),
+		},
+		{
+			name:    "api_key_with_scope",
+			headers: map[string]string{rest.HeaderAPIKey: "reader"},
+			setup: func(m *mock.MockAuthenticator) {
+				m.EXPECT().AuthenticateAPIKey("reader").
+					Return(&domain.Principal{Subject: "reader", Scopes: []string{domain.ScopeOrdersRead}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:    "bearer_admin",
+			headers: map[string]string{"Authorization": "Bearer jwt-token"},
+			setup: func(m *mock.MockAuthenticator) {
+				m.EXPECT().AuthenticateToken("jwt-token").
+					Return(&domain.Principal{Subject: "admin", Scopes: []string{domain.ScopeAdmin}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "basic_scheme",
+			headers:            map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      domain.ErrUnauthenticated.Error(),
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockAuthenticator := mock.NewMockAuthenticator(ctrl)
+			if testCase.setup != nil {
+				testCase.setup(mockAuthenticator)
+			}
+
+			var principal *domain.Principal
+			handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = domain.PrincipalFromContext(r.Context())
+			}), rest.RequireScope(mockAuthenticator, domain.ScopeOrdersRead, logger.Discard()))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/order/uid", nil)
+			for key, value := range testCase.headers {
+				req.Header.Set(key, value)
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			if testCase.expectedError == "" {
+				require.NotNil(t, principal)
+				return
+			}
+
+			require.NotEmpty(t, respRec.Header().Get("WWW-Authenticate"))
+			var problem rest.Problem
+			require.NoError(t, json.NewDecoder(respRec.Body).Decode(&problem))
+			require.Equal(t, testCase.expectedError, problem.Detail)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		called := false
+		handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}), rest.RequireScope(nil, domain.ScopeAdmin, logger.Discard()))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		require.True(t, called)
+	})
+}
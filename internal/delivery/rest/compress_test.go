@@ -0,0 +1,120 @@
+package rest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	largeBody := []byte(strings.Repeat(`{"chrt_id":9934930,"name":"Mascaras"},`, 200))
+	smallBody := []byte(`{"order":{}}`)
+
+	decompress := map[string]func(t *testing.T, body []byte) []byte{
+		"gzip": func(t *testing.T, body []byte) []byte {
+			r, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			return data
+		},
+		"zstd": func(t *testing.T, body []byte) []byte {
+			r, err := zstd.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			defer r.Close()
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			return data
+		},
+	}
+
+	tbl := []struct {
+		name             string
+		acceptEncoding   string
+		body             []byte
+		status           int
+		contentRange     string
+		expectedEncoding string
+	}{
+		{name: "no_accept_encoding", body: largeBody, status: http.StatusOK},
+		{name: "gzip", acceptEncoding: "gzip", body: largeBody, status: http.StatusOK, expectedEncoding: "gzip"},
+		{name: "zstd", acceptEncoding: "zstd", body: largeBody, status: http.StatusOK, expectedEncoding: "zstd"},
+		{name: "zstd_preferred_on_tie", acceptEncoding: "gzip, deflate, br, zstd", body: largeBody, status: http.StatusOK, expectedEncoding: "zstd"},
+		{name: "quality", acceptEncoding: "zstd;q=0.5, gzip", body: largeBody, status: http.StatusOK, expectedEncoding: "gzip"},
+		{name: "wildcard", acceptEncoding: "*", body: largeBody, status: http.StatusOK, expectedEncoding: "zstd"},
+		{name: "wildcard_with_exclusion", acceptEncoding: "*, zstd;q=0", body: largeBody, status: http.StatusOK, expectedEncoding: "gzip"},
+		{name: "unsupported", acceptEncoding: "br", body: largeBody, status: http.StatusOK},
+		{name: "small_body", acceptEncoding: "gzip", body: smallBody, status: http.StatusOK},
+		{name: "not_modified", acceptEncoding: "gzip", status: http.StatusNotModified},
+		{name: "partial_content", acceptEncoding: "gzip", body: largeBody, status: http.StatusPartialContent},
+		{name: "content_range", acceptEncoding: "gzip", body: largeBody, status: http.StatusRequestedRangeNotSatisfiable,
+			contentRange: "bytes */8000"},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"abc"`)
+				w.Header().Set("Content-Length", strconv.Itoa(len(testCase.body)))
+				if testCase.contentRange != "" {
+					w.Header().Set("Content-Range", testCase.contentRange)
+				}
+				w.WriteHeader(testCase.status)
+				w.Write(testCase.body) //nolint:errcheck
+			}), rest.Compress())
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCase.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", testCase.acceptEncoding)
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.status, respRec.Code)
+			require.Equal(t, testCase.expectedEncoding, respRec.Header().Get("Content-Encoding"))
+			require.Contains(t, respRec.Header().Values("Vary"), "Accept-Encoding")
+
+			if testCase.expectedEncoding == "" {
+				require.Equal(t, `"abc"`, respRec.Header().Get("ETag"))
+				require.Equal(t, string(testCase.body), respRec.Body.String())
+				return
+			}
+
+			require.Equal(t, `W/"abc"`, respRec.Header().Get("ETag"))
+			require.Empty(t, respRec.Header().Get("Content-Length"))
+			require.Less(t, respRec.Body.Len(), len(testCase.body))
+			require.Equal(t, testCase.body, decompress[testCase.expectedEncoding](t, respRec.Body.Bytes()))
+		})
+	}
+}
+
+func TestCompressFlush(t *testing.T) {
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: order\n\n")) //nolint:errcheck
+		w.(http.Flusher).Flush()
+	}), rest.Compress())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	respRec := httptest.NewRecorder()
+	handler.ServeHTTP(respRec, req)
+
+	require.True(t, respRec.Flushed)
+	r, err := gzip.NewReader(respRec.Body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "event: order\n\n", string(data))
+}
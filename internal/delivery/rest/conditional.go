@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// orderCacheControl разрешает кешировать заказ только клиенту (ответ зависит от его скоупов
+// и может содержать персональные данные) и требует перепроверки через минуту.
+const orderCacheControl = "private, max-age=60, must-revalidate"
+
+// writeCacheable отправляет тело ответа с ETag и Last-Modified. Если клиент прислал
+// совпадающий If-None-Match (или, без него, If-Modified-Since не раньше lastModified),
+// отвечает 304 без тела. Нулевой lastModified не отправляется.
+func writeCacheable(w http.ResponseWriter, r *http.Request, contentType string, body []byte, lastModified time.Time) {
+	etag := computeETag(body)
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", orderCacheControl)
+	header.Add("Vary", "Accept, Authorization, "+HeaderAPIKey)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body) //nolint:errcheck,gosec
+}
+
+// computeETag возвращает сильный ETag по содержимому ответа.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified проверяет условия запроса по RFC 9110: If-None-Match имеет приоритет
+// над If-Modified-Since и сравнивается слабым сравнением.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
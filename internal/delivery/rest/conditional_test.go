@@ -0,0 +1,87 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetOrderConditional(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil).AnyTimes()
+	mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+	mockHTTPMetrics.EXPECT().IncRequest().AnyTimes()
+	mockHTTPMetrics.EXPECT().ObserveRequest(gomock.Any()).AnyTimes()
+
+	handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, handler.GetOrders())
+
+	get := func(query string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+validOrder.OrderUID+query, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		respRec := httptest.NewRecorder()
+		mux.ServeHTTP(respRec, req)
+		return respRec
+	}
+
+	first := get("", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	require.Equal(t, "Sun, 07 Jan 2024 06:22:08 GMT", first.Header().Get("Last-Modified"))
+	require.Contains(t, first.Header().Get("Cache-Control"), "private")
+	require.Contains(t, first.Header().Get("Vary"), rest.HeaderAPIKey)
+
+	require.Equal(t, etag, get("", nil).Header().Get("ETag"), "etag must be stable")
+	require.NotEqual(t, etag, get("?redact=true", nil).Header().Get("ETag"), "redacted view is a different representation")
+
+	tbl := []struct {
+		name               string
+		headers            map[string]string
+		expectedStatusCode int
+	}{
+		{name: "if_none_match", headers: map[string]string{"If-None-Match": etag}, expectedStatusCode: http.StatusNotModified},
+		{name: "if_none_match_weak_list", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, expectedStatusCode: http.StatusNotModified},
+		{name: "if_none_match_any", headers: map[string]string{"If-None-Match": "*"}, expectedStatusCode: http.StatusNotModified},
+		{name: "if_none_match_stale", headers: map[string]string{"If-None-Match": `"stale"`}, expectedStatusCode: http.StatusOK},
+		{
+			name:               "if_none_match_wins_over_if_modified_since",
+			headers:            map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Mon, 08 Jan 2024 00:00:00 GMT"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "if_modified_since_later", headers: map[string]string{"If-Modified-Since": "Sun, 07 Jan 2024 06:22:08 GMT"}, expectedStatusCode: http.StatusNotModified},
+		{name: "if_modified_since_earlier", headers: map[string]string{"If-Modified-Since": "Sat, 06 Jan 2024 00:00:00 GMT"}, expectedStatusCode: http.StatusOK},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			respRec := get("", testCase.headers)
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			require.Equal(t, etag, respRec.Header().Get("ETag"))
+			if testCase.expectedStatusCode == http.StatusNotModified {
+				require.Empty(t, respRec.Body.Bytes())
+			}
+		})
+	}
+
+	t.Run("errors_are_not_cacheable", func(t *testing.T) {
+		mockOrderService.EXPECT().GetOrder(gomock.Any(), "missing").Return(nil, domain.ErrOrderNotFound)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/order/missing", nil)
+		respRec := httptest.NewRecorder()
+		mux.ServeHTTP(respRec, req)
+		require.Equal(t, http.StatusNotFound, respRec.Code)
+		require.Empty(t, respRec.Header().Get("ETag"))
+	})
+}
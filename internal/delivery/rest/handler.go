@@ -1,9 +1,10 @@
 package rest
 
 import (
-	"encoding/json"
-	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"order_service/internal/domain"
@@ -13,17 +14,29 @@ import (
 type Handler struct {
 	service     domain.OrderService
 	httpMetrics domain.HTTPMetrics
+	log         *slog.Logger
+	streamsDone chan struct{}
+	stopOnce    sync.Once
 }
 
 // NewHandler создает новый HTTP обработчик с внедренным сервисом заказов.
-func NewHandler(service domain.OrderService, httpMetrics domain.HTTPMetrics) *Handler {
-	logger.DebugLogger.Println("Initializing Handler")
+func NewHandler(service domain.OrderService, httpMetrics domain.HTTPMetrics, log *slog.Logger) *Handler {
+	log.Debug("Initializing Handler")
 	return &Handler{
 		service:     service,
 		httpMetrics: httpMetrics,
+		log:         log,
+		streamsDone: make(chan struct{}),
 	}
 }
 
+// StopStreams завершает открытые потоки StreamOrders. http.Server.Shutdown не прерывает
+// активные запросы, поэтому вызывается из RegisterOnShutdown; клиенты переподключатся
+// с Last-Event-ID.
+func (h *Handler) StopStreams() {
+	h.stopOnce.Do(func() { close(h.streamsDone) })
+}
+
 // GetOrders возвращает HTTP обработчик для получения заказа по order_uid.
 func (h *Handler) GetOrders() func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -31,24 +44,62 @@ func (h *Handler) GetOrders() func(http.ResponseWriter, *http.Request) {
 		defer h.httpMetrics.ObserveRequest(start)
 		h.httpMetrics.IncRequest()
 
-		ctx := r.Context()
 		orderUID := r.PathValue("order_uid")
+		ctx := logger.WithOrderUID(r.Context(), orderUID)
+
+		redact, err := shouldRedact(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		encoder, ok := negotiateEncoder(r.Header.Get("Accept"))
+		if !ok {
+			writeProblem(w, r, domain.ErrNotAcceptable)
+			return
+		}
 
 		order, err := h.service.GetOrder(ctx, orderUID)
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			if errors.Is(err, domain.ErrOrderNotFound) {
-				w.WriteHeader(http.StatusNotFound)
-				json.NewEncoder(w).Encode(ErrorResponse{Error: domain.ErrOrderNotFound.Error()}) //nolint:errcheck,gosec
-			} else {
-				logger.ErrorLogger.Println(err)
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(ErrorResponse{Error: domain.ErrInternalServer.Error()}) //nolint:errcheck,gosec
+			if problemStatus[domain.CodeOf(err)] >= http.StatusInternalServerError {
+				h.log.ErrorContext(ctx, "Failed to get order", slog.Any("error", err))
 			}
+			writeProblem(w, r, err)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(OrderResponse{Order: order}) //nolint:errcheck,gosec
+		if redact {
+			order = order.Redacted()
+		}
+
+		body, err := encoder.encode(OrderResponse{Order: order})
+		if err != nil {
+			h.log.ErrorContext(ctx, "Failed to encode order", slog.Any("error", err))
+			writeProblem(w, r, err)
+			return
+		}
+
+		// Заказ не меняется после сохранения, поэтому дата создания служит Last-Modified
+		lastModified, _ := time.Parse(time.RFC3339, order.DateCreated)
+		writeCacheable(w, r, encoder.mediaType, body, lastModified)
+	}
+}
+
+// shouldRedact решает, маскировать ли персональные данные в ответе.
+// Клиенту без скоупа orders:pii данные маскируются всегда, остальные могут
+// запросить маскирование параметром ?redact=true. Если аутентификация
+// выключена (клиента в контексте нет), решает только параметр.
+func shouldRedact(r *http.Request) (bool, error) {
+	redact := false
+	if value := r.URL.Query().Get("redact"); value != "" {
+		var err error
+		if redact, err = strconv.ParseBool(value); err != nil {
+			return false, domain.ErrInvalidRedactParam
+		}
+	}
+
+	if principal, ok := domain.PrincipalFromContext(r.Context()); ok && !principal.HasScope(domain.ScopePIIRead) {
+		return true, nil
 	}
+	return redact, nil
 }
@@ -9,7 +9,6 @@ import (
 	"net/url"
 	"testing"
 
-	"order_service/config"
 	"order_service/internal/delivery/rest"
 	"order_service/internal/domain"
 	"order_service/internal/logger"
@@ -28,16 +27,10 @@ type instance struct {
 
 	expectedStatusCode    int
 	expectedOrderResponse rest.OrderResponse
-	expectedErrorResponse rest.ErrorResponse
+	expectedErrorResponse rest.Problem
 }
 
 var (
-	cfg = &config.Config{
-		Serv: config.Server{
-			Debug: false,
-		},
-	}
-
 	validOrder *domain.Order = &domain.Order{
 		OrderUID:          "b563feb7b2b84b6test",
 		TrackNumber:       "WBILMTESTTRACK",
@@ -96,7 +89,7 @@ var (
 
 			expectedStatusCode:    http.StatusOK,
 			expectedOrderResponse: rest.OrderResponse{Order: validOrder},
-			expectedErrorResponse: rest.ErrorResponse{},
+			expectedErrorResponse: rest.Problem{},
 		},
 		// 2. Несуществующий orderUID и ответ 404 NotFound
 		{
@@ -106,7 +99,7 @@ var (
 			outputErr:          domain.ErrOrderNotFound,
 
 			expectedOrderResponse: rest.OrderResponse{},
-			expectedErrorResponse: rest.ErrorResponse{Error: domain.ErrOrderNotFound.Error()},
+			expectedErrorResponse: rest.Problem{Code: domain.CodeNotFound, Detail: domain.ErrOrderNotFound.Error()},
 		},
 		// 3. Пустой orderUID и ответ 404 NotFound
 		{
@@ -115,7 +108,7 @@ var (
 
 			expectedStatusCode:    http.StatusNotFound,
 			expectedOrderResponse: rest.OrderResponse{},
-			expectedErrorResponse: rest.ErrorResponse{},
+			expectedErrorResponse: rest.Problem{},
 		},
 		// 4. Ошибка в сервисе и ответ 500 InternalServerError
 		{
@@ -124,13 +117,12 @@ var (
 
 			expectedStatusCode:    http.StatusInternalServerError,
 			outputErr:             errors.New("internal server error"),
-			expectedErrorResponse: rest.ErrorResponse{Error: "internal server error"},
+			expectedErrorResponse: rest.Problem{Code: domain.CodeInternal, Detail: "internal server error"},
 		},
 	}
 )
 
 func TestGetOrder(t *testing.T) {
-	logger.InitLogger(cfg)
 
 	for i, testCase := range tbl {
 		t.Run(fmt.Sprintf("test case №%d", i+1), func(t *testing.T) {
@@ -157,7 +149,7 @@ func TestGetOrder(t *testing.T) {
 					ObserveRequest(gomock.Any())
 			}
 
-			handler := rest.NewHandler(mockOrderService, mockHTTPpMetrics)
+			handler := rest.NewHandler(mockOrderService, mockHTTPpMetrics, logger.Discard())
 			mux := http.NewServeMux()
 			mux.HandleFunc(pattern, handler.GetOrders())
 
@@ -173,7 +165,7 @@ func TestGetOrder(t *testing.T) {
 
 			var (
 				actualOrderResponse rest.OrderResponse
-				actualErrorResponse rest.ErrorResponse
+				actualErrorResponse rest.Problem
 			)
 
 			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
@@ -181,11 +173,93 @@ func TestGetOrder(t *testing.T) {
 			if testCase.expectedOrderResponse.Order != nil {
 				require.NoError(t, json.NewDecoder(respRec.Body).Decode(&actualOrderResponse))
 				require.Equal(t, testCase.expectedOrderResponse, actualOrderResponse)
-			} else if testCase.expectedErrorResponse.Error != "" {
+			} else if testCase.expectedErrorResponse.Code != "" {
+				require.Equal(t, rest.MediaTypeProblem, respRec.Header().Get("Content-Type"))
 				require.NoError(t, json.NewDecoder(respRec.Body).Decode(&actualErrorResponse))
-				require.Equal(t, testCase.expectedErrorResponse, actualErrorResponse)
+				require.Equal(t, testCase.expectedErrorResponse.Code, actualErrorResponse.Code)
+				require.Equal(t, testCase.expectedErrorResponse.Detail, actualErrorResponse.Detail)
+				require.Equal(t, testCase.expectedStatusCode, actualErrorResponse.Status)
+				require.Equal(t, testURL, actualErrorResponse.Instance)
+				return
+			}
+		})
+	}
+}
+
+func TestGetOrderRedaction(t *testing.T) {
+	tbl := []struct {
+		name               string
+		query              string
+		principal          *domain.Principal
+		expectedStatusCode int
+		expectedRedacted   bool
+	}{
+		{name: "auth_disabled", expectedStatusCode: http.StatusOK},
+		{name: "auth_disabled_redact_flag", query: "?redact=true", expectedStatusCode: http.StatusOK, expectedRedacted: true},
+		{
+			name:               "without_pii_scope",
+			principal:          &domain.Principal{Scopes: []string{domain.ScopeOrdersRead}},
+			expectedStatusCode: http.StatusOK,
+			expectedRedacted:   true,
+		},
+		{
+			name:               "without_pii_scope_cannot_unredact",
+			query:              "?redact=false",
+			principal:          &domain.Principal{Scopes: []string{domain.ScopeOrdersRead}},
+			expectedStatusCode: http.StatusOK,
+			expectedRedacted:   true,
+		},
+		{
+			name:               "pii_scope",
+			principal:          &domain.Principal{Scopes: []string{domain.ScopeOrdersRead, domain.ScopePIIRead}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "pii_scope_redact_flag",
+			query:              "?redact=1",
+			principal:          &domain.Principal{Scopes: []string{domain.ScopeAdmin}},
+			expectedStatusCode: http.StatusOK,
+			expectedRedacted:   true,
+		},
+		{name: "invalid_flag", query: "?redact=maybe", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockOrderService := mock.NewMockOrderService(ctrl)
+			mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil).AnyTimes()
+			mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+			mockHTTPMetrics.EXPECT().IncRequest()
+			mockHTTPMetrics.EXPECT().ObserveRequest(gomock.Any())
+
+			handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+			mux := http.NewServeMux()
+			mux.HandleFunc(pattern, handler.GetOrders())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+validOrder.OrderUID+testCase.query, nil)
+			if testCase.principal != nil {
+				req = req.WithContext(domain.WithPrincipal(req.Context(), testCase.principal))
+			}
+			respRec := httptest.NewRecorder()
+			mux.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			if testCase.expectedStatusCode != http.StatusOK {
 				return
 			}
+
+			var actual rest.OrderResponse
+			require.NoError(t, json.NewDecoder(respRec.Body).Decode(&actual))
+			expected := validOrder
+			if testCase.expectedRedacted {
+				expected = validOrder.Redacted()
+				require.Equal(t, "+972*****00", actual.Order.Delivery.Phone)
+				require.Equal(t, "t***@gmail.com", actual.Order.Delivery.Email)
+			}
+			require.Equal(t, expected, actual.Order)
 		})
 	}
 }
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLen ограничивает длину входящего X-Request-ID, чтобы клиент не раздувал логи.
+const maxRequestIDLen = 128
+
+// Middleware оборачивает HTTP обработчик дополнительной логикой.
+type Middleware func(http.Handler) http.Handler
+
+// Chain применяет middleware к обработчику. Первый middleware в списке — внешний.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// RequestID возвращает middleware, который берет X-Request-ID из запроса
+// или генерирует новый, возвращает его в ответе и кладет в контекст логгера.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(HeaderRequestID)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			w.Header().Set(HeaderRequestID, requestID)
+			ctx := logger.WithRequestID(r.Context(), requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AccessLog возвращает middleware, который пишет структурированную запись о каждом запросе.
+func AccessLog(log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := WrapResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			log.InfoContext(r.Context(), "HTTP request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.Status()),
+				slog.Int("bytes", rw.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// Recovery возвращает middleware, который перехватывает панику в обработчике
+// и отвечает клиенту problem+json со статусом 500.
+func Recovery(log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := WrapResponseWriter(w)
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				log.ErrorContext(r.Context(), "Panic in HTTP handler",
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				if rw.WroteHeader() {
+					return
+				}
+				writeProblem(rw, r, domain.ErrInternalServer)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
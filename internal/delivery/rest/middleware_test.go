@@ -0,0 +1,123 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	tbl := []struct {
+		name           string
+		inputRequestID string
+		expectSame     bool
+	}{
+		{name: "propagate_incoming", inputRequestID: "req-123", expectSame: true},
+		{name: "generate_missing", inputRequestID: ""},
+		{name: "replace_invalid", inputRequestID: "bad id\n"},
+		{name: "replace_too_long", inputRequestID: strings.Repeat("a", 200)},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ctxRequestID string
+			handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxRequestID = logger.RequestID(r.Context())
+			}), rest.RequestID())
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCase.inputRequestID != "" {
+				req.Header.Set(rest.HeaderRequestID, testCase.inputRequestID)
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+
+			respRequestID := respRec.Header().Get(rest.HeaderRequestID)
+			require.NotEmpty(t, respRequestID)
+			require.Equal(t, respRequestID, ctxRequestID)
+			if testCase.expectSame {
+				require.Equal(t, testCase.inputRequestID, respRequestID)
+			} else {
+				require.NotEqual(t, testCase.inputRequestID, respRequestID)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.New(&buf, logger.FormatJSON, new(slog.LevelVar))
+	require.NoError(t, err)
+
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout")) //nolint:errcheck,gosec
+	}), rest.RequestID(), rest.AccessLog(log))
+
+	req := httptest.NewRequest(http.MethodPost, "/teapot", nil)
+	req.Header.Set(rest.HeaderRequestID, "req-access")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "HTTP request", record["msg"])
+	require.Equal(t, http.MethodPost, record["method"])
+	require.Equal(t, "/teapot", record["path"])
+	require.EqualValues(t, http.StatusTeapot, record["status"])
+	require.EqualValues(t, len("short and stout"), record["bytes"])
+	require.Equal(t, req.RemoteAddr, record["remote_addr"])
+	require.Equal(t, "req-access", record[logger.KeyRequestID])
+	require.Contains(t, record, "duration")
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.New(&buf, logger.FormatJSON, new(slog.LevelVar))
+	require.NoError(t, err)
+
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), rest.AccessLog(logger.Discard()), rest.Recovery(log))
+
+	respRec := httptest.NewRecorder()
+	handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusInternalServerError, respRec.Code)
+	require.Equal(t, rest.MediaTypeProblem, respRec.Header().Get("Content-Type"))
+
+	var problem rest.Problem
+	require.NoError(t, json.NewDecoder(respRec.Body).Decode(&problem))
+	require.Equal(t, domain.ErrInternalServer.Error(), problem.Detail)
+	require.Equal(t, domain.CodeInternal, problem.Code)
+	require.Contains(t, buf.String(), `"panic":"boom"`)
+}
+
+func TestResponseWriter(t *testing.T) {
+	respRec := httptest.NewRecorder()
+	rw := rest.WrapResponseWriter(respRec)
+
+	require.Same(t, rw, rest.WrapResponseWriter(rw))
+	require.Equal(t, http.StatusOK, rw.Status())
+	require.False(t, rw.WroteHeader())
+
+	_, err := rw.Write([]byte("hello"))
+	require.NoError(t, err)
+	rw.WriteHeader(http.StatusNotFound) // повторный заголовок игнорируется
+
+	require.True(t, rw.WroteHeader())
+	require.Equal(t, http.StatusOK, rw.Status())
+	require.Equal(t, 5, rw.BytesWritten())
+	require.Equal(t, http.StatusOK, respRec.Code)
+}
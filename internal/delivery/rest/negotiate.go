@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"order_service/internal/delivery/protoconv"
+	orderv1 "order_service/internal/gen/order/v1"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Форматы ответа с заказом, выбираемые по заголовку Accept. JSON — формат по умолчанию.
+const (
+	MediaTypeJSON     = "application/json"
+	MediaTypeProtobuf = "application/x-protobuf"
+	MediaTypeMsgPack  = "application/msgpack"
+)
+
+type responseEncoder struct {
+	mediaType string
+	aliases   []string
+	encode    func(resp OrderResponse) ([]byte, error)
+}
+
+// responseEncoders перечислены в порядке предпочтения при равном q.
+var responseEncoders = []responseEncoder{
+	{mediaType: MediaTypeJSON, encode: encodeJSON},
+	{mediaType: MediaTypeProtobuf, aliases: []string{"application/protobuf", "application/vnd.google.protobuf"}, encode: encodeProtobuf},
+	{mediaType: MediaTypeMsgPack, aliases: []string{"application/x-msgpack", "application/vnd.msgpack"}, encode: encodeMsgPack},
+}
+
+func encodeJSON(resp OrderResponse) ([]byte, error) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
+
+// encodeProtobuf кодирует ответ детерминированно, чтобы ETag не зависел от порядка полей.
+func encodeProtobuf(resp OrderResponse) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(&orderv1.OrderResponse{
+		Order: protoconv.OrderToProto(resp.Order),
+	})
+}
+
+// encodeMsgPack использует json-теги, поэтому имена полей совпадают с JSON ответом.
+func encodeMsgPack(resp OrderResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(resp); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiateEncoder выбирает формат ответа по Accept (RFC 9110, 12.5.1): для каждого формата
+// берется q самого конкретного подходящего диапазона. Пустой Accept означает JSON.
+// false — ни один формат не подходит.
+func negotiateEncoder(accept string) (responseEncoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return responseEncoders[0], true
+	}
+
+	ranges := parseQualityList(accept)
+	best, bestQ := -1, 0.0
+	for i, encoder := range responseEncoders {
+		if q := mediaTypeQuality(encoder, ranges); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return responseEncoder{}, false
+	}
+	return responseEncoders[best], true
+}
+
+func mediaTypeQuality(encoder responseEncoder, ranges []qualityValue) float64 {
+	names := append([]string{encoder.mediaType}, encoder.aliases...)
+	mainType, _, _ := strings.Cut(encoder.mediaType, "/")
+
+	q, specificity := 0.0, 0
+	for _, r := range ranges {
+		s := 0
+		switch {
+		case slices.Contains(names, r.value):
+			s = 3
+		case r.value == mainType+"/*":
+			s = 2
+		case r.value == "*/*":
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+type qualityValue struct {
+	value string
+	q     float64
+}
+
+// parseQualityList разбирает заголовки вида "a;q=0.5, b" (Accept, Accept-Encoding).
+// Значения приводятся к нижнему регистру, параметры кроме q отбрасываются.
+// Результат отсортирован по убыванию q с сохранением исходного порядка.
+func parseQualityList(header string) []qualityValue {
+	var values []qualityValue
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, raw, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(raw, 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				}
+			}
+		}
+		values = append(values, qualityValue{value: value, q: q})
+	}
+
+	sort.SliceStable(values, func(i, j int) bool { return values[i].q > values[j].q })
+	return values
+}
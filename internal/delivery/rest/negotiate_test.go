@@ -0,0 +1,105 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+)
+
+// decoders восстанавливают заказ из тела ответа в каждом из форматов.
+var decoders = map[string]func(t *testing.T, body []byte) *domain.Order{
+	rest.MediaTypeJSON: func(t *testing.T, body []byte) *domain.Order {
+		var resp rest.OrderResponse
+		require.NoError(t, json.Unmarshal(body, &resp))
+		return resp.Order
+	},
+	rest.MediaTypeProtobuf: func(t *testing.T, body []byte) *domain.Order {
+		var resp orderv1.OrderResponse
+		require.NoError(t, proto.Unmarshal(body, &resp))
+		return protoconv.OrderFromProto(resp.GetOrder())
+	},
+	rest.MediaTypeMsgPack: func(t *testing.T, body []byte) *domain.Order {
+		var resp rest.OrderResponse
+		dec := msgpack.NewDecoder(bytes.NewReader(body))
+		dec.SetCustomStructTag("json")
+		require.NoError(t, dec.Decode(&resp))
+		return resp.Order
+	},
+}
+
+func TestGetOrderContentNegotiation(t *testing.T) {
+	tbl := []struct {
+		name                string
+		accept              string
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{name: "default", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeJSON},
+		{name: "any", accept: "*/*", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeJSON},
+		{name: "json", accept: "application/json", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeJSON},
+		{name: "protobuf", accept: "application/x-protobuf", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeProtobuf},
+		{name: "protobuf_alias", accept: "application/protobuf", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeProtobuf},
+		{name: "msgpack", accept: "application/msgpack", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeMsgPack},
+		{
+			name:                "quality",
+			accept:              "application/json;q=0.5, application/x-msgpack;q=0.9, application/x-protobuf;q=0.1",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: rest.MediaTypeMsgPack,
+		},
+		{
+			name:                "specific_range_wins_over_wildcard",
+			accept:              "*/*;q=0.8, application/json;q=0.1",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: rest.MediaTypeProtobuf,
+		},
+		{name: "browser", accept: "text/html,application/xhtml+xml,*/*;q=0.8", expectedStatusCode: http.StatusOK, expectedContentType: rest.MediaTypeJSON},
+		{name: "not_acceptable", accept: "text/html", expectedStatusCode: http.StatusNotAcceptable, expectedContentType: rest.MediaTypeProblem},
+		{name: "json_disabled", accept: "application/json;q=0, text/plain", expectedStatusCode: http.StatusNotAcceptable, expectedContentType: rest.MediaTypeProblem},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockOrderService := mock.NewMockOrderService(ctrl)
+			mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil).AnyTimes()
+			mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+			mockHTTPMetrics.EXPECT().IncRequest()
+			mockHTTPMetrics.EXPECT().ObserveRequest(gomock.Any())
+
+			handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+			mux := http.NewServeMux()
+			mux.HandleFunc(pattern, handler.GetOrders())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/order/"+validOrder.OrderUID, nil)
+			if testCase.accept != "" {
+				req.Header.Set("Accept", testCase.accept)
+			}
+			respRec := httptest.NewRecorder()
+			mux.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			require.Equal(t, testCase.expectedContentType, respRec.Header().Get("Content-Type"))
+			if testCase.expectedStatusCode != http.StatusOK {
+				return
+			}
+			require.Contains(t, respRec.Header().Values("Vary"), "Accept, Authorization, "+rest.HeaderAPIKey)
+			require.Equal(t, validOrder, decoders[testCase.expectedContentType](t, respRec.Body.Bytes()))
+		})
+	}
+}
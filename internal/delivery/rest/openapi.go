@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"order_service/internal/domain"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+func init() {
+	// Бинарные форматы и поток событий проверяются только по наличию в спецификации, без разбора тела
+	for _, mediaType := range []string{MediaTypeProtobuf, MediaTypeMsgPack, MediaTypeEventStream} {
+		openapi3filter.RegisterBodyDecoder(mediaType, openapi3filter.FileBodyDecoder)
+	}
+}
+
+// OpenAPI — спецификация REST API, по которой проверяются запросы и ответы.
+type OpenAPI struct {
+	raw    []byte
+	spec   *openapi3.T
+	router routers.Router
+}
+
+// NewOpenAPI разбирает и проверяет спецификацию в формате JSON или YAML.
+func NewOpenAPI(data []byte) (*OpenAPI, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &OpenAPI{raw: data, spec: spec, router: router}, nil
+}
+
+// Spec возвращает разобранную спецификацию.
+func (o *OpenAPI) Spec() *openapi3.T {
+	return o.spec
+}
+
+// Handler отдает спецификацию как есть.
+func (o *OpenAPI) Handler() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(o.raw) //nolint:errcheck,gosec
+	}
+}
+
+// ValidateRequests возвращает middleware, который проверяет параметры и тело запроса
+// по спецификации и отвечает 400 с описанием первого нарушения. Запросы к путям,
+// которых нет в спецификации (UI, статика), пропускаются. Учетные данные здесь
+// не проверяются — это делает RequireScope.
+func (o *OpenAPI) ValidateRequests(log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			input, ok := o.requestInput(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				log.InfoContext(r.Context(), "Request rejected by OpenAPI validation", slog.Any("error", err))
+				writeProblem(w, r, requestValidationError(err))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateResponse проверяет, что ответ на запрос r описан в спецификации:
+// документирован статус, заголовки и тело соответствуют схемам.
+func (o *OpenAPI) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	input, ok := o.requestInput(r)
+	if !ok {
+		return fmt.Errorf("%s %s is not described in the openapi spec", r.Method, r.URL.Path)
+	}
+	input.Options.IncludeResponseStatus = true
+
+	return openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(body)),
+		Options:                input.Options,
+	})
+}
+
+func (o *OpenAPI) requestInput(r *http.Request) (*openapi3filter.RequestValidationInput, bool) {
+	route, pathParams, err := o.router.FindRoute(r)
+	if err != nil {
+		return nil, false
+	}
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, true
+}
+
+// requestValidationError сокращает ошибку валидатора до нарушения без дампа схемы
+// и относит ее к domain.ErrInvalidRequest.
+func requestValidationError(err error) error {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			requestErr.Err = errors.New(schemaErr.Reason)
+		}
+		err = requestErr
+	}
+	return fmt.Errorf("%w: %w", domain.ErrInvalidRequest, err)
+}
@@ -0,0 +1,273 @@
+package rest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"order_service/api"
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newContractServer собирает маршруты через rest.RegisterRoutes, как cmd/main.go, поверх моков сервиса.
+func newContractServer(t *testing.T, openAPI *rest.OpenAPI, limiters rest.Limiters) http.Handler {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), validOrder.OrderUID).Return(validOrder, nil).AnyTimes()
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), "missing").Return(nil, domain.ErrOrderNotFound).AnyTimes()
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), "broken").Return(nil, errors.New("connection refused")).AnyTimes()
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), "unavailable").Return(nil, domain.ErrUnavailable).AnyTimes()
+	mockOrderService.EXPECT().GetOrder(gomock.Any(), "slow").Return(nil, context.DeadlineExceeded).AnyTimes()
+	// Канал закрыт после одного события, поэтому поток завершается сам, как при медленном клиенте
+	mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).DoAndReturn(
+		func(context.Context, uint64) (<-chan domain.OrderEvent, error) {
+			events := make(chan domain.OrderEvent, 1)
+			events <- domain.OrderEvent{ID: 1, Type: domain.EventOrderCreated, Order: validOrder}
+			close(events)
+			return events, nil
+		}).AnyTimes()
+
+	mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+	mockHTTPMetrics.EXPECT().IncRequest().AnyTimes()
+	mockHTTPMetrics.EXPECT().ObserveRequest(gomock.Any()).AnyTimes()
+
+	mockAuthenticator := mock.NewMockAuthenticator(ctrl)
+	mockAuthenticator.EXPECT().AuthenticateAPIKey("reader").
+		Return(&domain.Principal{Subject: "reader", Scopes: []string{domain.ScopeOrdersRead}}, nil).AnyTimes()
+	mockAuthenticator.EXPECT().AuthenticateAPIKey("admin").
+		Return(&domain.Principal{Subject: "admin", Scopes: []string{domain.ScopeAdmin}}, nil).AnyTimes()
+	mockAuthenticator.EXPECT().AuthenticateAPIKey("bad").Return(nil, domain.ErrUnauthenticated).AnyTimes()
+
+	handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+	adminHandler := rest.NewAdminHandler(new(slog.LevelVar), logger.Discard())
+
+	mux := http.NewServeMux()
+	rest.RegisterRoutes(mux, handler, adminHandler, openAPI, mockAuthenticator, limiters, logger.Discard())
+
+	return rest.Chain(mux, openAPI.ValidateRequests(logger.Discard()), rest.Compress())
+}
+
+func TestOpenAPIContract(t *testing.T) {
+	openAPI, err := rest.NewOpenAPI(api.OpenAPI)
+	require.NoError(t, err)
+
+	limited := mock.NewMockRateLimiter(gomock.NewController(t))
+	limited.EXPECT().Allow(gomock.Any()).Return(domain.RateLimitDecision{Limit: 1, RetryAfter: time.Second}).AnyTimes()
+
+	server := newContractServer(t, openAPI, rest.Limiters{})
+	limitedServer := newContractServer(t, openAPI, rest.Limiters{Order: limited})
+	ipLimitedServer := newContractServer(t, openAPI, rest.Limiters{IP: limited})
+
+	orderURL := "/api/v1/order/" + validOrder.OrderUID
+	tbl := []struct {
+		name               string
+		server             http.Handler
+		method             string
+		url                string
+		body               string
+		headers            map[string]string
+		expectedStatusCode int
+	}{
+		{name: "get_order_json", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusOK},
+		{name: "get_order_redacted", method: http.MethodGet, url: orderURL + "?redact=true", headers: map[string]string{rest.HeaderAPIKey: "reader"}, expectedStatusCode: http.StatusOK},
+		{name: "get_order_gzip", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin", "Accept-Encoding": "gzip"}, expectedStatusCode: http.StatusOK},
+		{name: "get_order_protobuf", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin", "Accept": rest.MediaTypeProtobuf}, expectedStatusCode: http.StatusOK},
+		{name: "get_order_msgpack", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin", "Accept": rest.MediaTypeMsgPack}, expectedStatusCode: http.StatusOK},
+		{name: "get_order_not_modified", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin", "If-None-Match": "*"}, expectedStatusCode: http.StatusNotModified},
+		{name: "get_order_invalid_redact", method: http.MethodGet, url: orderURL + "?redact=maybe", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusBadRequest},
+		{name: "get_order_unauthenticated", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "bad"}, expectedStatusCode: http.StatusUnauthorized},
+		{name: "get_order_not_found", method: http.MethodGet, url: "/api/v1/order/missing", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusNotFound},
+		{name: "get_order_not_acceptable", method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin", "Accept": "text/csv"}, expectedStatusCode: http.StatusNotAcceptable},
+		{name: "get_order_internal_error", method: http.MethodGet, url: "/api/v1/order/broken", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusInternalServerError},
+		{name: "get_order_unavailable", method: http.MethodGet, url: "/api/v1/order/unavailable", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusServiceUnavailable},
+		{name: "get_order_timeout", method: http.MethodGet, url: "/api/v1/order/slow", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusGatewayTimeout},
+		{name: "get_order_rate_limited", server: limitedServer, method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusTooManyRequests},
+		{name: "get_order_bad_key_ip_limited", server: ipLimitedServer, method: http.MethodGet, url: orderURL, headers: map[string]string{rest.HeaderAPIKey: "bad"}, expectedStatusCode: http.StatusTooManyRequests},
+		{name: "stream_orders", method: http.MethodGet, url: "/api/v1/orders/stream?customer_id=" + validOrder.CustomerID, headers: map[string]string{rest.HeaderAPIKey: "reader"}, expectedStatusCode: http.StatusOK},
+		{name: "stream_orders_invalid_last_event_id", method: http.MethodGet, url: "/api/v1/orders/stream", headers: map[string]string{rest.HeaderAPIKey: "reader", "Last-Event-ID": "abc"}, expectedStatusCode: http.StatusBadRequest},
+		{name: "stream_orders_rate_limited", server: limitedServer, method: http.MethodGet, url: "/api/v1/orders/stream", headers: map[string]string{rest.HeaderAPIKey: "reader"}, expectedStatusCode: http.StatusTooManyRequests},
+		{name: "get_log_level", method: http.MethodGet, url: "/admin/log-level", headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusOK},
+		{name: "get_log_level_forbidden", method: http.MethodGet, url: "/admin/log-level", headers: map[string]string{rest.HeaderAPIKey: "reader"}, expectedStatusCode: http.StatusForbidden},
+		{name: "set_log_level", method: http.MethodPut, url: "/admin/log-level", body: `{"level":"debug"}`, headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusOK},
+		{name: "set_log_level_unknown", method: http.MethodPut, url: "/admin/log-level", body: `{"level":"loud"}`, headers: map[string]string{rest.HeaderAPIKey: "admin"}, expectedStatusCode: http.StatusBadRequest},
+		{name: "get_metrics", method: http.MethodGet, url: "/metrics", expectedStatusCode: http.StatusOK},
+		{name: "get_openapi", method: http.MethodGet, url: "/api/openapi.json", expectedStatusCode: http.StatusOK},
+	}
+
+	covered := map[string]bool{}
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := server
+			if testCase.server != nil {
+				handler = testCase.server
+			}
+
+			req := httptest.NewRequest(testCase.method, testCase.url, strings.NewReader(testCase.body))
+			if testCase.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			for key, value := range testCase.headers {
+				req.Header.Set(key, value)
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code, respRec.Body.String())
+
+			// Тело проверяется в том виде, в каком его отдал обработчик, до сжатия
+			body := respRec.Body.Bytes()
+			if respRec.Header().Get("Content-Encoding") == "gzip" {
+				r, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+				body, err = io.ReadAll(r)
+				require.NoError(t, err)
+			}
+
+			checkReq := httptest.NewRequest(testCase.method, testCase.url, nil)
+			for key, value := range testCase.headers {
+				checkReq.Header.Set(key, value)
+			}
+			require.NoError(t, openAPI.ValidateResponse(checkReq, respRec.Code, respRec.Header(), body))
+
+			route, _, err := findOperation(openAPI.Spec(), testCase.method, checkReq.URL.Path)
+			require.NoError(t, err)
+			covered[route] = true
+		})
+	}
+
+	// Каждая операция спецификации должна проверяться хотя бы одним запросом
+	var missing []string
+	for path, item := range openAPI.Spec().Paths.Map() {
+		for method, operation := range item.Operations() {
+			if !covered[method+" "+path] {
+				missing = append(missing, operation.OperationID)
+			}
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(t, missing, "operations without contract test cases")
+}
+
+func TestOpenAPIOrderSchema(t *testing.T) {
+	openAPI, err := rest.NewOpenAPI(api.OpenAPI)
+	require.NoError(t, err)
+
+	// Схемы должны описывать ровно те поля, которые сериализует domain
+	for name, typ := range map[string]reflect.Type{
+		"Order":         reflect.TypeOf(domain.Order{}),
+		"Delivery":      reflect.TypeOf(domain.Delivery{}),
+		"Payment":       reflect.TypeOf(domain.Payment{}),
+		"Item":          reflect.TypeOf(domain.Item{}),
+		"OrderResponse": reflect.TypeOf(rest.OrderResponse{}),
+		"Problem":       reflect.TypeOf(rest.Problem{}),
+		"LogLevel":      reflect.TypeOf(rest.LogLevelResponse{}),
+	} {
+		schema := openAPI.Spec().Components.Schemas[name]
+		require.NotNil(t, schema, name)
+		require.ElementsMatch(t, jsonFields(typ), keys(schema.Value.Properties), name)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	openAPI, err := rest.NewOpenAPI(api.OpenAPI)
+	require.NoError(t, err)
+
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		openAPI.ValidateRequests(logger.Discard()))
+
+	tbl := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		expectedStatusCode int
+	}{
+		{name: "valid_query", method: http.MethodGet, url: "/api/v1/order/uid?redact=false", expectedStatusCode: http.StatusOK},
+		{name: "invalid_query", method: http.MethodGet, url: "/api/v1/order/uid?redact=maybe", expectedStatusCode: http.StatusBadRequest},
+		{name: "valid_body", method: http.MethodPut, url: "/admin/log-level", body: `{"level":"warn"}`, expectedStatusCode: http.StatusOK},
+		{name: "missing_body", method: http.MethodPut, url: "/admin/log-level", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown_field", method: http.MethodPut, url: "/admin/log-level", body: `{"level":"warn","extra":1}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "wrong_type", method: http.MethodPut, url: "/admin/log-level", body: `{"level":1}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "path_outside_spec", method: http.MethodGet, url: "/style.css", expectedStatusCode: http.StatusOK},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(testCase.method, testCase.url, strings.NewReader(testCase.body))
+			if testCase.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code, respRec.Body.String())
+			if testCase.expectedStatusCode == http.StatusBadRequest {
+				require.Equal(t, rest.MediaTypeProblem, respRec.Header().Get("Content-Type"))
+				require.NotContains(t, respRec.Body.String(), "Schema:", "schema dump must not leak to clients")
+			}
+		})
+	}
+}
+
+// findOperation возвращает ключ операции "METHOD /path/template" для запроса.
+func findOperation(spec *openapi3.T, method, path string) (string, bool, error) {
+	for template, item := range spec.Paths.Map() {
+		if item.GetOperation(method) == nil {
+			continue
+		}
+		if matchPath(template, path) {
+			return method + " " + template, true, nil
+		}
+	}
+	return "", false, errors.New("no operation for " + method + " " + path)
+}
+
+func matchPath(template, path string) bool {
+	templateParts, pathParts := strings.Split(template, "/"), strings.Split(path, "/")
+	if len(templateParts) != len(pathParts) {
+		return false
+	}
+	for i, part := range templateParts {
+		if !strings.HasPrefix(part, "{") && part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonFields(typ reflect.Type) []string {
+	fields := make([]string, 0, typ.NumField())
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func keys(schemas openapi3.Schemas) []string {
+	result := make([]string, 0, len(schemas))
+	for key := range schemas {
+		result = append(result, key)
+	}
+	return result
+}
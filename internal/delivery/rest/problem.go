@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"order_service/internal/domain"
+)
+
+// MediaTypeProblem — тип ответа с ошибкой по RFC 9457.
+const MediaTypeProblem = "application/problem+json"
+
+// problemTypePrefix — пространство имен URI типов ошибок; тип — префикс и domain.ErrorCode.
+const problemTypePrefix = "urn:order-service:problem:"
+
+// retryAfterTransient — через сколько секунд повторять запрос, если БД недоступна или не ответила вовремя.
+const retryAfterTransient = 2
+
+// StatusClientClosedRequest — клиент отменил запрос до ответа (нестандартный код nginx).
+const StatusClientClosedRequest = 499
+
+// Problem — описание ошибки в формате application/problem+json (RFC 9457).
+type Problem struct {
+	Type      string           `json:"type"`
+	Title     string           `json:"title"`
+	Status    int              `json:"status"`
+	Detail    string           `json:"detail"`
+	Instance  string           `json:"instance"`
+	Code      domain.ErrorCode `json:"code"`
+	RequestID string           `json:"request_id,omitempty"`
+}
+
+// problemStatus сопоставляет классы ошибок domain со статусами HTTP.
+var problemStatus = map[domain.ErrorCode]int{
+	domain.CodeNotFound:        http.StatusNotFound,
+	domain.CodeValidation:      http.StatusBadRequest,
+	domain.CodeConflict:        http.StatusConflict,
+	domain.CodeUnavailable:     http.StatusServiceUnavailable,
+	domain.CodeTimeout:         http.StatusGatewayTimeout,
+	domain.CodeCanceled:        StatusClientClosedRequest,
+	domain.CodeUnauthenticated: http.StatusUnauthorized,
+	domain.CodeForbidden:       http.StatusForbidden,
+	domain.CodeRateLimited:     http.StatusTooManyRequests,
+	domain.CodeOverloaded:      http.StatusServiceUnavailable,
+	domain.CodeNotAcceptable:   http.StatusNotAcceptable,
+	domain.CodeInternal:        http.StatusInternalServerError,
+}
+
+// publicDetails заменяют текст ошибок, в которых могут быть подробности инфраструктуры.
+var publicDetails = map[domain.ErrorCode]string{
+	domain.CodeConflict:    domain.ErrConflict.Error(),
+	domain.CodeInternal:    domain.ErrInternalServer.Error(),
+	domain.CodeUnavailable: domain.ErrUnavailable.Error(),
+	domain.CodeTimeout:     domain.ErrTimeout.Error(),
+}
+
+// NewProblem описывает ошибку запроса r. Статус и код определяются по err через domain.CodeOf.
+func NewProblem(r *http.Request, err error) Problem {
+	code := domain.CodeOf(err)
+	status := problemStatus[code]
+
+	title := http.StatusText(status)
+	if status == StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+
+	detail, ok := publicDetails[code]
+	if !ok {
+		detail = err.Error()
+	}
+
+	return Problem{
+		Type:     problemTypePrefix + string(code),
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+}
+
+// writeProblem отправляет ошибку как application/problem+json. Для временных сбоев БД
+// добавляет Retry-After, если middleware не выставил его раньше.
+func writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+	problem.RequestID = w.Header().Get(HeaderRequestID)
+
+	header := w.Header()
+	if problem.Code.Transient() && header.Get("Retry-After") == "" {
+		header.Set("Retry-After", strconv.Itoa(retryAfterTransient))
+	}
+	header.Set("Content-Type", MediaTypeProblem)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem) //nolint:errcheck,gosec
+}
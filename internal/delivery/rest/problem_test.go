@@ -0,0 +1,106 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetOrderProblem(t *testing.T) {
+	tbl := []struct {
+		name               string
+		outputErr          error
+		expectedStatusCode int
+		expectedCode       domain.ErrorCode
+		expectedDetail     string
+		expectedRetryAfter string
+	}{
+		{
+			name:               "not_found",
+			outputErr:          fmt.Errorf("failed to get order: %w", domain.ErrOrderNotFound),
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       domain.CodeNotFound,
+			expectedDetail:     "failed to get order: order not found",
+		},
+		{
+			name:               "conflict",
+			outputErr:          fmt.Errorf("%w: ERROR: duplicate key value violates unique constraint \"orders_pkey\" (SQLSTATE 23505)", domain.ErrConflict),
+			expectedStatusCode: http.StatusConflict,
+			expectedCode:       domain.CodeConflict,
+			expectedDetail:     domain.ErrConflict.Error(),
+		},
+		{
+			name:               "unavailable",
+			outputErr:          fmt.Errorf("failed to select order row: %w: dial tcp 10.0.0.5:5432: connection refused", domain.ErrUnavailable),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedCode:       domain.CodeUnavailable,
+			expectedDetail:     domain.ErrUnavailable.Error(),
+			expectedRetryAfter: "2",
+		},
+		{
+			name:               "timeout",
+			outputErr:          fmt.Errorf("failed to select order row: %w", context.DeadlineExceeded),
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedCode:       domain.CodeTimeout,
+			expectedDetail:     domain.ErrTimeout.Error(),
+			expectedRetryAfter: "2",
+		},
+		{
+			name:               "internal",
+			outputErr:          errors.New("pq: relation \"orders\" does not exist"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       domain.CodeInternal,
+			expectedDetail:     domain.ErrInternalServer.Error(),
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockOrderService := mock.NewMockOrderService(ctrl)
+			mockOrderService.EXPECT().GetOrder(gomock.Any(), "uid").Return(nil, testCase.outputErr)
+			mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+			mockHTTPMetrics.EXPECT().IncRequest()
+			mockHTTPMetrics.EXPECT().ObserveRequest(gomock.Any())
+
+			handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/order/{order_uid}", handler.GetOrders())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/order/uid", nil)
+			req.Header.Set(rest.HeaderRequestID, "req-42")
+			respRec := httptest.NewRecorder()
+			rest.Chain(mux, rest.RequestID()).ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			require.Equal(t, rest.MediaTypeProblem, respRec.Header().Get("Content-Type"))
+			require.Equal(t, testCase.expectedRetryAfter, respRec.Header().Get("Retry-After"))
+
+			var problem rest.Problem
+			require.NoError(t, json.NewDecoder(respRec.Body).Decode(&problem))
+			require.Equal(t, rest.Problem{
+				Type:      "urn:order-service:problem:" + string(testCase.expectedCode),
+				Title:     http.StatusText(testCase.expectedStatusCode),
+				Status:    testCase.expectedStatusCode,
+				Detail:    testCase.expectedDetail,
+				Instance:  "/api/v1/order/uid",
+				Code:      testCase.expectedCode,
+				RequestID: "req-42",
+			}, problem)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"order_service/internal/domain"
+)
+
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit возвращает middleware, который ограничивает частоту запросов клиента:
+// аутентифицированного — по его API-ключу или субъекту JWT, остальных — по IP.
+// Поэтому в цепочке он должен стоять после RequireScope. Превышение лимита — 429
+// с Retry-After. Если limiter равен nil (лимит для маршрута не задан), запрос пропускается;
+// решение с Unlimited пропускает запрос без заголовков X-RateLimit-*.
+func RateLimit(limiter domain.RateLimiter, log *slog.Logger) Middleware {
+	return rateLimit(limiter, clientKey, log)
+}
+
+// RateLimitByIP возвращает middleware, который ограничивает частоту запросов с одного IP
+// независимо от учетных данных. Он ставится перед RequireScope, чтобы перебор API-ключей
+// и токенов тоже упирался в лимит, а не получал 401 без ограничений.
+func RateLimitByIP(limiter domain.RateLimiter, log *slog.Logger) Middleware {
+	return rateLimit(limiter, ipKey, log)
+}
+
+// rateLimit проверяет запрос по bucket'у с ключом key(r).
+func rateLimit(limiter domain.RateLimiter, key func(r *http.Request) string, log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := key(r)
+			decision := limiter.Allow(key)
+			if decision.Unlimited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(HeaderRateLimitLimit, strconv.Itoa(decision.Limit))
+			w.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(decision.Remaining))
+			w.Header().Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+
+			if !decision.Allowed {
+				log.DebugContext(r.Context(), "Rate limit exceeded", slog.String("client", key))
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+				writeProblem(w, r, domain.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ConcurrencyLimit возвращает middleware, который одновременно обслуживает не больше
+// limit запросов, а остальные сразу отклоняет с 503 — так сервис сбрасывает нагрузку,
+// а не выстраивает очередь к пулу соединений БД. При limit <= 0 ограничения нет.
+func ConcurrencyLimit(limit int, log *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+
+		slots := make(chan struct{}, limit)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, r)
+			default:
+				log.WarnContext(r.Context(), "Concurrency limit reached, shedding request", slog.Int("limit", limit))
+				w.Header().Set("Retry-After", "1")
+				writeProblem(w, r, domain.ErrOverloaded)
+			}
+		})
+	}
+}
+
+// clientKey возвращает ключ bucket'а клиента.
+func clientKey(r *http.Request) string {
+	if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.Subject
+	}
+	return ipKey(r)
+}
+
+// ipKey возвращает ключ bucket'а IP-адреса клиента.
+func ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд, как того требуют заголовки.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,173 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimit(t *testing.T) {
+	tbl := []struct {
+		name               string
+		principal          *domain.Principal
+		expectedKey        string
+		decision           domain.RateLimitDecision
+		expectedStatusCode int
+		expectedRetryAfter string
+	}{
+		{
+			name:               "allowed_by_ip",
+			expectedKey:        "ip:192.0.2.1",
+			decision:           domain.RateLimitDecision{Allowed: true, Limit: 20, Remaining: 19, ResetAfter: 100 * time.Millisecond},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "allowed_by_api_key",
+			principal:          &domain.Principal{Subject: "apikey:reader"},
+			expectedKey:        "principal:apikey:reader",
+			decision:           domain.RateLimitDecision{Allowed: true, Limit: 20, Remaining: 19, ResetAfter: 100 * time.Millisecond},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "limited",
+			expectedKey: "ip:192.0.2.1",
+			decision: domain.RateLimitDecision{
+				Limit: 20, RetryAfter: 1500 * time.Millisecond, ResetAfter: 2 * time.Second,
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedRetryAfter: "2",
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockLimiter := mock.NewMockRateLimiter(ctrl)
+			mockLimiter.EXPECT().Allow(testCase.expectedKey).Return(testCase.decision)
+
+			handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+				rest.RateLimit(mockLimiter, logger.Discard()))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/order/uid", nil)
+			req.RemoteAddr = "192.0.2.1:43210"
+			if testCase.principal != nil {
+				req = req.WithContext(domain.WithPrincipal(req.Context(), testCase.principal))
+			}
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, req)
+
+			require.Equal(t, testCase.expectedStatusCode, respRec.Code)
+			require.Equal(t, "20", respRec.Header().Get(rest.HeaderRateLimitLimit))
+			require.NotEmpty(t, respRec.Header().Get(rest.HeaderRateLimitRemaining))
+			require.NotEmpty(t, respRec.Header().Get(rest.HeaderRateLimitReset))
+			require.Equal(t, testCase.expectedRetryAfter, respRec.Header().Get("Retry-After"))
+
+			if testCase.expectedStatusCode == http.StatusTooManyRequests {
+				var problem rest.Problem
+				require.NoError(t, json.NewDecoder(respRec.Body).Decode(&problem))
+				require.Equal(t, domain.ErrRateLimited.Error(), problem.Detail)
+				require.Equal(t, domain.CodeRateLimited, problem.Code)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			rest.RateLimit(nil, logger.Discard()))
+
+		respRec := httptest.NewRecorder()
+		handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, respRec.Code)
+		require.Empty(t, respRec.Header().Get(rest.HeaderRateLimitLimit))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockLimiter := mock.NewMockRateLimiter(ctrl)
+		mockLimiter.EXPECT().Allow(gomock.Any()).Return(domain.RateLimitDecision{Allowed: true, Unlimited: true})
+
+		handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			rest.RateLimit(mockLimiter, logger.Discard()))
+
+		respRec := httptest.NewRecorder()
+		handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, respRec.Code)
+		require.Empty(t, respRec.Header().Get(rest.HeaderRateLimitLimit))
+	})
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLimiter := mock.NewMockRateLimiter(ctrl)
+	mockLimiter.EXPECT().Allow("ip:192.0.2.1").Return(domain.RateLimitDecision{
+		Limit: 100, RetryAfter: 300 * time.Millisecond, ResetAfter: time.Second,
+	})
+
+	called := false
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }),
+		rest.RateLimitByIP(mockLimiter, logger.Discard()))
+
+	// Даже у аутентифицированного клиента ключом остается IP
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/order/uid", nil)
+	req.RemoteAddr = "192.0.2.1:43210"
+	req = req.WithContext(domain.WithPrincipal(req.Context(), &domain.Principal{Subject: "apikey:reader"}))
+	respRec := httptest.NewRecorder()
+	handler.ServeHTTP(respRec, req)
+
+	require.Equal(t, http.StatusTooManyRequests, respRec.Code)
+	require.Equal(t, "1", respRec.Header().Get("Retry-After"))
+	require.False(t, called)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 2
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := rest.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}), rest.ConcurrencyLimit(limit, logger.Discard()))
+
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := range limit {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			respRec := httptest.NewRecorder()
+			handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+			codes[i] = respRec.Code
+		}()
+		<-started
+	}
+
+	// Все слоты заняты: следующий запрос сбрасывается сразу, без ожидания
+	respRec := httptest.NewRecorder()
+	handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusServiceUnavailable, respRec.Code)
+	require.Equal(t, "1", respRec.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, []int{http.StatusOK, http.StatusOK}, codes)
+
+	// После освобождения слотов запросы снова обслуживаются
+	go func() { <-started }()
+	respRec = httptest.NewRecorder()
+	handler.ServeHTTP(respRec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, respRec.Code)
+}
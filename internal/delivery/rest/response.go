@@ -6,6 +6,10 @@ type OrderResponse struct {
 	Order *domain.Order `json:"order"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
 }
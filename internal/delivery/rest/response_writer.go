@@ -0,0 +1,69 @@
+package rest
+
+import "net/http"
+
+// ResponseWriter запоминает статус и размер ответа, чтобы middleware
+// могли использовать их после вызова обработчика.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WrapResponseWriter оборачивает w. Если w уже обернут, возвращается он же,
+// поэтому несколько middleware в цепочке видят одно и то же состояние.
+func WrapResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+	return &ResponseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *ResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Flush нужен потоковым ответам; no-op, если нижний writer не умеет сбрасывать буфер.
+func (w *ResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного writer'а.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status возвращает записанный код ответа (200, если заголовок еще не отправлен).
+func (w *ResponseWriter) Status() int {
+	return w.status
+}
+
+// BytesWritten возвращает количество записанных байт тела ответа.
+func (w *ResponseWriter) BytesWritten() int {
+	return w.bytes
+}
+
+// WroteHeader сообщает, был ли уже отправлен заголовок ответа.
+func (w *ResponseWriter) WroteHeader() bool {
+	return w.wroteHeader
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"log/slog"
+	"net/http"
+
+	"order_service/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Limiters — limiter'ы частоты запросов маршрутов. Nil снимает ограничение.
+type Limiters struct {
+	Order domain.RateLimiter // маршруты заказов, по клиенту
+	Admin domain.RateLimiter // административные маршруты, по клиенту
+	IP    domain.RateLimiter // все защищенные маршруты, по IP до проверки учетных данных
+}
+
+// RegisterRoutes регистрирует в mux маршруты API, описанные в OpenAPI спецификации, вместе
+// с аутентификацией и лимитами. Таблица маршрутов одна для сервиса и контрактного теста.
+func RegisterRoutes(
+	mux *http.ServeMux, handler *Handler, adminHandler *AdminHandler, openAPI *OpenAPI,
+	authenticator domain.Authenticator, limiters Limiters, log *slog.Logger,
+) {
+	protect := func(h http.HandlerFunc, scope string, limiter domain.RateLimiter) http.Handler {
+		return Chain(h,
+			RateLimitByIP(limiters.IP, log),
+			RequireScope(authenticator, scope, log),
+			RateLimit(limiter, log),
+		)
+	}
+
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("GET /api/openapi.json", openAPI.Handler())
+	mux.Handle("GET /api/v1/order/{order_uid}", protect(handler.GetOrders(), domain.ScopeOrdersRead, limiters.Order))
+	mux.Handle("GET /api/v1/orders/stream", protect(handler.StreamOrders(), domain.ScopeOrdersRead, limiters.Order))
+	mux.Handle("GET /admin/log-level", protect(adminHandler.GetLogLevel(), domain.ScopeAdmin, limiters.Admin))
+	mux.Handle("PUT /admin/log-level", protect(adminHandler.SetLogLevel(), domain.ScopeAdmin, limiters.Admin))
+}
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"order_service/internal/domain"
+)
+
+const (
+	MediaTypeEventStream = "text/event-stream"
+
+	// eventReset сообщает клиенту, что часть событий пропущена и состояние нужно перечитать.
+	eventReset = "reset"
+	// streamRetry — через сколько браузер переподключится после обрыва.
+	streamRetry = 3 * time.Second
+	// streamHeartbeat — интервал комментариев-пингов, чтобы прокси не закрывали простаивающее соединение.
+	streamHeartbeat = 15 * time.Second
+)
+
+// StreamOrders возвращает обработчик Server-Sent Events с событиями сохраненных заказов.
+// Фильтры customer_id и delivery_service, маскирование — как у GetOrders. Клиент продолжает
+// поток после Last-Event-ID (или параметра last_event_id); если события уже вытеснены
+// из журнала, первым приходит событие reset, дальше — новые события.
+func (h *Handler) StreamOrders() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+		customerID, deliveryService := query.Get("customer_id"), query.Get("delivery_service")
+
+		redact, err := shouldRedact(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		afterID, err := lastEventID(r)
+		if err != nil {
+			writeProblem(w, r, err)
+			return
+		}
+
+		events, err := h.service.WatchOrderEvents(ctx, afterID)
+		reset := errors.Is(err, domain.ErrEventsExpired)
+		if reset {
+			events, err = h.service.WatchOrderEvents(ctx, 0)
+		}
+		if err != nil {
+			if problemStatus[domain.CodeOf(err)] >= http.StatusInternalServerError {
+				h.log.ErrorContext(ctx, "Failed to subscribe to order events", slog.Any("error", err))
+			}
+			writeProblem(w, r, err)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		// Поток живет дольше WriteTimeout сервера
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			h.log.WarnContext(ctx, "Failed to disable write deadline", slog.Any("error", err))
+		}
+
+		header := w.Header()
+		header.Set("Content-Type", MediaTypeEventStream)
+		header.Set("Cache-Control", "no-cache")
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()) //nolint:errcheck
+		if reset {
+			fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventReset) //nolint:errcheck
+		}
+		if err := rc.Flush(); err != nil {
+			h.log.ErrorContext(ctx, "Streaming is not supported", slog.Any("error", err))
+			return
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.streamsDone:
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			case event, ok := <-events:
+				if !ok {
+					// Клиент не успевал читать: он переподключится с Last-Event-ID и догонит по журналу
+					h.log.WarnContext(ctx, "Order event subscriber dropped: client is too slow")
+					return
+				}
+				if customerID != "" && event.Order.CustomerID != customerID ||
+					deliveryService != "" && event.Order.DeliveryService != deliveryService {
+					continue
+				}
+
+				order := event.Order
+				if redact {
+					order = order.Redacted()
+				}
+				data, err := json.Marshal(OrderResponse{Order: order})
+				if err != nil {
+					h.log.ErrorContext(ctx, "Failed to encode order event", slog.Any("error", err))
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+					return
+				}
+			}
+			rc.Flush() //nolint:errcheck,gosec
+		}
+	}
+}
+
+// lastEventID возвращает ID, после которого клиент хочет продолжить поток: заголовок
+// Last-Event-ID выставляет браузер при переподключении, параметр нужен клиентам,
+// которые не могут задать заголовок. 0 — только новые события.
+func lastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, domain.ErrInvalidEventID
+	}
+	return id, nil
+}
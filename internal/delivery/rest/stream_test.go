@@ -0,0 +1,184 @@
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"order_service/internal/delivery/rest"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// sseEvent — разобранное событие потока без комментариев.
+type sseEvent struct {
+	id, event, data string
+}
+
+// readEvent читает поток до следующего события, пропуская поле retry и комментарии.
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		if line == "" {
+			if event != (sseEvent{}) {
+				return event
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.event = value
+		case "data":
+			event.data = value
+		}
+	}
+}
+
+func TestStreamOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderService := mock.NewMockOrderService(ctrl)
+	mockHTTPMetrics := mock.NewMockHTTPMetrics(ctrl)
+
+	handler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+	server := httptest.NewServer(http.HandlerFunc(handler.StreamOrders()))
+	t.Cleanup(server.Close)
+
+	open := func(t *testing.T, query string, headers map[string]string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+query, nil)
+		require.NoError(t, err)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	other := *validOrder
+	other.OrderUID = "other"
+	other.CustomerID = "someone-else"
+
+	t.Run("filters_and_redaction", func(t *testing.T) {
+		events := make(chan domain.OrderEvent, 2)
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).Return(events, nil)
+
+		resp := open(t, "?customer_id="+validOrder.CustomerID+"&delivery_service="+validOrder.DeliveryService+"&redact=true", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, rest.MediaTypeEventStream, resp.Header.Get("Content-Type"))
+		require.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+		events <- domain.OrderEvent{ID: 41, Type: domain.EventOrderCreated, Order: &other}
+		events <- domain.OrderEvent{ID: 42, Type: domain.EventOrderCreated, Order: validOrder}
+
+		event := readEvent(t, bufio.NewReader(resp.Body))
+		require.Equal(t, "42", event.id)
+		require.Equal(t, domain.EventOrderCreated, event.event)
+
+		var orderResp rest.OrderResponse
+		require.NoError(t, json.Unmarshal([]byte(event.data), &orderResp))
+		require.Equal(t, validOrder.Redacted(), orderResp.Order)
+	})
+
+	t.Run("resume_after_last_event_id", func(t *testing.T) {
+		events := make(chan domain.OrderEvent, 1)
+		events <- domain.OrderEvent{ID: 8, Type: domain.EventOrderCreated, Order: validOrder}
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(7)).Return(events, nil)
+
+		resp := open(t, "", map[string]string{"Last-Event-ID": "7"})
+		require.Equal(t, "8", readEvent(t, bufio.NewReader(resp.Body)).id)
+	})
+
+	t.Run("resume_by_query_parameter", func(t *testing.T) {
+		events := make(chan domain.OrderEvent, 1)
+		events <- domain.OrderEvent{ID: 8, Type: domain.EventOrderCreated, Order: validOrder}
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(7)).Return(events, nil)
+
+		resp := open(t, "?last_event_id=7", nil)
+		require.Equal(t, "8", readEvent(t, bufio.NewReader(resp.Body)).id)
+	})
+
+	t.Run("expired_events_reset", func(t *testing.T) {
+		events := make(chan domain.OrderEvent, 1)
+		events <- domain.OrderEvent{ID: 900, Type: domain.EventOrderCreated, Order: validOrder}
+		gomock.InOrder(
+			mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(3)).Return(nil, domain.ErrEventsExpired),
+			mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).Return(events, nil),
+		)
+
+		resp := open(t, "", map[string]string{"Last-Event-ID": "3"})
+		body := bufio.NewReader(resp.Body)
+		require.Equal(t, "reset", readEvent(t, body).event)
+		require.Equal(t, "900", readEvent(t, body).id)
+	})
+
+	t.Run("invalid_last_event_id", func(t *testing.T) {
+		resp := open(t, "?last_event_id=-1", nil)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, rest.MediaTypeProblem, resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("slow_subscriber_dropped", func(t *testing.T) {
+		events := make(chan domain.OrderEvent)
+		close(events)
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).Return(events, nil)
+
+		resp := open(t, "", nil)
+		_, err := bufio.NewReader(resp.Body).ReadString(0)
+		require.Error(t, err, "stream must end when the subscription is dropped")
+	})
+
+	t.Run("stop_streams_on_shutdown", func(t *testing.T) {
+		stopHandler := rest.NewHandler(mockOrderService, mockHTTPMetrics, logger.Discard())
+		stopServer := httptest.NewServer(http.HandlerFunc(stopHandler.StreamOrders()))
+		defer stopServer.Close()
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).Return(make(chan domain.OrderEvent), nil)
+
+		resp, err := http.Get(stopServer.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		stopHandler.StopStreams()
+		stopHandler.StopStreams()
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err, "stream must end cleanly")
+	})
+
+	t.Run("client_disconnect_ends_subscription", func(t *testing.T) {
+		subscribed := make(chan context.Context, 1)
+		mockOrderService.EXPECT().WatchOrderEvents(gomock.Any(), uint64(0)).DoAndReturn(
+			func(ctx context.Context, _ uint64) (<-chan domain.OrderEvent, error) {
+				subscribed <- ctx
+				return make(chan domain.OrderEvent), nil
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		subscriptionCtx := <-subscribed
+		cancel()
+		<-subscriptionCtx.Done()
+	})
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// Скоупы доступа к API
+const (
+	ScopeOrdersRead  = "orders:read"
+	ScopeOrdersWrite = "orders:write"
+	ScopeAdmin       = "admin"
+
+	// ScopePIIRead разрешает получать персональные данные получателя без маскирования.
+	ScopePIIRead = "orders:pii"
+)
+
+// Principal — аутентифицированный клиент API и выданные ему скоупы.
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope сообщает, есть ли у клиента скоуп. Скоуп admin включает все остальные.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+type Authenticator interface {
+	AuthenticateAPIKey(key string) (*Principal, error)
+	AuthenticateToken(token string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal кладет аутентифицированного клиента в контекст запроса.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext возвращает клиента из контекста, если запрос был аутентифицирован.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,14 @@
+package domain
+
+// FieldCipher шифрует отдельные поля заказа перед записью в БД.
+// aad привязывает шифротекст к строке и колонке, чтобы его нельзя было
+// перенести в другую запись.
+type FieldCipher interface {
+	Encrypt(plaintext, aad string) (string, error)
+	Decrypt(value, aad string) (string, error)
+	// NeedsReencrypt сообщает, что значение хранится открытым текстом
+	// или зашифровано не текущим основным ключом.
+	NeedsReencrypt(value string) bool
+	// BlindIndex возвращает детерминированный HMAC значения для поиска на равенство.
+	BlindIndex(value string) string
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorCode — машиночитаемый класс ошибки, общий для REST и gRPC API.
+type ErrorCode string
+
+const (
+	CodeNotFound        ErrorCode = "not_found"
+	CodeValidation      ErrorCode = "validation_failed"
+	CodeConflict        ErrorCode = "conflict"
+	CodeUnavailable     ErrorCode = "unavailable"
+	CodeTimeout         ErrorCode = "timeout"
+	CodeCanceled        ErrorCode = "canceled"
+	CodeUnauthenticated ErrorCode = "unauthenticated"
+	CodeForbidden       ErrorCode = "forbidden"
+	CodeRateLimited     ErrorCode = "rate_limited"
+	CodeOverloaded      ErrorCode = "overloaded"
+	CodeNotAcceptable   ErrorCode = "not_acceptable"
+	CodeInternal        ErrorCode = "internal"
+)
+
+// errorCodes проверяются по порядку: первая подходящая ошибка определяет код.
+var errorCodes = []struct {
+	target error
+	code   ErrorCode
+}{
+	{ErrOrderNotFound, CodeNotFound},
+	{ErrOrdersNotFound, CodeNotFound},
+	{ErrInvalidRequest, CodeValidation},
+	{ErrInvalidRedactParam, CodeValidation},
+	{ErrInvalidEventID, CodeValidation},
+	{ErrConflict, CodeConflict},
+	{ErrUnavailable, CodeUnavailable},
+	{ErrTimeout, CodeTimeout},
+	{context.DeadlineExceeded, CodeTimeout},
+	{context.Canceled, CodeCanceled},
+	{ErrUnauthenticated, CodeUnauthenticated},
+	{ErrForbidden, CodeForbidden},
+	{ErrRateLimited, CodeRateLimited},
+	{ErrOverloaded, CodeOverloaded},
+	{ErrNotAcceptable, CodeNotAcceptable},
+}
+
+// CodeOf возвращает класс ошибки. Нарушения правил ValidateOrder — CodeValidation,
+// неизвестные ошибки — CodeInternal.
+func CodeOf(err error) ErrorCode {
+	if IsValidationError(err) {
+		return CodeValidation
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.target) {
+			return c.code
+		}
+	}
+	return CodeInternal
+}
+
+// Transient сообщает, что запрос с этой ошибкой имеет смысл повторить позже.
+func (c ErrorCode) Transient() bool {
+	switch c {
+	case CodeUnavailable, CodeTimeout, CodeRateLimited, CodeOverloaded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOf(t *testing.T) {
+	tbl := []struct {
+		err               error
+		expectedCode      domain.ErrorCode
+		expectedTransient bool
+	}{
+		{err: fmt.Errorf("failed to get order: %w", domain.ErrOrderNotFound), expectedCode: domain.CodeNotFound},
+		{err: domain.ErrNoItems, expectedCode: domain.CodeValidation},
+		{err: fmt.Errorf("%w: bad body", domain.ErrInvalidRequest), expectedCode: domain.CodeValidation},
+		{err: domain.ErrConflict, expectedCode: domain.CodeConflict},
+		{err: fmt.Errorf("failed to begin transaction: %w", domain.ErrUnavailable), expectedCode: domain.CodeUnavailable, expectedTransient: true},
+		{err: fmt.Errorf("failed to select: %w", context.DeadlineExceeded), expectedCode: domain.CodeTimeout, expectedTransient: true},
+		{err: context.Canceled, expectedCode: domain.CodeCanceled},
+		{err: domain.ErrRateLimited, expectedCode: domain.CodeRateLimited, expectedTransient: true},
+		{err: errors.New("unexpected"), expectedCode: domain.CodeInternal},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(string(testCase.expectedCode), func(t *testing.T) {
+			code := domain.CodeOf(testCase.err)
+			require.Equal(t, testCase.expectedCode, code)
+			require.Equal(t, testCase.expectedTransient, code.Transient())
+		})
+	}
+}
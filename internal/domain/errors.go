@@ -7,10 +7,42 @@ var (
 
 	ErrOrderNotFound  = errors.New("order not found")
 	ErrOrdersNotFound = errors.New("orders not found")
+	ErrConflict       = errors.New("order conflicts with stored data")
+	ErrUnavailable    = errors.New("storage is temporarily unavailable")
 
 	// http errors
 
-	ErrInternalServer = errors.New("internal server error")
+	ErrInternalServer     = errors.New("internal server error")
+	ErrInvalidRequest     = errors.New("invalid request")
+	ErrTimeout            = errors.New("request timed out")
+	ErrInvalidRedactParam = errors.New("redact must be a boolean")
+	ErrRateLimited        = errors.New("rate limit exceeded")
+	ErrNotAcceptable      = errors.New("none of the accepted media types is supported")
+	ErrOverloaded         = errors.New("server is overloaded, retry later")
+	ErrInvalidEventID     = errors.New("last event id must be a positive integer")
+
+	// Event stream errors
+
+	ErrEventsExpired = errors.New("requested events are no longer retained")
+
+	// Message schema errors
+
+	ErrUnknownSchema          = errors.New("unknown message schema")
+	ErrUnsupportedContentType = errors.New("unsupported message content type")
+	ErrIncompatibleSchema     = errors.New("schema is incompatible with the previous version")
+
+	// Strict decoding errors
+
+	ErrUnknownField     = errors.New("unknown field")
+	ErrMissingField     = errors.New("required field is missing")
+	ErrInvalidFieldType = errors.New("invalid field type")
+	ErrPayloadTooLarge  = errors.New("message payload is too large")
+	ErrTooManyItems     = errors.New("order has too many items")
+
+	// Auth errors
+
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("insufficient scope")
 
 	// Validation errors - business rules
 
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Типы событий потока заказов. Заказ после сохранения не меняется, поэтому пока
+// событие одно; смена статуса добавится отдельным типом.
+const EventOrderCreated = "order.created"
+
+// OrderEvent — запись журнала событий заказов. ID монотонно растут в пределах процесса
+// и служат точкой возобновления подписки.
+type OrderEvent struct {
+	ID    uint64
+	Type  string
+	Order *Order
+	Time  time.Time
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// OrderMessage — заказ, прочитанный из Kafka, вместе с позицией и исходным содержимым сообщения.
+type OrderMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []MessageHeader
+	Order     *Order
+}
+
+// MessageHeader — заголовок сообщения Kafka.
+type MessageHeader struct {
+	Key   string
+	Value []byte
+}
+
+// MessageOutcome — чем закончилась обработка сообщения с заказом.
+type MessageOutcome string
+
+const (
+	// OutcomeNew — сообщение и заказ раньше не встречались.
+	OutcomeNew MessageOutcome = ""
+	// OutcomeSaved — заказ сохранен.
+	OutcomeSaved MessageOutcome = "saved"
+	// OutcomeDuplicate — то же сообщение или тот же заказ с тем же содержимым уже обработан.
+	OutcomeDuplicate MessageOutcome = "duplicate"
+	// OutcomeConflict — заказ с тем же order_uid уже сохранен с другим содержимым.
+	OutcomeConflict MessageOutcome = "conflict"
+	// OutcomeFailed — заказ не сохранить повторами, сообщение отправлено в DLQ. В журнал не записывается.
+	OutcomeFailed MessageOutcome = "failed"
+)
+
+// ProcessedMessage — запись журнала обработанных сообщений.
+type ProcessedMessage struct {
+	Topic       string
+	Partition   int
+	Offset      int64
+	OrderUID    string
+	ContentHash string
+}
+
+// NewProcessedMessage готовит запись журнала для сообщения.
+func NewProcessedMessage(msg *OrderMessage) (ProcessedMessage, error) {
+	hash, err := ContentHash(msg.Order)
+	if err != nil {
+		return ProcessedMessage{}, err
+	}
+	return ProcessedMessage{
+		Topic:       msg.Topic,
+		Partition:   msg.Partition,
+		Offset:      msg.Offset,
+		OrderUID:    msg.Order.OrderUID,
+		ContentHash: hash,
+	}, nil
+}
+
+// ContentHash возвращает SHA-256 заказа в каноническом JSON: пробелы и порядок полей
+// исходного сообщения на хеш не влияют.
+func ContentHash(order *Order) (string, error) {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MessageLedger — журнал обработанных сообщений для дедупликации повторных отправок.
+type MessageLedger interface {
+	// CheckMessage сообщает, обрабатывалось ли сообщение с той же позицией (OutcomeDuplicate)
+	// и сохранен ли заказ с тем же order_uid с тем же (OutcomeDuplicate) или другим
+	// (OutcomeConflict) хешем. OutcomeNew — сообщение нужно обработать.
+	CheckMessage(ctx context.Context, msg ProcessedMessage) (MessageOutcome, error)
+	// RecordMessage записывает итог обработки. Повторная запись той же позиции игнорируется.
+	// Если заказ с тем же order_uid уже записан как сохраненный, возвращает ErrConflict.
+	RecordMessage(ctx context.Context, msg ProcessedMessage, outcome MessageOutcome) error
+	// CleanupMessages удаляет записи, сделанные раньше before, и возвращает их число.
+	CleanupMessages(ctx context.Context, before time.Time) (int, error)
+}
+
+// DeadLetter — сообщение, которое нельзя обработать автоматически, с причиной отказа.
+type DeadLetter struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []MessageHeader // заголовки исходного сообщения
+	Reason    string
+	Detail    string
+}
+
+// Причины отправки сообщения в DLQ.
+const (
+	DeadLetterConflict = "conflict"
+	DeadLetterFailed   = "failed"
+)
+
+// DeadLetterQueue сохраняет сообщения для ручного разбора и повторной отправки.
+type DeadLetterQueue interface {
+	Send(ctx context.Context, letter DeadLetter) error
+}
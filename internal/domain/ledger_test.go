@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"testing"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHash(t *testing.T) {
+	first, err := domain.ContentHash(&domain.Order{OrderUID: "order_1", TrackNumber: "A"})
+	require.NoError(t, err)
+	same, err := domain.ContentHash(&domain.Order{OrderUID: "order_1", TrackNumber: "A"})
+	require.NoError(t, err)
+	changed, err := domain.ContentHash(&domain.Order{OrderUID: "order_1", TrackNumber: "B"})
+	require.NoError(t, err)
+
+	require.Len(t, first, 64)
+	require.Equal(t, first, same)
+	require.NotEqual(t, first, changed)
+}
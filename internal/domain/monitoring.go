@@ -6,3 +6,44 @@ type HTTPMetrics interface {
 	IncRequest()
 	ObserveRequest(start time.Time)
 }
+
+// GRPCMetrics собирает количество и время обработки gRPC вызовов по методам и кодам ответа.
+type GRPCMetrics interface {
+	ObserveRPC(method, code string, start time.Time)
+}
+
+// ConsumerMetrics собирает метрики чтения заказов из Kafka и бизнес-метрики по принятым заказам.
+type ConsumerMetrics interface {
+	IncConsumed()
+	IncDecodeFailure()
+	IncValidationFailure(rule string)
+	IncDuplicate()
+	IncConflict()
+	ObserveSave(start time.Time)
+	ObserveCommit(start time.Time)
+	SetLag(partition int, lag int64)
+	SetReaderLag(lag int64)
+	IncOrder(order *Order)
+}
+
+// RepositoryMetrics собирает время выполнения SQL-запросов по их именам.
+type RepositoryMetrics interface {
+	ObserveQuery(query string, start time.Time)
+}
+
+// CacheMetrics собирает статистику работы кеша заказов.
+type CacheMetrics interface {
+	IncHit()
+	IncMiss()
+	IncEviction()
+	IncExpiration()
+	SetSize(size int)
+}
+
+// OutboxMetrics собирает метрики публикации событий outbox.
+type OutboxMetrics interface {
+	AddPublished(n int)
+	IncPublishFailure()
+	AddDead(n int)
+	AddCleaned(n int)
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OutboxEvent — событие для других сервисов, записанное в outbox в одной транзакции с заказом.
+// Key (order_uid) становится ключом сообщения Kafka, поэтому события одного заказа
+// попадают в одну партицию и читаются по порядку.
+type OutboxEvent struct {
+	ID        int64
+	Type      string
+	Key       string
+	Payload   []byte
+	Headers   map[string]string
+	CreatedAt time.Time
+	Attempts  int
+}
+
+// OrderCreatedPayload — тело события order.created. Персональные данные получателя
+// замаскированы: outbox и топик не шифруются, полный заказ доступен через API.
+type OrderCreatedPayload struct {
+	Type       string    `json:"type"`
+	OrderUID   string    `json:"order_uid"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      *Order    `json:"order"`
+}
+
+// OutboxFailure — неудачная попытка опубликовать событие outbox.
+type OutboxFailure struct {
+	ID         int64
+	Error      string
+	RetryAfter time.Duration // через сколько событие снова выдается relay'ю
+	Dead       bool          // попытки исчерпаны: событие переводится в failed и больше не публикуется
+}
+
+// PublishErrors — ошибки публикации отдельных событий пачки по их ID. События, которых
+// нет в map, опубликованы.
+type PublishErrors map[int64]error
+
+func (e PublishErrors) Error() string {
+	first := int64(0)
+	for id := range e {
+		if first == 0 || id < first {
+			first = id
+		}
+	}
+	return fmt.Sprintf("failed to publish %d events, event %d: %v", len(e), first, e[first])
+}
+
+// OutboxRepository выдает неопубликованные события relay'ю, записывает результат публикации
+// и удаляет доставленные события.
+type OutboxRepository interface {
+	// ClaimOutbox забирает до limit неопубликованных событий в порядке записи и скрывает их
+	// от других relay на время lease. Событие не выдается, пока не опубликовано более раннее
+	// событие с тем же ключом, поэтому события одного заказа не обгоняют друг друга.
+	// События в состоянии failed не выдаются и не задерживают следующие.
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]OutboxEvent, error)
+	// CompleteOutbox в одной транзакции помечает события published опубликованными
+	// и записывает неудачные попытки failed.
+	CompleteOutbox(ctx context.Context, published []int64, failed []OutboxFailure) error
+	// CleanupOutbox удаляет события, опубликованные раньше before.
+	CleanupOutbox(ctx context.Context, before time.Time) (int, error)
+}
+
+// EventPublisher отправляет события outbox во внешний брокер.
+type EventPublisher interface {
+	// Publish отправляет события. Если часть событий не отправлена, возвращает PublishErrors
+	// с их ошибками; любая другая ошибка относится ко всей пачке.
+	Publish(ctx context.Context, events []OutboxEvent) error
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// RateLimitDecision — результат проверки лимита запросов для одного клиента.
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int           // емкость bucket'а (максимальный всплеск запросов)
+	Remaining  int           // сколько запросов еще можно сделать сразу
+	RetryAfter time.Duration // через сколько появится следующий токен, если запрос отклонен
+	ResetAfter time.Duration // через сколько bucket наполнится полностью
+	Unlimited  bool          // лимит не задан, запрос пропущен без проверки
+}
+
+type RateLimiter interface {
+	Allow(key string) RateLimitDecision
+}
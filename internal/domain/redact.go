@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted возвращает копию заказа с замаскированными персональными данными получателя.
+func (o *Order) Redacted() *Order {
+	redacted := *o
+	redacted.Delivery = o.Delivery.Redacted()
+	return &redacted
+}
+
+// Redacted возвращает копию данных доставки с замаскированными именем, телефоном, адресом и email.
+func (d Delivery) Redacted() Delivery {
+	d.Name = MaskWords(d.Name)
+	d.Phone = MaskPhone(d.Phone)
+	d.Address = MaskWords(d.Address)
+	d.Email = MaskEmail(d.Email)
+	return d
+}
+
+// LogValue не дает персональным данным попасть в логи: заказ всегда логируется замаскированным.
+// Метод объявлен на значении, чтобы маскировались и *Order, и Order: у значения метода
+// с получателем-указателем нет, а LogValue из Delivery затеняется этим методом.
+func (o Order) LogValue() slog.Value {
+	r := o.Redacted()
+	return slog.GroupValue(
+		slog.String("order_uid", r.OrderUID),
+		slog.String("track_number", r.TrackNumber),
+		slog.String("customer_id", r.CustomerID),
+		slog.String("delivery_service", r.DeliveryService),
+		slog.Any("delivery", r.Delivery),
+		slog.String("currency", r.Currency),
+		slog.Int("amount", r.Amount),
+		slog.Int("items", len(r.Items)),
+	)
+}
+
+// LogValue логирует данные доставки только в замаскированном виде.
+func (d Delivery) LogValue() slog.Value {
+	r := d.Redacted()
+	return slog.GroupValue(
+		slog.String("name", r.Name),
+		slog.String("phone", r.Phone),
+		slog.String("zip", r.Zip),
+		slog.String("city", r.City),
+		slog.String("address", r.Address),
+		slog.String("region", r.Region),
+		slog.String("email", r.Email),
+	)
+}
+
+// MaskPhone оставляет код страны (первые 4 символа) и 2 последние цифры: +972*****00.
+func MaskPhone(phone string) string {
+	runes := []rune(phone)
+	switch {
+	case len(runes) == 0:
+		return ""
+	case len(runes) <= 6:
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", len(runes)-6) + string(runes[len(runes)-2:])
+}
+
+// MaskEmail оставляет первый символ имени и домен: t***@gmail.com.
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return MaskWords(email)
+	}
+	if local == "" {
+		return "***@" + domain
+	}
+	first, _ := utf8.DecodeRuneInString(local)
+	return string(first) + "***@" + domain
+}
+
+// MaskWords оставляет первый символ каждого слова: Test Testov → T*** T*****.
+func MaskWords(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMask(t *testing.T) {
+	require.Equal(t, "+972*****00", domain.MaskPhone("+9720000000"))
+	require.Equal(t, "****", domain.MaskPhone("1234"))
+	require.Equal(t, "", domain.MaskPhone(""))
+
+	require.Equal(t, "t***@gmail.com", domain.MaskEmail("test@gmail.com"))
+	require.Equal(t, "***@gmail.com", domain.MaskEmail("@gmail.com"))
+	require.Equal(t, "n*******", domain.MaskEmail("no-email"))
+
+	require.Equal(t, "T*** T*****", domain.MaskWords("Test Testov"))
+	require.Equal(t, "И*** П*****", domain.MaskWords("Иван  Петров"))
+	require.Equal(t, "", domain.MaskWords(""))
+}
+
+func TestRedacted(t *testing.T) {
+	order := &domain.Order{
+		OrderUID: "uid",
+		Delivery: domain.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+	}
+
+	redacted := order.Redacted()
+	require.Equal(t, domain.Delivery{
+		Name:    "T*** T*****",
+		Phone:   "+972*****00",
+		Zip:     "2639809",
+		City:    "Kiryat Mozkin",
+		Address: "P****** M*** 1*",
+		Region:  "Kraiot",
+		Email:   "t***@gmail.com",
+	}, redacted.Delivery)
+	require.Equal(t, "Test Testov", order.Delivery.Name, "original order must not change")
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	log.Info("order", slog.Any("order", order), slog.Any("delivery", order.Delivery))
+	require.NotContains(t, buf.String(), "+9720000000")
+	require.NotContains(t, buf.String(), "test@gmail.com")
+	require.Contains(t, buf.String(), "T*** T*****")
+
+	t.Run("order_value", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(slog.NewJSONHandler(&buf, nil))
+		log.Info("order", slog.Any("order", *order))
+		require.NotContains(t, buf.String(), "Test Testov")
+		require.NotContains(t, buf.String(), "+9720000000")
+		require.NotContains(t, buf.String(), "test@gmail.com")
+		require.NotContains(t, buf.String(), "Ploshad Mira 15")
+		require.Contains(t, buf.String(), `"order_uid":"uid"`)
+		require.Contains(t, buf.String(), "T*** T*****")
+	})
+}
@@ -7,5 +7,8 @@ import (
 type OrderRepository interface {
 	GetOrder(ctx context.Context, orderUID string) (*Order, error)
 	GetOrders(ctx context.Context, quantity int) ([]*Order, error)
-	SaveOrder(ctx context.Context, order *Order) error
+	// SaveOrder сохраняет заказ и сообщает, был ли он вставлен: заказ с уже сохраненным
+	// order_uid не перезаписывается, и inserted равен false.
+	SaveOrder(ctx context.Context, order *Order) (inserted bool, err error)
+	FindOrdersByEmail(ctx context.Context, email string) ([]*Order, error)
 }
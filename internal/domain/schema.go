@@ -0,0 +1,51 @@
+package domain
+
+import "strings"
+
+// OrderDecoder декодирует тело сообщения с заказом по типу содержимого и идентификатору
+// схемы из заголовков сообщения. Пустые заголовки означают JSON без версии.
+type OrderDecoder interface {
+	DecodeOrder(contentType, schemaID string, data []byte) (*Order, error)
+}
+
+// FieldError — ошибка в поле сообщения с путем до поля, например items[1].price.
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors — все ошибки в полях отклоненного сообщения.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fieldErr := range e {
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
+
+// Paths возвращает пути полей с ошибками.
+func (e FieldErrors) Paths() []string {
+	paths := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		paths = append(paths, fieldErr.Path)
+	}
+	return paths
+}
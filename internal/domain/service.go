@@ -6,5 +6,14 @@ import (
 
 type OrderService interface {
 	GetOrder(ctx context.Context, orderUID string) (*Order, error)
-	SaveOrder(ctx context.Context, order *Order) error
+	// SaveOrder сохраняет заказ. Заказ с уже сохраненным order_uid не перезаписывается,
+	// и inserted равен false.
+	SaveOrder(ctx context.Context, order *Order) (inserted bool, err error)
+	ListOrders(ctx context.Context, limit int) ([]*Order, error)
+	FindOrdersByEmail(ctx context.Context, email string) ([]*Order, error)
+	// WatchOrderEvents возвращает канал событий с ID больше afterID: сначала из журнала,
+	// затем новые. afterID 0 — только новые события. Если часть событий после afterID уже
+	// вытеснена из журнала, возвращает ErrEventsExpired. Канал закрывается при отмене ctx
+	// или если подписчик не успевает читать — тогда ctx.Err() равен nil.
+	WatchOrderEvents(ctx context.Context, afterID uint64) (<-chan OrderEvent, error)
 }
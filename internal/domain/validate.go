@@ -1,5 +1,19 @@
 package domain
 
+import "errors"
+
+// validationRules сопоставляет ошибки валидации с короткими именами правил для меток метрик.
+var validationRules = map[error]string{
+	ErrOrderUIDRequired:     "order_uid_required",
+	ErrCustomerIDRequired:   "customer_id_required",
+	ErrTrackNumberRequired:  "track_number_required",
+	ErrTransactionRequired:  "transaction_required",
+	ErrInvalidPaymentAmount: "payment_amount_positive",
+	ErrNoItems:              "items_required",
+	ErrInvalidItemID:        "item_chrt_id_positive",
+	ErrInvalidItemPrice:     "item_price_positive",
+}
+
 // ValidateOrder проверяет корректность данных заказа
 func ValidateOrder(order *Order) error {
 	if order.OrderUID == "" {
@@ -32,3 +46,18 @@ func ValidateOrder(order *Order) error {
 
 	return nil
 }
+
+// ValidationRule возвращает имя нарушенного правила валидации или "unknown".
+func ValidationRule(err error) string {
+	for target, rule := range validationRules {
+		if errors.Is(err, target) {
+			return rule
+		}
+	}
+	return "unknown"
+}
+
+// IsValidationError сообщает, что err — нарушение одного из правил ValidateOrder.
+func IsValidationError(err error) bool {
+	return ValidationRule(err) != "unknown"
+}
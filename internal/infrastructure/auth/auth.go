@@ -0,0 +1,114 @@
+// Package auth аутентифицирует клиентов API по статическим API-ключам
+// (в конфигурации хранятся только их SHA-256 хеши) и по JWT (HS256/RS256),
+// ключи проверки подписи которых берутся из локального JWKS файла.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"order_service/config"
+	"order_service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type apiKey struct {
+	name   string
+	hash   []byte
+	scopes []string
+}
+
+type Authenticator struct {
+	apiKeys []apiKey
+	keys    *KeySet
+	parser  *jwt.Parser
+}
+
+// NewAuthenticator создает аутентификатор по секции auth конфигурации.
+// JWT принимаются, только если задан jwks_file.
+func NewAuthenticator(cfg *config.Config) (*Authenticator, error) {
+	a := &Authenticator{}
+
+	for _, key := range cfg.Auth.APIKeys {
+		hash, err := hex.DecodeString(strings.TrimPrefix(key.Hash, "sha256:"))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("api key %q: hash must be a hex-encoded sha256 digest", key.Name)
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{name: key.Name, hash: hash, scopes: key.Scopes})
+	}
+
+	if cfg.Auth.JWT.JWKSFile != "" {
+		keys, err := LoadKeySet(cfg.Auth.JWT.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwks: %w", err)
+		}
+		a.keys = keys
+
+		opts := []jwt.ParserOption{
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+			jwt.WithExpirationRequired(),
+		}
+		if cfg.Auth.JWT.Issuer != "" {
+			opts = append(opts, jwt.WithIssuer(cfg.Auth.JWT.Issuer))
+		}
+		if cfg.Auth.JWT.Audience != "" {
+			opts = append(opts, jwt.WithAudience(cfg.Auth.JWT.Audience))
+		}
+		a.parser = jwt.NewParser(opts...)
+	}
+
+	return a, nil
+}
+
+// HashAPIKey возвращает хеш API-ключа в формате, который ожидается в конфигурации.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthenticateAPIKey ищет ключ среди настроенных, сравнивая хеши за постоянное время.
+func (a *Authenticator) AuthenticateAPIKey(key string) (*domain.Principal, error) {
+	sum := sha256.Sum256([]byte(key))
+
+	var found *apiKey
+	for i := range a.apiKeys {
+		if subtle.ConstantTimeCompare(sum[:], a.apiKeys[i].hash) == 1 {
+			found = &a.apiKeys[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown api key: %w", domain.ErrUnauthenticated)
+	}
+
+	return &domain.Principal{Subject: "apikey:" + found.name, Scopes: found.scopes}, nil
+}
+
+// claims — JWT claims со скоупами в формате OAuth2 ("scope": "a b") или списком ("scopes": ["a", "b"]).
+type claims struct {
+	jwt.RegisteredClaims
+	Scope  string   `json:"scope,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// AuthenticateToken проверяет подпись и срок действия JWT и возвращает его владельца.
+func (a *Authenticator) AuthenticateToken(token string) (*domain.Principal, error) {
+	if a.parser == nil {
+		return nil, fmt.Errorf("jwt is not configured: %w", domain.ErrUnauthenticated)
+	}
+
+	var c claims
+	if _, err := a.parser.ParseWithClaims(token, &c, a.keys.Keyfunc); err != nil {
+		return nil, fmt.Errorf("invalid token: %w: %w", domain.ErrUnauthenticated, err)
+	}
+
+	scopes := c.Scopes
+	if c.Scope != "" {
+		scopes = append(scopes, strings.Fields(c.Scope)...)
+	}
+
+	return &domain.Principal{Subject: c.Subject, Scopes: scopes}, nil
+}
@@ -0,0 +1,213 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+var hmacSecret = []byte("0123456789abcdef0123456789abcdef")
+
+type instance struct {
+	name           string
+	token          func(t *testing.T) string
+	expectedErr    error
+	expectedScopes []string
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	cfg := &config.Config{
+		Auth: config.Auth{
+			APIKeys: []config.APIKey{
+				{Name: "reader", Hash: auth.HashAPIKey("read-key"), Scopes: []string{domain.ScopeOrdersRead}},
+				{Name: "admin", Hash: "sha256:" + auth.HashAPIKey("admin-key"), Scopes: []string{domain.ScopeAdmin}},
+			},
+		},
+	}
+
+	authenticator, err := auth.NewAuthenticator(cfg)
+	require.NoError(t, err)
+
+	principal, err := authenticator.AuthenticateAPIKey("read-key")
+	require.NoError(t, err)
+	require.Equal(t, "apikey:reader", principal.Subject)
+	require.True(t, principal.HasScope(domain.ScopeOrdersRead))
+	require.False(t, principal.HasScope(domain.ScopeAdmin))
+
+	principal, err = authenticator.AuthenticateAPIKey("admin-key")
+	require.NoError(t, err)
+	require.True(t, principal.HasScope(domain.ScopeOrdersWrite))
+
+	_, err = authenticator.AuthenticateAPIKey("unknown-key")
+	require.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	_, err = authenticator.AuthenticateToken("any.jwt.token")
+	require.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	cfg.Auth.APIKeys[0].Hash = "plaintext-key"
+	_, err = auth.NewAuthenticator(cfg)
+	require.Error(t, err)
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		Auth: config.Auth{
+			JWT: config.JWT{
+				JWKSFile: writeJWKS(t, &rsaKey.PublicKey),
+				Issuer:   "issuer",
+				Audience: "order_service",
+			},
+		},
+	}
+
+	authenticator, err := auth.NewAuthenticator(cfg)
+	require.NoError(t, err)
+
+	validClaims := jwt.MapClaims{
+		"sub":   "dashboard",
+		"iss":   "issuer",
+		"aud":   "order_service",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "orders:read orders:write",
+	}
+
+	tbl := []instance{
+		{
+			name:           "rs256_valid",
+			token:          signRS256(rsaKey, "rsa-1", validClaims),
+			expectedScopes: []string{domain.ScopeOrdersRead, domain.ScopeOrdersWrite},
+		},
+		{
+			name: "hs256_valid_scopes_list",
+			token: signHS256("hmac-1", hmacSecret, jwt.MapClaims{
+				"sub":    "service",
+				"iss":    "issuer",
+				"aud":    "order_service",
+				"exp":    time.Now().Add(time.Hour).Unix(),
+				"scopes": []string{domain.ScopeAdmin},
+			}),
+			expectedScopes: []string{domain.ScopeAdmin},
+		},
+		{
+			name: "expired",
+			token: signRS256(rsaKey, "rsa-1", jwt.MapClaims{
+				"sub": "dashboard", "iss": "issuer", "aud": "order_service",
+				"exp": time.Now().Add(-time.Minute).Unix(),
+			}),
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name: "without_exp",
+			token: signRS256(rsaKey, "rsa-1", jwt.MapClaims{
+				"sub": "dashboard", "iss": "issuer", "aud": "order_service",
+			}),
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name: "wrong_audience",
+			token: signRS256(rsaKey, "rsa-1", jwt.MapClaims{
+				"sub": "dashboard", "iss": "issuer", "aud": "other",
+				"exp": time.Now().Add(time.Hour).Unix(),
+			}),
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name:        "unknown_kid",
+			token:       signRS256(rsaKey, "rsa-2", validClaims),
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name:        "wrong_hmac_secret",
+			token:       signHS256("hmac-1", []byte("another-secret-another-secret!!"), validClaims),
+			expectedErr: domain.ErrUnauthenticated,
+		},
+		{
+			name: "alg_none",
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims)
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				require.NoError(t, err)
+				return signed
+			},
+			expectedErr: domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			principal, err := authenticator.AuthenticateToken(testCase.token(t))
+			if testCase.expectedErr != nil {
+				require.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, testCase.expectedScopes, principal.Scopes)
+		})
+	}
+}
+
+// writeJWKS сохраняет во временный файл JWKS с RSA ключом rsa-1 и HMAC ключом hmac-1.
+func writeJWKS(t *testing.T, pub *rsa.PublicKey) string {
+	doc := map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "hmac-1",
+				"alg": "HS256",
+				"k":   base64.RawURLEncoding.EncodeToString(hmacSecret),
+			},
+		},
+	}
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func signRS256(key *rsa.PrivateKey, kid string, claims jwt.MapClaims) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+}
+
+func signHS256(kid string, secret []byte, claims jwt.MapClaims) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(secret)
+		require.NoError(t, err)
+		return signed
+	}
+}
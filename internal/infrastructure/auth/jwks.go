@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk — ключ из JWKS (RFC 7517). Поддерживаются RSA (RS256) и симметричные oct (HS256) ключи.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// KeySet хранит ключи проверки подписи JWT по их kid.
+type KeySet struct {
+	rsaKeys  map[string]*rsa.PublicKey
+	hmacKeys map[string][]byte
+}
+
+// LoadKeySet читает JWKS из локального файла.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return ParseKeySet(data)
+}
+
+// ParseKeySet разбирает JWKS документ.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	ks := &KeySet{
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+		hmacKeys: make(map[string][]byte),
+	}
+
+	for _, key := range doc.Keys {
+		if key.Kid == "" {
+			return nil, errors.New("jwk without kid")
+		}
+
+		switch key.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("jwk %q: invalid modulus: %w", key.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return nil, fmt.Errorf("jwk %q: invalid exponent: %w", key.Kid, err)
+			}
+			ks.rsaKeys[key.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("jwk %q: invalid secret: %w", key.Kid, err)
+			}
+			ks.hmacKeys[key.Kid] = secret
+		default:
+			return nil, fmt.Errorf("jwk %q: unsupported key type %q", key.Kid, key.Kty)
+		}
+	}
+
+	return ks, nil
+}
+
+// Keyfunc выбирает ключ по заголовку kid. Тип ключа должен соответствовать алгоритму,
+// иначе токен с alg=HS256 можно было бы подписать публичным RSA ключом.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if key, ok := ks.rsaKeys[kid]; ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodHMAC:
+		if key, ok := ks.hmacKeys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s key with kid %q", token.Method.Alg(), kid)
+}
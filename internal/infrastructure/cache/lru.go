@@ -1,37 +1,170 @@
 package cache
 
 import (
+	"sync"
 	"time"
 
 	"order_service/config"
 	"order_service/internal/domain"
 
-	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 )
 
+// cacheEntry хранит заказ вместе с моментом сохранения: TTL отсчитывается от него,
+// поэтому новый TTL из SetTTL сразу применяется ко всем заказам.
+type cacheEntry struct {
+	order   *domain.Order
+	savedAt time.Time
+}
+
+// sweepDivisor — во сколько раз период фоновой очистки меньше TTL: истекший заказ
+// остается в памяти не дольше TTL/sweepDivisor.
+const sweepDivisor = 100
+
+// minSweepInterval ограничивает частоту фоновой очистки при коротком TTL.
+const minSweepInterval = 10 * time.Millisecond
+
+// LRUCache — LRU кеш заказов с TTL. Истекший заказ удаляется фоновой очисткой, при обращении
+// к нему или вытесняется по емкости. Очистку выполняет одна горутина, которая перезапускается
+// при смене TTL и останавливается в Close.
 type LRUCache struct {
-	cache *expirable.LRU[string, *domain.Order]
+	// mu защищает cache, ttl и stopSweep: simplelru не синхронизирован, а Get меняет порядок записей
+	mu      sync.Mutex
+	cache   *simplelru.LRU[string, cacheEntry]
+	ttl     time.Duration
+	metrics domain.CacheMetrics
+	// stopSweep останавливает фоновую очистку и дожидается ее завершения; nil после Close
+	stopSweep func()
 }
 
-// NewLRUCache создает новый LRU кеш с TTL на основе конфигурации.
-func NewLRUCache(cfg *config.Config) *LRUCache {
-	var ttl time.Duration
-	if cfg.Serv.Debug {
-		ttl = time.Second * time.Duration(cfg.Ttl) // Debug: TTL in seconds
-	} else {
-		ttl = time.Hour * time.Duration(cfg.Ttl) // Production: TTL in hours
-	}
-	cache := expirable.NewLRU[string, *domain.Order](cfg.Capacity, nil, ttl)
-	return &LRUCache{cache: cache}
+// NewLRUCache создает новый LRU кеш с TTL на основе конфигурации и запускает фоновую очистку.
+func NewLRUCache(cfg *config.Config, metrics domain.CacheMetrics) *LRUCache {
+	c := &LRUCache{ttl: cfg.TTL, metrics: metrics}
+	// Ошибку NewLRU возвращает только для неположительной емкости, ее отсекает проверка конфигурации
+	c.cache, _ = simplelru.NewLRU(cfg.Capacity, c.onEvict)
+	c.stopSweep = c.startSweep(cfg.TTL)
+	return c
 }
 
 // GetOrder получает заказ из кеша по order_uid.
 func (c *LRUCache) GetOrder(orderUID string) (*domain.Order, bool) {
-	order, ok := c.cache.Get(orderUID)
-	return order, ok
+	c.mu.Lock()
+	entry, ok := c.cache.Get(orderUID)
+	if ok && c.expired(entry, time.Now()) {
+		c.cache.Remove(orderUID)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.metrics.IncMiss()
+		return nil, false
+	}
+	c.metrics.IncHit()
+	return entry.order, true
 }
 
 // SaveOrder сохраняет заказ в кеш.
 func (c *LRUCache) SaveOrder(orderUID string, order *domain.Order) {
-	c.cache.Add(orderUID, order)
+	c.mu.Lock()
+	c.cache.Add(orderUID, cacheEntry{order: order, savedAt: time.Now()})
+	size := c.cache.Len()
+	c.mu.Unlock()
+	c.metrics.SetSize(size)
+}
+
+// SetTTL меняет TTL кеша во время работы. TTL отсчитывается от сохранения заказа:
+// заказы, пролежавшие дольше нового TTL, удаляются сразу, остальные хранятся до истечения
+// нового TTL. Фоновая очистка перезапускается с периодом под новый TTL.
+func (c *LRUCache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	if ttl == c.ttl {
+		c.mu.Unlock()
+		return
+	}
+	c.ttl = ttl
+	c.removeExpired()
+
+	stop := c.stopSweep
+	if stop != nil {
+		c.stopSweep = c.startSweep(ttl)
+	}
+	c.mu.Unlock()
+
+	// Прежняя очистка может ждать c.mu, поэтому останавливается после его освобождения
+	if stop != nil {
+		stop()
+	}
+}
+
+// Close останавливает фоновую очистку. Кеш остается рабочим, истекшие заказы удаляются
+// при обращении к ним.
+func (c *LRUCache) Close() {
+	c.mu.Lock()
+	stop := c.stopSweep
+	c.stopSweep = nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
+
+// startSweep запускает горутину, удаляющую истекшие заказы каждые ttl/sweepDivisor,
+// и возвращает функцию ее остановки. При неположительном TTL заказы не истекают,
+// и горутина не нужна.
+func (c *LRUCache) startSweep(ttl time.Duration) func() {
+	if ttl <= 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(max(ttl/sweepDivisor, minSweepInterval))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.mu.Lock()
+				c.removeExpired()
+				c.mu.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// removeExpired удаляет все истекшие заказы. Вызывается под c.mu.
+func (c *LRUCache) removeExpired() {
+	now := time.Now()
+	for _, key := range c.cache.Keys() {
+		if entry, ok := c.cache.Peek(key); ok && c.expired(entry, now) {
+			c.cache.Remove(key)
+		}
+	}
+}
+
+// expired сообщает, истек ли TTL заказа к моменту now. Вызывается под c.mu.
+func (c *LRUCache) expired(entry cacheEntry, now time.Time) bool {
+	return c.ttl > 0 && !now.Before(entry.savedAt.Add(c.ttl))
+}
+
+// onEvict вызывается simplelru при удалении заказа под c.mu и отличает удаление
+// по TTL от вытеснения по емкости.
+func (c *LRUCache) onEvict(_ string, entry cacheEntry) {
+	if c.expired(entry, time.Now()) {
+		c.metrics.IncExpiration()
+	} else {
+		c.metrics.IncEviction()
+	}
+	c.metrics.SetSize(c.cache.Len())
 }
@@ -1,14 +1,18 @@
 package cache_test
 
 import (
+	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"order_service/config"
 	"order_service/internal/domain"
 	"order_service/internal/infrastructure/cache"
+	"order_service/internal/mock"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 var testOrder = &domain.Order{
@@ -67,14 +71,14 @@ func TestSaveAndGetOrder(t *testing.T) {
 		},
 		Cache: config.Cache{
 			Capacity: 2,
-			Ttl:      15, // 15 sec
+			TTL:      15 * time.Second,
 		},
 	}
 
 	t.Run("save_and_get_order", func(t *testing.T) {
 		t.Parallel()
 
-		lruCache := cache.NewLRUCache(cfg)
+		lruCache := newLRUCache(t, cfg)
 
 		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
 
@@ -86,7 +90,7 @@ func TestSaveAndGetOrder(t *testing.T) {
 	t.Run("get_nonexistent_order", func(t *testing.T) {
 		t.Parallel()
 
-		lruCache := cache.NewLRUCache(cfg)
+		lruCache := newLRUCache(t, cfg)
 
 		order, ok := lruCache.GetOrder("nonexistent")
 		require.False(t, ok)
@@ -96,7 +100,7 @@ func TestSaveAndGetOrder(t *testing.T) {
 	t.Run("lru_eviction", func(t *testing.T) {
 		t.Parallel()
 
-		lruCache := cache.NewLRUCache(cfg)
+		lruCache := newLRUCache(t, cfg)
 
 		order1 := &domain.Order{OrderUID: "order1"}
 		order2 := &domain.Order{OrderUID: "order2"}
@@ -130,11 +134,11 @@ func TestSaveAndGetOrder(t *testing.T) {
 			Serv: config.Server{Debug: true},
 			Cache: config.Cache{
 				Capacity: 10,
-				Ttl:      1, // 1 секунда вместо 15
+				TTL:      time.Second, // 1 секунда вместо 15
 			},
 		}
 
-		lruCache := cache.NewLRUCache(shortTtlCfg)
+		lruCache := newLRUCache(t, shortTtlCfg)
 
 		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
 
@@ -152,7 +156,7 @@ func TestSaveAndGetOrder(t *testing.T) {
 	t.Run("update_existing_order", func(t *testing.T) {
 		t.Parallel()
 
-		lruCache := cache.NewLRUCache(cfg)
+		lruCache := newLRUCache(t, cfg)
 
 		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
 
@@ -171,3 +175,190 @@ func TestSaveAndGetOrder(t *testing.T) {
 		require.Equal(t, "UPDATED_TRACK", order.TrackNumber)
 	})
 }
+
+func TestCacheMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Serv: config.Server{Debug: true},
+		Cache: config.Cache{
+			Capacity: 1,
+			TTL:      time.Minute,
+		},
+	}
+
+	t.Run("hit_and_miss", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+		lruCache := cache.NewLRUCache(cfg, mockCacheMetrics)
+		t.Cleanup(lruCache.Close)
+
+		mockCacheMetrics.EXPECT().SetSize(1)
+		mockCacheMetrics.EXPECT().IncHit()
+		mockCacheMetrics.EXPECT().IncMiss()
+
+		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
+
+		_, ok := lruCache.GetOrder(testOrder.OrderUID)
+		require.True(t, ok)
+		_, ok = lruCache.GetOrder("nonexistent")
+		require.False(t, ok)
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+		lruCache := cache.NewLRUCache(cfg, mockCacheMetrics)
+		t.Cleanup(lruCache.Close)
+
+		// Второй заказ вытесняет первый до истечения TTL
+		mockCacheMetrics.EXPECT().SetSize(gomock.Any()).AnyTimes()
+		mockCacheMetrics.EXPECT().IncEviction()
+
+		lruCache.SaveOrder("order1", &domain.Order{OrderUID: "order1"})
+		lruCache.SaveOrder("order2", &domain.Order{OrderUID: "order2"})
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		t.Parallel()
+
+		shortTtlCfg := &config.Config{
+			Serv: config.Server{Debug: true},
+			Cache: config.Cache{
+				Capacity: 1,
+				TTL:      time.Second,
+			},
+		}
+
+		ctrl := gomock.NewController(t)
+		mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+		lruCache := cache.NewLRUCache(shortTtlCfg, mockCacheMetrics)
+		t.Cleanup(lruCache.Close)
+
+		// Истекший заказ удаляется фоновой очисткой или при обращении
+		gomock.InOrder(
+			mockCacheMetrics.EXPECT().SetSize(1),
+			mockCacheMetrics.EXPECT().IncExpiration(),
+			mockCacheMetrics.EXPECT().SetSize(0),
+			mockCacheMetrics.EXPECT().IncMiss(),
+		)
+
+		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
+		time.Sleep(time.Second)
+
+		_, ok := lruCache.GetOrder(testOrder.OrderUID)
+		require.False(t, ok)
+	})
+}
+
+func TestSweep(t *testing.T) {
+	cfg := &config.Config{
+		Cache: config.Cache{
+			Capacity: 10,
+			TTL:      200 * time.Millisecond,
+		},
+	}
+
+	t.Run("expired_orders_removed_without_access", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+		lruCache := cache.NewLRUCache(cfg, mockCacheMetrics)
+		t.Cleanup(lruCache.Close)
+
+		expired := make(chan struct{})
+		gomock.InOrder(
+			mockCacheMetrics.EXPECT().SetSize(1),
+			mockCacheMetrics.EXPECT().IncExpiration(),
+			mockCacheMetrics.EXPECT().SetSize(0).Do(func(int) { close(expired) }),
+		)
+
+		lruCache.SaveOrder(testOrder.OrderUID, testOrder)
+
+		// Заказ с персональными данными не лежит в памяти после истечения TTL
+		select {
+		case <-expired:
+		case <-time.After(time.Second):
+			t.Fatal("expired order was not swept")
+		}
+	})
+
+	t.Run("no_goroutines_after_close", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+
+		// Каждая смена TTL перезапускает очистку; прежние горутины не должны оставаться
+		lruCache := newLRUCache(t, cfg)
+		for i := range 100 {
+			lruCache.SetTTL(time.Duration(i+1) * time.Second)
+		}
+		lruCache.Close()
+		require.LessOrEqual(t, runtime.NumGoroutine(), before)
+	})
+}
+
+func TestSetTTL(t *testing.T) {
+	cfg := &config.Config{
+		Cache: config.Cache{
+			Capacity: 10,
+			TTL:      time.Second,
+		},
+	}
+
+	t.Run("longer_ttl_keeps_orders", func(t *testing.T) {
+		t.Parallel()
+
+		lruCache := newLRUCache(t, cfg)
+		lruCache.SaveOrder("order1", &domain.Order{OrderUID: "order1"})
+
+		lruCache.SetTTL(time.Minute)
+		time.Sleep(1200 * time.Millisecond)
+
+		_, ok := lruCache.GetOrder("order1")
+		require.True(t, ok)
+	})
+
+	t.Run("shorter_ttl_drops_old_orders", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+		lruCache := cache.NewLRUCache(cfg, mockCacheMetrics)
+		t.Cleanup(lruCache.Close)
+
+		mockCacheMetrics.EXPECT().SetSize(gomock.Any()).AnyTimes()
+		mockCacheMetrics.EXPECT().IncHit().AnyTimes()
+		mockCacheMetrics.EXPECT().IncMiss().AnyTimes()
+		var expirations atomic.Int32
+		mockCacheMetrics.EXPECT().IncExpiration().Do(func() { expirations.Add(1) }).AnyTimes()
+
+		lruCache.SaveOrder("old", &domain.Order{OrderUID: "old"})
+		time.Sleep(300 * time.Millisecond)
+		lruCache.SaveOrder("new", &domain.Order{OrderUID: "new"})
+
+		// Заказ old пролежал дольше нового TTL и удаляется сразу
+		lruCache.SetTTL(200 * time.Millisecond)
+		require.EqualValues(t, 1, expirations.Load())
+
+		_, ok := lruCache.GetOrder("old")
+		require.False(t, ok)
+		_, ok = lruCache.GetOrder("new")
+		require.True(t, ok)
+	})
+}
+
+// newLRUCache создает кеш с моком метрик, допускающим любые вызовы.
+func newLRUCache(t *testing.T, cfg *config.Config) *cache.LRUCache {
+	ctrl := gomock.NewController(t)
+	mockCacheMetrics := mock.NewMockCacheMetrics(ctrl)
+	mockCacheMetrics.EXPECT().IncHit().AnyTimes()
+	mockCacheMetrics.EXPECT().IncMiss().AnyTimes()
+	mockCacheMetrics.EXPECT().IncEviction().AnyTimes()
+	mockCacheMetrics.EXPECT().IncExpiration().AnyTimes()
+	mockCacheMetrics.EXPECT().SetSize(gomock.Any()).AnyTimes()
+	lruCache := cache.NewLRUCache(cfg, mockCacheMetrics)
+	t.Cleanup(lruCache.Close)
+	return lruCache
+}
@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix отмечает зашифрованные значения. Формат значения:
+//
+//	enc:v1:<key id>:<ключ данных, зашифрованный мастер-ключом>:<шифротекст>
+//
+// Обе части — base64url(nonce || ciphertext). Значения без префикса считаются
+// открытым текстом, записанным до включения шифрования.
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey       = errors.New("unknown encryption key")
+	ErrMalformedPayload = errors.New("malformed encrypted value")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Encrypt шифрует значение новым ключом данных, который оборачивается основным мастер-ключом.
+func (k *Keyring) Encrypt(plaintext, aad string) (string, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := seal(k.keks[k.primary], dek, []byte(k.primary))
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", fmt.Errorf("failed to create data cipher: %w", err)
+	}
+	data, err := seal(aead, []byte(plaintext), []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	return prefix + k.primary + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(data), nil
+}
+
+// Decrypt расшифровывает значение любым ключом из keyring. Открытый текст возвращается как есть.
+func (k *Keyring) Decrypt(value, aad string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformedPayload
+	}
+	kek, ok := k.keks[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, parts[0])
+	}
+
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformedPayload
+	}
+	data, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformedPayload
+	}
+
+	dek, err := open(kek, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", fmt.Errorf("failed to create data cipher: %w", err)
+	}
+	plaintext, err := open(aead, data, []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt сообщает, что значение не зашифровано или зашифровано не основным ключом.
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return true
+	}
+	id, _, _ := strings.Cut(rest, ":")
+	return id != k.primary
+}
+
+// BlindIndex возвращает HMAC-SHA256 нормализованного значения (без регистра и внешних пробелов).
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformedPayload
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
@@ -0,0 +1,106 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"order_service/internal/infrastructure/encryption"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey   = bytes.Repeat([]byte{1}, encryption.KeySize)
+	newKey   = bytes.Repeat([]byte{2}, encryption.KeySize)
+	indexKey = bytes.Repeat([]byte{3}, encryption.KeySize)
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring, err := encryption.NewKeyring("k1", map[string][]byte{"k1": oldKey}, indexKey)
+	require.NoError(t, err)
+
+	encrypted, err := keyring.Encrypt("+9720000000", "uid/phone")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted, "enc:v1:k1:"))
+	require.NotContains(t, encrypted, "9720000000")
+	require.False(t, keyring.NeedsReencrypt(encrypted))
+
+	again, err := keyring.Encrypt("+9720000000", "uid/phone")
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, again, "each value must use its own data key and nonce")
+
+	decrypted, err := keyring.Decrypt(encrypted, "uid/phone")
+	require.NoError(t, err)
+	require.Equal(t, "+9720000000", decrypted)
+
+	_, err = keyring.Decrypt(encrypted, "other_uid/phone")
+	require.Error(t, err, "ciphertext must be bound to its row and column")
+
+	plain, err := keyring.Decrypt("legacy plaintext", "uid/phone")
+	require.NoError(t, err)
+	require.Equal(t, "legacy plaintext", plain)
+	require.True(t, keyring.NeedsReencrypt("legacy plaintext"))
+
+	_, err = keyring.Decrypt("enc:v1:k1:broken", "uid/phone")
+	require.ErrorIs(t, err, encryption.ErrMalformedPayload)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKeyring, err := encryption.NewKeyring("k1", map[string][]byte{"k1": oldKey}, indexKey)
+	require.NoError(t, err)
+	encrypted, err := oldKeyring.Encrypt("test@gmail.com", "uid/email")
+	require.NoError(t, err)
+
+	rotated, err := encryption.NewKeyring("k2", map[string][]byte{"k1": oldKey, "k2": newKey}, indexKey)
+	require.NoError(t, err)
+	require.True(t, rotated.NeedsReencrypt(encrypted))
+
+	decrypted, err := rotated.Decrypt(encrypted, "uid/email")
+	require.NoError(t, err)
+	require.Equal(t, "test@gmail.com", decrypted)
+
+	reencrypted, err := rotated.Encrypt(decrypted, "uid/email")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(reencrypted, "enc:v1:k2:"))
+
+	withoutOld, err := encryption.NewKeyring("k2", map[string][]byte{"k2": newKey}, indexKey)
+	require.NoError(t, err)
+	_, err = withoutOld.Decrypt(encrypted, "uid/email")
+	require.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+	require.Equal(t, oldKeyring.BlindIndex("Test@Gmail.com "), rotated.BlindIndex("test@gmail.com"),
+		"blind index must not depend on master key rotation")
+}
+
+func TestLoadKeyring(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "keyring.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	keyring, err := encryption.LoadKeyring(write(t, fmt.Sprintf(
+		`{"primary": "k2", "keys": {"k1": %q, "k2": %q}, "blind_index_key": %q}`,
+		b64(oldKey), b64(newKey), b64(indexKey))))
+	require.NoError(t, err)
+	require.Equal(t, "k2", keyring.Primary())
+
+	tbl := map[string]string{
+		"unknown_primary": fmt.Sprintf(`{"primary": "k3", "keys": {"k1": %q}, "blind_index_key": %q}`, b64(oldKey), b64(indexKey)),
+		"short_key":       fmt.Sprintf(`{"primary": "k1", "keys": {"k1": %q}, "blind_index_key": %q}`, b64(oldKey[:16]), b64(indexKey)),
+		"no_index_key":    fmt.Sprintf(`{"primary": "k1", "keys": {"k1": %q}}`, b64(oldKey)),
+		"colon_in_id":     fmt.Sprintf(`{"primary": "k:1", "keys": {"k:1": %q}, "blind_index_key": %q}`, b64(oldKey), b64(indexKey)),
+	}
+	for name, content := range tbl {
+		t.Run(name, func(t *testing.T) {
+			_, err := encryption.LoadKeyring(write(t, content))
+			require.Error(t, err)
+		})
+	}
+}
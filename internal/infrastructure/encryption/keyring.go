@@ -0,0 +1,118 @@
+// Package encryption реализует конвертное шифрование персональных данных:
+// каждое значение шифруется своим ключом данных (AES-256-GCM), а ключ данных —
+// мастер-ключом из локального keyring файла. Идентификатор мастер-ключа хранится
+// рядом с шифротекстом, поэтому ключи можно ротировать без остановки сервиса.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize — размер мастер-ключей и ключей данных (AES-256).
+const KeySize = 32
+
+// keyringFile — формат keyring файла:
+//
+//	{
+//	  "primary": "2025-01",
+//	  "keys": {"2024-06": "<base64>", "2025-01": "<base64>"},
+//	  "blind_index_key": "<base64>"
+//	}
+//
+// Новые значения шифруются ключом primary, остальные ключи нужны для чтения
+// старых записей до их перешифрования.
+type keyringFile struct {
+	Primary       string            `json:"primary"`
+	Keys          map[string]string `json:"keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+type Keyring struct {
+	primary  string
+	keks     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// LoadKeyring читает keyring из JSON файла.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+	return ParseKeyring(data)
+}
+
+// ParseKeyring разбирает keyring в формате keyringFile.
+func ParseKeyring(data []byte) (*Keyring, error) {
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode keyring: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(file.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+
+	return NewKeyring(file.Primary, keys, indexKey)
+}
+
+// NewKeyring создает keyring из мастер-ключей по их идентификаторам.
+// Ключ blind index не ротируется: при его смене индекс нужно перестроить.
+func NewKeyring(primary string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not in the keyring", primary)
+	}
+	if len(indexKey) < KeySize {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes", KeySize)
+	}
+
+	k := &Keyring{
+		primary:  primary,
+		keks:     make(map[string]cipher.AEAD, len(keys)),
+		indexKey: indexKey,
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key id %q must be non-empty and must not contain ':'", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes", id, KeySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keks[id] = aead
+	}
+
+	return k, nil
+}
+
+// Primary возвращает идентификатор ключа, которым шифруются новые значения.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,63 +1,172 @@
 package consumer
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
 
 	"order_service/config"
 	"order_service/internal/domain"
+	"order_service/internal/infrastructure/schema"
+	"order_service/internal/infrastructure/tracing"
 	"order_service/internal/logger"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("order_service/internal/infrastructure/kafka/consumer")
+
+// lagInterval — период опроса статистики reader'а для общей метрики отставания.
+const lagInterval = 5 * time.Second
+
 type Consumer struct {
-	reader *kafka.Reader
+	reader  *kafka.Reader
+	decoder domain.OrderDecoder
+	metrics domain.ConsumerMetrics
+	log     *slog.Logger
 }
 
-// NewConsumer создает новый Kafka consumer с конфигурацией
-func NewConsumer(cfg *config.Config) *Consumer {
-	logger.DebugLogger.Println("Initializing Kafka Consumer")
+// NewConsumer создает новый Kafka consumer с конфигурацией. Тело сообщения декодируется
+// decoder'ом по заголовкам content-type и schema-version.
+func NewConsumer(cfg *config.Config, decoder domain.OrderDecoder, metrics domain.ConsumerMetrics, log *slog.Logger) *Consumer {
+	log.Debug("Initializing Kafka Consumer")
 	return &Consumer{
 		reader: kafka.NewReader(kafka.ReaderConfig{
 			Brokers: cfg.Brokers,
 			Topic:   cfg.Topic,
 			GroupID: cfg.GroupID,
 		}),
+		decoder: decoder,
+		metrics: metrics,
+		log:     log,
 	}
 }
 
-// ReadMessage читает сообщение из Kafka, декодирует в Order и коммитит
-func (c *Consumer) ReadMessage(ctx context.Context) (*domain.Order, error) {
+// ReadMessage читает сообщение из Kafka и декодирует в Order. Смещение декодированного сообщения
+// не коммитится: вызывающий коммитит его через Commit, когда заказ обработан. Сообщение, которое
+// не удалось декодировать, коммитится сразу. Вместе с заказом возвращаются позиция
+// и исходное содержимое сообщения — для журнала обработанных сообщений и DLQ.
+// Возвращаемый контекст содержит span сообщения, продолжающий trace из его заголовков. Span
+// остается открытым на время обработки заказа: его закрывает функция end, которую вызывающий
+// вызывает с итоговой ошибкой обработки. При ошибке чтения span закрывается сразу, а end равна nil.
+func (c *Consumer) ReadMessage(ctx context.Context) (context.Context, *domain.OrderMessage, func(error), error) {
 	if ctx.Err() != nil {
-		return nil, fmt.Errorf("reading from Kafka cancelled: %w", ctx.Err())
+		return ctx, nil, nil, fmt.Errorf("reading from Kafka cancelled: %w", ctx.Err())
 	}
 	msg, err := c.reader.FetchMessage(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to receive message: %w", err)
+		return ctx, nil, nil, fmt.Errorf("failed to receive message: %w", err)
 	}
 
-	logger.InfoLogger.Printf(
-		"Message at topic/partition/offset %v/%v/%v",
-		msg.Topic,
-		msg.Partition,
-		msg.Offset,
+	msgCtx := otel.GetTextMapPropagator().Extract(ctx, tracing.NewKafkaHeaderCarrier(&msg))
+	msgCtx, span := tracer.Start(msgCtx, "Consumer.ReadMessage",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+			semconv.MessagingKafkaOffset(int(msg.Offset)),
+		),
 	)
+	end := func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to process message")
+		}
+		span.End()
+	}
+	msgCtx = logger.WithMessage(msgCtx, msg.Partition, msg.Offset)
+
+	c.metrics.IncConsumed()
+	// HighWaterMark — смещение, следующее за последним сообщением партиции на момент fetch
+	c.metrics.SetLag(msg.Partition, msg.HighWaterMark-msg.Offset-1)
+
+	c.log.InfoContext(msgCtx, "Message received", slog.String("topic", msg.Topic))
 
-	data := msg.Value
-	order := domain.Order{}
+	contentType, schemaID := header(msg, schema.HeaderContentType), header(msg, schema.HeaderSchemaVersion)
+	span.SetAttributes(
+		attribute.String("messaging.message.content_type", contentType),
+		attribute.String("messaging.message.schema_id", schemaID),
+	)
 
-	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&order); err != nil {
-		return nil, fmt.Errorf("failed to decode message: %w", err)
+	order, err := c.decoder.DecodeOrder(contentType, schemaID, msg.Value)
+	if err != nil {
+		c.metrics.IncDecodeFailure()
+		err = fmt.Errorf("failed to decode message: %w", err)
+		// Повторное чтение не поможет: коммитим, чтобы сообщение не читалось после перезапуска
+		if commitErr := c.reader.CommitMessages(msgCtx, msg); commitErr != nil {
+			c.log.ErrorContext(msgCtx, "Failed to commit undecodable message", slog.Any("error", commitErr))
+		}
+		end(err)
+		return msgCtx, nil, nil, err
 	}
 
-	if err := c.reader.CommitMessages(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to commit messages: %w", err)
+	return msgCtx, &domain.OrderMessage{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   messageHeaders(msg),
+		Order:     order,
+	}, end, nil
+}
+
+// Commit коммитит смещение сообщения. Коммит сдвигает позицию группы в партиции за это
+// сообщение, поэтому вызывать его можно только после обработки всех более ранних сообщений
+// партиции — иначе необработанные сообщения будут пропущены после перезапуска.
+func (c *Consumer) Commit(ctx context.Context, msg *domain.OrderMessage) error {
+	start := time.Now()
+	err := c.reader.CommitMessages(ctx, kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset})
+	if err != nil {
+		return fmt.Errorf("failed to commit messages: %w", err)
 	}
+	c.metrics.ObserveCommit(start)
+	return nil
+}
+
+// ReportLag периодически снимает отставание из статистики Kafka reader'а, пока не отменен ctx.
+// Отставание по партициям обновляется при чтении каждого сообщения (см. ReadMessage) и замирает,
+// пока consumer стоит. Статистика reader'а обновляется при каждом fetch, но в режиме consumer
+// group kafka-go не разделяет ее по партициям, поэтому она пишется в отдельную общую метрику.
+func (c *Consumer) ReportLag(ctx context.Context) {
+	ticker := time.NewTicker(lagInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.metrics.SetReaderLag(c.reader.Stats().Lag)
+		}
+	}
+}
 
-	return &order, nil
+// messageHeaders копирует заголовки сообщения в порядке их следования.
+func messageHeaders(msg kafka.Message) []domain.MessageHeader {
+	headers := make([]domain.MessageHeader, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers = append(headers, domain.MessageHeader{Key: h.Key, Value: h.Value})
+	}
+	return headers
+}
+
+// header возвращает значение заголовка сообщения или пустую строку.
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
 }
 
 // Close закрывает Kafka reader
@@ -0,0 +1,238 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"order_service/config"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// adminTimeout ограничивает каждый запрос GroupAdmin к брокеру.
+const adminTimeout = 10 * time.Second
+
+// ErrGroupActive — у consumer group есть участники, смещения сдвинуть нельзя.
+var ErrGroupActive = errors.New("consumer group has active members")
+
+// PartitionLag — отставание consumer group в партиции.
+type PartitionLag struct {
+	Partition int
+	// Committed — закоммиченное смещение, -1 — группа в партиции ничего не коммитила.
+	Committed int64
+	// End — смещение следующего сообщения партиции (high watermark).
+	End int64
+	// Lag — сколько сообщений группе осталось прочитать.
+	Lag int64
+}
+
+// OffsetReset — сдвиг смещения группы в партиции.
+type OffsetReset struct {
+	Partition int
+	From      int64
+	To        int64
+}
+
+// GroupAdmin читает и сдвигает смещения consumer group сервиса в топике заказов.
+type GroupAdmin struct {
+	client  *kafka.Client
+	topic   string
+	groupID string
+	log     *slog.Logger
+}
+
+// NewGroupAdmin создает GroupAdmin для группы kafka.group_id и топика kafka.topic.
+func NewGroupAdmin(cfg *config.Config, log *slog.Logger) *GroupAdmin {
+	return &GroupAdmin{
+		client:  &kafka.Client{Addr: kafka.TCP(cfg.Brokers...), Timeout: adminTimeout},
+		topic:   cfg.Topic,
+		groupID: cfg.GroupID,
+		log:     log,
+	}
+}
+
+// Lag возвращает отставание группы по партициям топика. Для партиций без коммита
+// отставание считается от начала партиции: с него группа начнет читать.
+func (a *GroupAdmin) Lag(ctx context.Context) ([]PartitionLag, error) {
+	partitions, err := a.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := a.committed(ctx, partitions)
+	if err != nil {
+		return nil, err
+	}
+	first, err := a.listOffsets(ctx, partitions, kafka.FirstOffsetOf)
+	if err != nil {
+		return nil, err
+	}
+	last, err := a.listOffsets(ctx, partitions, kafka.LastOffsetOf)
+	if err != nil {
+		return nil, err
+	}
+
+	lags := make([]PartitionLag, 0, len(partitions))
+	for _, partition := range partitions {
+		lag := PartitionLag{Partition: partition, Committed: committed[partition], End: last[partition].LastOffset}
+		from := lag.Committed
+		if from < 0 {
+			from = first[partition].FirstOffset
+		}
+		lag.Lag = max(lag.End-from, 0)
+		lags = append(lags, lag)
+	}
+	return lags, nil
+}
+
+// ResetOffsets сдвигает смещения группы во всех партициях на первое сообщение не раньше at.
+// Если таких сообщений в партиции нет, смещение ставится в конец партиции. Пока группа
+// читает топик, брокер не примет коммит, поэтому у группы не должно быть участников.
+// При dryRun смещения только вычисляются.
+func (a *GroupAdmin) ResetOffsets(ctx context.Context, at time.Time, dryRun bool) ([]OffsetReset, error) {
+	if err := a.ensureInactive(ctx); err != nil {
+		return nil, err
+	}
+	partitions, err := a.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := a.committed(ctx, partitions)
+	if err != nil {
+		return nil, err
+	}
+	byTime, err := a.listOffsets(ctx, partitions, func(partition int) kafka.OffsetRequest {
+		return kafka.TimeOffsetOf(partition, at)
+	})
+	if err != nil {
+		return nil, err
+	}
+	last, err := a.listOffsets(ctx, partitions, kafka.LastOffsetOf)
+	if err != nil {
+		return nil, err
+	}
+
+	resets := make([]OffsetReset, 0, len(partitions))
+	commits := make([]kafka.OffsetCommit, 0, len(partitions))
+	for _, partition := range partitions {
+		// Ответ на запрос по времени содержит единственное смещение — первое сообщение не раньше at
+		to := last[partition].LastOffset
+		for offset := range byTime[partition].Offsets {
+			to = offset
+		}
+		resets = append(resets, OffsetReset{Partition: partition, From: committed[partition], To: to})
+		commits = append(commits, kafka.OffsetCommit{Partition: partition, Offset: to})
+	}
+	if dryRun {
+		return resets, nil
+	}
+
+	resp, err := a.client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      a.groupID,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{a.topic: commits},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit offsets: %w", err)
+	}
+	for _, partition := range resp.Topics[a.topic] {
+		if partition.Error != nil {
+			return nil, fmt.Errorf("failed to commit offset of partition %d: %w", partition.Partition, partition.Error)
+		}
+	}
+	a.log.Info("Consumer group offsets reset",
+		slog.String("group_id", a.groupID), slog.String("topic", a.topic), slog.Time("at", at))
+	return resets, nil
+}
+
+// ensureInactive проверяет, что у группы нет участников.
+func (a *GroupAdmin) ensureInactive(ctx context.Context) error {
+	resp, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{a.groupID}})
+	if err != nil {
+		return fmt.Errorf("failed to describe consumer group: %w", err)
+	}
+	for _, group := range resp.Groups {
+		if group.Error != nil {
+			return fmt.Errorf("failed to describe consumer group: %w", group.Error)
+		}
+		if group.GroupState != "Empty" && group.GroupState != "Dead" {
+			return fmt.Errorf("%w: group %s is %s with %d members", ErrGroupActive, a.groupID, group.GroupState, len(group.Members))
+		}
+	}
+	return nil
+}
+
+// partitions возвращает номера партиций топика по возрастанию.
+func (a *GroupAdmin) partitions(ctx context.Context) ([]int, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{a.topic}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+	for _, topic := range resp.Topics {
+		if topic.Name != a.topic {
+			continue
+		}
+		if topic.Error != nil {
+			return nil, fmt.Errorf("failed to get topic metadata: %w", topic.Error)
+		}
+		partitions := make([]int, 0, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			partitions = append(partitions, partition.ID)
+		}
+		slices.Sort(partitions)
+		return partitions, nil
+	}
+	return nil, fmt.Errorf("topic %s not found", a.topic)
+}
+
+// committed возвращает закоммиченные смещения группы по партициям.
+func (a *GroupAdmin) committed(ctx context.Context, partitions []int) (map[int]int64, error) {
+	resp, err := a.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: a.groupID,
+		Topics:  map[string][]int{a.topic: partitions},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch committed offsets: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to fetch committed offsets: %w", resp.Error)
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, partition := range partitions {
+		offsets[partition] = -1
+	}
+	for _, partition := range resp.Topics[a.topic] {
+		if partition.Error != nil {
+			return nil, fmt.Errorf("failed to fetch committed offset of partition %d: %w", partition.Partition, partition.Error)
+		}
+		offsets[partition.Partition] = partition.CommittedOffset
+	}
+	return offsets, nil
+}
+
+// listOffsets запрашивает у брокера смещения партиций, request задает запрос для партиции.
+func (a *GroupAdmin) listOffsets(ctx context.Context, partitions []int, request func(partition int) kafka.OffsetRequest) (map[int]kafka.PartitionOffsets, error) {
+	requests := make([]kafka.OffsetRequest, 0, len(partitions))
+	for _, partition := range partitions {
+		requests = append(requests, request(partition))
+	}
+	resp, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{a.topic: requests},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets: %w", err)
+	}
+
+	offsets := make(map[int]kafka.PartitionOffsets, len(partitions))
+	for _, partition := range resp.Topics[a.topic] {
+		if partition.Error != nil {
+			return nil, fmt.Errorf("failed to list offsets of partition %d: %w", partition.Partition, partition.Error)
+		}
+		offsets[partition.Partition] = partition
+	}
+	return offsets, nil
+}
@@ -0,0 +1,190 @@
+// Package producer отправляет заказы в Kafka для нагрузочных тестов и повторной отправки:
+// синтетические заказы генерируются из seed, сохраненные сообщения читаются из файлов.
+package producer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Defect — способ испортить синтетический заказ.
+type Defect string
+
+const (
+	// DefectNone — заказ корректен.
+	DefectNone Defect = ""
+	// Нарушения правил domain.ValidateOrder
+	DefectMissingOrderUID Defect = "missing_order_uid"
+	DefectZeroAmount      Defect = "zero_amount"
+	DefectNoItems         Defect = "no_items"
+	DefectBadItemPrice    Defect = "bad_item_price"
+	// Нарушения схемы, которые отклоняет строгий режим декодирования
+	DefectUnknownField Defect = "unknown_field"
+	DefectWrongType    Defect = "wrong_type"
+)
+
+// Defects — все способы испортить заказ, из которых Faker выбирает случайно.
+var Defects = []Defect{
+	DefectMissingOrderUID, DefectZeroAmount, DefectNoItems, DefectBadItemPrice, DefectUnknownField, DefectWrongType,
+}
+
+var (
+	firstNames = []string{"Ivan", "Maria", "Alexey", "Olga", "Dmitry", "Anna", "Sergey", "Elena"}
+	lastNames  = []string{"Ivanov", "Petrova", "Smirnov", "Kuznetsova", "Popov", "Volkova"}
+	cities     = []string{"Moscow", "Kazan", "Novosibirsk", "Yekaterinburg", "Kiryat Mozkin", "Minsk"}
+	regions    = []string{"Central", "Volga", "Siberia", "Ural", "Kraiot"}
+	streets    = []string{"Lenina", "Mira", "Sadovaya", "Gagarina", "Pushkina"}
+	domains    = []string{"gmail.com", "mail.ru", "yandex.ru", "example.com"}
+	currencies = []string{"USD", "EUR", "RUB"}
+	banks      = []string{"alpha", "sber", "tinkoff", "vtb"}
+	services   = []string{"meest", "dhl", "cdek", "boxberry"}
+	brands     = []string{"Vivienne Sabo", "Nike", "Adidas", "Puma", "Zara", "Lego"}
+	products   = []string{"Mascaras", "Ball", "Sneakers", "T-shirt", "Backpack", "Constructor"}
+	locales    = []string{"en", "ru"}
+)
+
+// fakerEpoch — начало интервала дат создания заказов. Даты не зависят от текущего времени,
+// чтобы один seed всегда давал одни и те же сообщения.
+var fakerEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Faker генерирует правдоподобные заказы. Одинаковый seed дает одинаковую последовательность,
+// поэтому прогон можно повторить, а повторно отправленные заказы сервис распознает как дубли.
+type Faker struct {
+	rnd *rand.Rand
+}
+
+// NewFaker создает генератор с seed.
+func NewFaker(seed uint64) *Faker {
+	return &Faker{rnd: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// Order генерирует заказ, проходящий domain.ValidateOrder.
+func (f *Faker) Order() *domain.Order {
+	orderUID := fmt.Sprintf("%016x%04x", f.rnd.Uint64(), f.rnd.IntN(0x10000))
+	trackNumber := "WBIL" + f.letters(10)
+	first, last := pick(f.rnd, firstNames), pick(f.rnd, lastNames)
+
+	items := make([]domain.Item, 1+f.rnd.IntN(5))
+	goodsTotal := 0
+	for i := range items {
+		price := 100 + f.rnd.IntN(4900)
+		sale := f.rnd.IntN(6) * 10
+		totalPrice := price * (100 - sale) / 100
+		goodsTotal += totalPrice
+		items[i] = domain.Item{
+			ChrtID:      1_000_000 + f.rnd.IntN(9_000_000),
+			TrackNumber: trackNumber,
+			Price:       price,
+			Rid:         fmt.Sprintf("%016x", f.rnd.Uint64()),
+			Name:        pick(f.rnd, products),
+			Sale:        sale,
+			Size:        strconv.Itoa(f.rnd.IntN(5)),
+			TotalPrice:  totalPrice,
+			NmID:        1_000_000 + f.rnd.IntN(9_000_000),
+			Brand:       pick(f.rnd, brands),
+			Status:      202,
+		}
+	}
+	deliveryCost := 100 * f.rnd.IntN(20)
+	created := fakerEpoch.Add(time.Duration(f.rnd.IntN(365*24*60)) * time.Minute)
+
+	return &domain.Order{
+		OrderUID:    orderUID,
+		TrackNumber: trackNumber,
+		Entry:       "WBIL",
+		Delivery: domain.Delivery{
+			Name:    first + " " + last,
+			Phone:   fmt.Sprintf("+7%010d", f.rnd.IntN(10_000_000_000)),
+			Zip:     fmt.Sprintf("%06d", f.rnd.IntN(1_000_000)),
+			City:    pick(f.rnd, cities),
+			Address: fmt.Sprintf("%s %d", pick(f.rnd, streets), 1+f.rnd.IntN(200)),
+			Region:  pick(f.rnd, regions),
+			Email:   strings.ToLower(first+"."+last) + "@" + pick(f.rnd, domains),
+		},
+		Payment: domain.Payment{
+			Transaction:  orderUID,
+			Currency:     pick(f.rnd, currencies),
+			Provider:     "wbpay",
+			Amount:       goodsTotal + deliveryCost,
+			PaymentDt:    int(created.Unix()),
+			Bank:         pick(f.rnd, banks),
+			DeliveryCost: deliveryCost,
+			GoodsTotal:   goodsTotal,
+		},
+		Items:           items,
+		Locale:          pick(f.rnd, locales),
+		CustomerID:      "customer_" + strconv.Itoa(f.rnd.IntN(10_000)),
+		DeliveryService: pick(f.rnd, services),
+		ShardKey:        strconv.Itoa(f.rnd.IntN(10)),
+		SmID:            99,
+		DateCreated:     created.Format(time.RFC3339),
+		OofShard:        strconv.Itoa(1 + f.rnd.IntN(2)),
+	}
+}
+
+// Message генерирует сообщение с заказом в JSON. С вероятностью invalidRatio заказ испорчен
+// случайным дефектом из Defects; возвращается и сам дефект. Ключ сообщения — order_uid.
+func (f *Faker) Message(invalidRatio float64) (kafka.Message, Defect, error) {
+	order := f.Order()
+	defect := DefectNone
+	if invalidRatio > 0 && f.rnd.Float64() < invalidRatio {
+		defect = pick(f.rnd, Defects)
+	}
+
+	switch defect {
+	case DefectMissingOrderUID:
+		order.OrderUID = ""
+	case DefectZeroAmount:
+		order.Amount = 0
+	case DefectNoItems:
+		order.Items = []domain.Item{}
+	case DefectBadItemPrice:
+		order.Items[0].Price = -order.Items[0].Price
+	}
+
+	value, err := json.Marshal(order)
+	if err != nil {
+		return kafka.Message{}, defect, err
+	}
+
+	if defect == DefectUnknownField || defect == DefectWrongType {
+		fields := map[string]any{}
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return kafka.Message{}, defect, err
+		}
+		if defect == DefectUnknownField {
+			// Опечатка в имени блока: без строгого режима заказ сохранится с пустой доставкой
+			fields["delivry"] = fields["delivery"]
+			delete(fields, "delivery")
+		} else {
+			fields["sm_id"] = strconv.Itoa(order.SmID)
+		}
+		if value, err = json.Marshal(fields); err != nil {
+			return kafka.Message{}, defect, err
+		}
+	}
+
+	return kafka.Message{Key: []byte(order.OrderUID), Value: value}, defect, nil
+}
+
+// letters возвращает n случайных заглавных латинских букв.
+func (f *Faker) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('A' + f.rnd.IntN(26))
+	}
+	return string(b)
+}
+
+// pick возвращает случайный элемент списка.
+func pick[T any](rnd *rand.Rand, list []T) T {
+	return list[rnd.IntN(len(list))]
+}
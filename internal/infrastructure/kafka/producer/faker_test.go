@@ -0,0 +1,70 @@
+package producer_test
+
+import (
+	"testing"
+
+	"order_service/config"
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/kafka/producer"
+	"order_service/internal/infrastructure/schema"
+	"order_service/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaker(t *testing.T) {
+	registry, err := schema.LoadRegistry(config.Schema{
+		RegistryFile: "../../../../config/schemas/registry.json",
+		Strict:       config.StrictDecoding{Enabled: true, MaxPayloadSize: 1 << 20, MaxItems: 1000},
+	}, logger.Discard())
+	require.NoError(t, err)
+
+	t.Run("same_seed_same_orders", func(t *testing.T) {
+		first, second := producer.NewFaker(7), producer.NewFaker(7)
+		for range 10 {
+			expected, _, err := first.Message(0.5)
+			require.NoError(t, err)
+			actual, _, err := second.Message(0.5)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		}
+
+		other, _, err := producer.NewFaker(8).Message(0)
+		require.NoError(t, err)
+		same, _, err := producer.NewFaker(7).Message(0)
+		require.NoError(t, err)
+		require.NotEqual(t, same.Value, other.Value)
+	})
+
+	t.Run("valid_orders", func(t *testing.T) {
+		faker := producer.NewFaker(1)
+		for range 100 {
+			msg, defect, err := faker.Message(0)
+			require.NoError(t, err)
+			require.Equal(t, producer.DefectNone, defect)
+
+			order, err := registry.DecodeOrder("", "", msg.Value)
+			require.NoError(t, err)
+			require.NoError(t, domain.ValidateOrder(order))
+			require.Equal(t, order.OrderUID, string(msg.Key))
+		}
+	})
+
+	t.Run("defects_rejected", func(t *testing.T) {
+		faker := producer.NewFaker(1)
+		seen := map[producer.Defect]bool{}
+		for range 200 {
+			msg, defect, err := faker.Message(1)
+			require.NoError(t, err)
+			require.NotEqual(t, producer.DefectNone, defect)
+			seen[defect] = true
+
+			order, err := registry.DecodeOrder("", "", msg.Value)
+			if err == nil {
+				err = domain.ValidateOrder(order)
+			}
+			require.Error(t, err, defect)
+		}
+		require.Len(t, seen, len(producer.Defects))
+	})
+}
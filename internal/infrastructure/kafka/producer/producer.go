@@ -1,56 +1,190 @@
-package main
+package producer
 
 import (
 	"context"
-	"log"
-	"os"
-	"path"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
 
 	"order_service/config"
 
 	"github.com/segmentio/kafka-go"
 )
 
-const orderDir = "orders"
+// maxErrorKinds ограничивает число разных текстов ошибок в отчете, остальные считаются вместе.
+const maxErrorKinds = 20
 
-func main() {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalln("failed to load config:", err)
+// otherErrors — ключ отчета для ошибок сверх maxErrorKinds.
+const otherErrors = "other errors"
+
+// Writer — часть kafka.Writer, нужная Producer'у. В тестах подменяется фейком.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// NewKafkaWriter создает writer для топика topic. Сообщения распределяются по партициям
+// хешем ключа (order_uid), как у настоящих отправителей заказов.
+func NewKafkaWriter(cfg *config.Config, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: 10 * time.Millisecond,
 	}
+}
+
+// Options — параметры отправки.
+type Options struct {
+	// Rate — целевая скорость в сообщениях в секунду, 0 — без ограничения.
+	Rate float64
+	// BatchSize — наибольшее число сообщений в одной записи.
+	BatchSize int
+	// ReportEvery — период промежуточных отчетов, 0 — только итоговый.
+	ReportEvery time.Duration
+}
+
+// Report — итоги отправки.
+type Report struct {
+	Sent    int
+	Failed  int
+	Bytes   int64
+	Elapsed time.Duration
+	// Errors — количество неотправленных сообщений по тексту ошибки.
+	Errors map[string]int
+}
 
-	writer := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: cfg.Brokers,
-		Topic:   cfg.Topic,
-	})
-	defer writer.Close() //nolint:errcheck
+// Throughput возвращает достигнутую скорость отправки в сообщениях в секунду.
+func (r Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Sent) / r.Elapsed.Seconds()
+}
 
-	workDir, err := os.Getwd()
-	if err != nil {
-		log.Println("failed to read dir:", err)
-		return
+// addErrors учитывает n сообщений, не отправленных из-за err.
+func (r *Report) addErrors(err error, n int) {
+	r.Failed += n
+	key := err.Error()
+	if _, ok := r.Errors[key]; !ok && len(r.Errors) >= maxErrorKinds {
+		key = otherErrors
 	}
-	dirEntry, err := os.ReadDir(path.Join(workDir, orderDir))
-	if err != nil {
-		log.Println("failed to read dir:", err)
-		return
+	r.Errors[key] += n
+}
+
+// Producer отправляет сообщения источника в Kafka с заданной скоростью.
+type Producer struct {
+	writer Writer
+	opts   Options
+	log    *slog.Logger
+}
+
+// NewProducer создает Producer поверх writer.
+func NewProducer(writer Writer, opts Options, log *slog.Logger) *Producer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1
 	}
+	return &Producer{writer: writer, opts: opts, log: log}
+}
+
+// Run отправляет сообщения source пачками, пока источник не закончится или не будет отменен ctx.
+// Скорость выдерживается по среднему с начала отправки: отставание догоняется пачками до batch_size.
+// progress, если задан, получает промежуточный отчет каждые ReportEvery.
+func (p *Producer) Run(ctx context.Context, source Source, progress func(Report)) (Report, error) {
+	report := Report{Errors: map[string]int{}}
+	start := time.Now()
+	lastProgress := start
+	attempted := 0
+	batch := make([]kafka.Message, 0, p.opts.BatchSize)
 
-	msgs := make([]kafka.Message, 0, len(dirEntry))
-	for _, entry := range dirEntry {
-		data, err := os.ReadFile(path.Join(workDir, orderDir, entry.Name())) // #nosec G304
-		if err != nil {
-			log.Println("failed to read file:", err)
-			return
+	for done := false; !done; {
+		if ctx.Err() != nil {
+			break
+		}
+
+		limit := p.opts.BatchSize
+		if p.opts.Rate > 0 {
+			due := int(p.opts.Rate*time.Since(start).Seconds()) - attempted
+			if due <= 0 {
+				next := start.Add(time.Duration(float64(attempted+1) / p.opts.Rate * float64(time.Second)))
+				if !sleep(ctx, time.Until(next)) {
+					break
+				}
+				continue
+			}
+			limit = min(limit, due)
+		}
+
+		batch = batch[:0]
+		for len(batch) < limit {
+			msg, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				done = true
+				break
+			}
+			if err != nil {
+				report.Elapsed = time.Since(start)
+				return report, fmt.Errorf("failed to read message: %w", err)
+			}
+			batch = append(batch, msg)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		attempted += len(batch)
+
+		p.write(ctx, batch, &report)
+
+		if progress != nil && p.opts.ReportEvery > 0 && time.Since(lastProgress) >= p.opts.ReportEvery {
+			lastProgress = time.Now()
+			report.Elapsed = time.Since(start)
+			progress(report)
 		}
-		msgs = append(msgs, kafka.Message{Value: data})
 	}
 
-	err = writer.WriteMessages(context.Background(), msgs...)
-	if err != nil {
-		log.Println("failed to send message:", err)
-		return
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+// write отправляет пачку и учитывает результат по каждому сообщению.
+func (p *Producer) write(ctx context.Context, batch []kafka.Message, report *Report) {
+	err := p.writer.WriteMessages(ctx, batch...)
+
+	var writeErrs kafka.WriteErrors
+	switch {
+	case err == nil:
+		for _, msg := range batch {
+			report.Sent++
+			report.Bytes += int64(len(msg.Value))
+		}
+	case errors.As(err, &writeErrs) && len(writeErrs) == len(batch):
+		for i, msgErr := range writeErrs {
+			if msgErr != nil {
+				report.addErrors(msgErr, 1)
+				continue
+			}
+			report.Sent++
+			report.Bytes += int64(len(batch[i].Value))
+		}
+	default:
+		report.addErrors(err, len(batch))
+		p.log.Debug("Failed to write batch", slog.Int("messages", len(batch)), slog.Any("error", err))
 	}
+}
 
-	log.Printf("Message sent to topic: %s successfully!\n", cfg.Topic)
+// sleep ждет d или отмены ctx и сообщает, можно ли продолжать.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
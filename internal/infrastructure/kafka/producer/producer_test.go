@@ -0,0 +1,210 @@
+package producer_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"order_service/internal/infrastructure/kafka/producer"
+	"order_service/internal/infrastructure/kafka/publisher"
+	"order_service/internal/logger"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWriter запоминает отправленные сообщения и отклоняет те, для которых reject вернул ошибку.
+type fakeWriter struct {
+	sent   []kafka.Message
+	reject func(msg kafka.Message) error
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	var errs kafka.WriteErrors
+	for _, msg := range msgs {
+		var err error
+		if w.reject != nil {
+			err = w.reject(msg)
+		}
+		errs = append(errs, err)
+		if err == nil {
+			w.sent = append(w.sent, msg)
+		}
+	}
+	if errs.Count() > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+// readAll читает все сообщения источника.
+func readAll(t *testing.T, source producer.Source) []kafka.Message {
+	var msgs []kafka.Message
+	for {
+		msg, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return msgs
+		}
+		require.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReplaySource(t *testing.T) {
+	headers := []kafka.Header{{Key: "content-type", Value: []byte("application/json")}}
+
+	t.Run("orders", func(t *testing.T) {
+		ndjson := writeFile(t, "orders.ndjson", `{"order_uid":"a1","items":[]}
+{"order_uid":"a2"}
+`)
+		pretty := writeFile(t, "order.json", "{\n  \"order_uid\": \"b1\",\n  \"track_number\": \"X\"\n}\n")
+		source, err := producer.NewReplaySource([]string{ndjson, pretty}, producer.ReplayOrders, headers)
+		require.NoError(t, err)
+		defer source.Close() //nolint:errcheck
+
+		msgs := readAll(t, source)
+		require.Len(t, msgs, 3)
+		for i, key := range []string{"a1", "a2", "b1"} {
+			require.Equal(t, key, string(msgs[i].Key))
+			require.Equal(t, headers, msgs[i].Headers)
+		}
+		require.JSONEq(t, `{"order_uid":"b1","track_number":"X"}`, string(msgs[2].Value))
+	})
+
+	t.Run("sample_orders", func(t *testing.T) {
+		files, err := filepath.Glob("orders/*.json")
+		require.NoError(t, err)
+		source, err := producer.NewReplaySource(files, producer.ReplayOrders, nil)
+		require.NoError(t, err)
+		msgs := readAll(t, source)
+		require.Len(t, msgs, len(files))
+		for _, msg := range msgs {
+			require.NotEmpty(t, msg.Key)
+		}
+	})
+
+	t.Run("dlq_dump", func(t *testing.T) {
+		dump := writeFile(t, "dlq.ndjson",
+			`{"topic":"my-topic.dlq","partition":0,"offset":5,"key":"c1","payload":"{\"order_uid\":\"c1\"}",`+
+				`"headers":["content-type","application/avro","schema-version","2","`+publisher.HeaderDLQReason+`","conflict","`+publisher.HeaderSourceOffset+`","17"]}
+{"topic":"my-topic.dlq","partition":1,"offset":6,"key":null,"payload":"{\"order_uid\":\"c2\"}","headers":[]}
+`)
+		source, err := producer.NewReplaySource([]string{dump}, producer.ReplayDLQ, headers)
+		require.NoError(t, err)
+
+		msgs := readAll(t, source)
+		require.Len(t, msgs, 2)
+		require.Equal(t, "c1", string(msgs[0].Key))
+		require.Equal(t, `{"order_uid":"c1"}`, string(msgs[0].Value))
+		require.Equal(t, []kafka.Header{
+			{Key: "content-type", Value: []byte("application/avro")},
+			{Key: "schema-version", Value: []byte("2")},
+		}, msgs[0].Headers)
+		// Без ключа и заголовков используются order_uid и заголовки по умолчанию
+		require.Equal(t, "c2", string(msgs[1].Key))
+		require.Equal(t, headers, msgs[1].Headers)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := producer.NewReplaySource(nil, "csv", nil)
+		require.Error(t, err)
+
+		broken := writeFile(t, "broken.ndjson", "{\"order_uid\":\"a1\"}\n{broken\n")
+		source, err := producer.NewReplaySource([]string{broken}, producer.ReplayOrders, nil)
+		require.NoError(t, err)
+		_, err = source.Next()
+		require.NoError(t, err)
+		_, err = source.Next()
+		require.ErrorContains(t, err, "broken.ndjson")
+		require.NoError(t, source.Close())
+
+		source, err = producer.NewReplaySource([]string{"missing.ndjson"}, producer.ReplayOrders, nil)
+		require.NoError(t, err)
+		_, err = source.Next()
+		require.Error(t, err)
+	})
+}
+
+func TestProducerRun(t *testing.T) {
+	t.Run("report", func(t *testing.T) {
+		writer := &fakeWriter{reject: func(msg kafka.Message) error {
+			if len(msg.Key) > 0 && msg.Key[0] < '8' {
+				return kafka.MessageSizeTooLarge
+			}
+			return nil
+		}}
+		source := producer.NewGeneratorSource(producer.NewFaker(3), 50, 0, nil)
+		p := producer.NewProducer(writer, producer.Options{BatchSize: 7}, logger.Discard())
+
+		report, err := p.Run(context.Background(), source, nil)
+		require.NoError(t, err)
+		require.Equal(t, 50, report.Sent+report.Failed)
+		require.Len(t, writer.sent, report.Sent)
+		require.Positive(t, report.Failed)
+		require.Equal(t, report.Failed, report.Errors[kafka.MessageSizeTooLarge.Error()])
+
+		var bytes int64
+		for _, msg := range writer.sent {
+			bytes += int64(len(msg.Value))
+		}
+		require.Equal(t, bytes, report.Bytes)
+	})
+
+	t.Run("batch_error", func(t *testing.T) {
+		writer := &failingWriter{err: errors.New("broker unavailable")}
+		source := producer.NewGeneratorSource(producer.NewFaker(3), 10, 0, nil)
+		p := producer.NewProducer(writer, producer.Options{BatchSize: 4}, logger.Discard())
+
+		report, err := p.Run(context.Background(), source, nil)
+		require.NoError(t, err)
+		require.Zero(t, report.Sent)
+		require.Equal(t, 10, report.Failed)
+		require.Equal(t, map[string]int{"broker unavailable": 10}, report.Errors)
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		writer := &fakeWriter{}
+		source := producer.NewGeneratorSource(producer.NewFaker(3), 20, 0, nil)
+		p := producer.NewProducer(writer, producer.Options{Rate: 100, BatchSize: 100}, logger.Discard())
+
+		report, err := p.Run(context.Background(), source, nil)
+		require.NoError(t, err)
+		require.Equal(t, 20, report.Sent)
+		// 20 сообщений при 100 msg/s занимают не меньше ~190 мс
+		require.GreaterOrEqual(t, report.Elapsed, 180*time.Millisecond)
+		require.LessOrEqual(t, report.Throughput(), 110.0)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		writer := &fakeWriter{}
+		source := producer.NewGeneratorSource(producer.NewFaker(3), 0, 0, nil)
+		p := producer.NewProducer(writer, producer.Options{Rate: 100, BatchSize: 10}, logger.Discard())
+
+		report, err := p.Run(ctx, source, nil)
+		require.NoError(t, err)
+		require.Less(t, report.Sent, 20)
+	})
+}
+
+// failingWriter отклоняет каждую запись целиком.
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) WriteMessages(context.Context, ...kafka.Message) error { return w.err }
+
+func (w *failingWriter) Close() error { return nil }
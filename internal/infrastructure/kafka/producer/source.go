@@ -0,0 +1,208 @@
+package producer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"order_service/internal/infrastructure/kafka/publisher"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Source — поток сообщений для отправки. Next возвращает io.EOF, когда сообщения закончились.
+type Source interface {
+	Next() (kafka.Message, error)
+}
+
+// GeneratorSource выдает синтетические заказы Faker'а и считает, сколько заказов испорчено каждым дефектом.
+type GeneratorSource struct {
+	faker        *Faker
+	headers      []kafka.Header
+	count        int
+	invalidRatio float64
+	generated    int
+	defects      map[Defect]int
+}
+
+// NewGeneratorSource создает источник count заказов (0 — без ограничения). Заголовки headers
+// добавляются к каждому сообщению.
+func NewGeneratorSource(faker *Faker, count int, invalidRatio float64, headers []kafka.Header) *GeneratorSource {
+	return &GeneratorSource{
+		faker:        faker,
+		headers:      headers,
+		count:        count,
+		invalidRatio: invalidRatio,
+		defects:      map[Defect]int{},
+	}
+}
+
+func (s *GeneratorSource) Next() (kafka.Message, error) {
+	if s.count > 0 && s.generated >= s.count {
+		return kafka.Message{}, io.EOF
+	}
+	msg, defect, err := s.faker.Message(s.invalidRatio)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	s.generated++
+	if defect != DefectNone {
+		s.defects[defect]++
+	}
+	msg.Headers = s.headers
+	return msg, nil
+}
+
+// Defects возвращает количество сгенерированных заказов по дефектам.
+func (s *GeneratorSource) Defects() map[Defect]int {
+	return s.defects
+}
+
+// ReplayFormat — формат файлов для повторной отправки.
+type ReplayFormat string
+
+const (
+	// ReplayOrders — JSON-заказы подряд: NDJSON или обычные JSON-файлы.
+	ReplayOrders ReplayFormat = "orders"
+	// ReplayDLQ — выгрузка топика DLQ в NDJSON в формате `kcat -C -J`: тело сообщения в поле payload,
+	// заголовки — плоским списком ключей и значений.
+	ReplayDLQ ReplayFormat = "dlq"
+)
+
+// dlqRecord — строка выгрузки `kcat -C -J`.
+type dlqRecord struct {
+	Topic     string   `json:"topic"`
+	Partition int      `json:"partition"`
+	Offset    int64    `json:"offset"`
+	Key       *string  `json:"key"`
+	Payload   *string  `json:"payload"`
+	Headers   []string `json:"headers"`
+}
+
+// dlqHeaders — заголовки, которые DLQ добавляет к сообщению; при повторной отправке они отбрасываются.
+var dlqHeaders = []string{
+	publisher.HeaderDLQReason, publisher.HeaderDLQDetail,
+	publisher.HeaderSourceTopic, publisher.HeaderSourcePartition, publisher.HeaderSourceOffset,
+}
+
+// ReplaySource читает сообщения из файлов по очереди.
+type ReplaySource struct {
+	paths   []string
+	format  ReplayFormat
+	headers []kafka.Header
+	file    *os.File
+	decoder *json.Decoder
+}
+
+// NewReplaySource создает источник, читающий файлы paths в формате format. Заголовки headers
+// добавляются к сообщениям, у которых своих заголовков нет.
+func NewReplaySource(paths []string, format ReplayFormat, headers []kafka.Header) (*ReplaySource, error) {
+	if format != ReplayOrders && format != ReplayDLQ {
+		return nil, fmt.Errorf("unknown replay format %q", format)
+	}
+	return &ReplaySource{paths: paths, format: format, headers: headers}, nil
+}
+
+func (s *ReplaySource) Next() (kafka.Message, error) {
+	for {
+		if s.decoder == nil {
+			if len(s.paths) == 0 {
+				return kafka.Message{}, io.EOF
+			}
+			file, err := os.Open(s.paths[0]) // #nosec G304 -- файлы указывает оператор
+			if err != nil {
+				return kafka.Message{}, fmt.Errorf("failed to open replay file: %w", err)
+			}
+			s.file, s.decoder = file, json.NewDecoder(bufio.NewReader(file))
+		}
+
+		raw := json.RawMessage{}
+		err := s.decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			if err := s.closeFile(); err != nil {
+				return kafka.Message{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return kafka.Message{}, fmt.Errorf("%s: byte %d: %w", s.paths[0], s.decoder.InputOffset(), err)
+		}
+
+		msg, err := s.message(raw)
+		if err != nil {
+			return kafka.Message{}, fmt.Errorf("%s: byte %d: %w", s.paths[0], s.decoder.InputOffset(), err)
+		}
+		return msg, nil
+	}
+}
+
+// message переводит прочитанное значение в сообщение Kafka.
+func (s *ReplaySource) message(raw json.RawMessage) (kafka.Message, error) {
+	if s.format == ReplayOrders {
+		return kafka.Message{Key: orderKey(raw), Value: raw, Headers: s.headers}, nil
+	}
+
+	record := dlqRecord{}
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return kafka.Message{}, err
+	}
+	if record.Payload == nil {
+		return kafka.Message{}, errors.New("dlq record has no payload")
+	}
+	msg := kafka.Message{Value: []byte(*record.Payload)}
+	if record.Key != nil && *record.Key != "" {
+		msg.Key = []byte(*record.Key)
+	} else {
+		msg.Key = orderKey(msg.Value)
+	}
+	for i := 0; i+1 < len(record.Headers); i += 2 {
+		key := record.Headers[i]
+		if isDLQHeader(key) {
+			continue
+		}
+		msg.Headers = append(msg.Headers, kafka.Header{Key: key, Value: []byte(record.Headers[i+1])})
+	}
+	if len(msg.Headers) == 0 {
+		msg.Headers = s.headers
+	}
+	return msg, nil
+}
+
+// closeFile закрывает текущий файл и переходит к следующему.
+func (s *ReplaySource) closeFile() error {
+	err := s.file.Close()
+	s.paths, s.file, s.decoder = s.paths[1:], nil, nil
+	return err
+}
+
+// Close закрывает открытый файл, если чтение прервано.
+func (s *ReplaySource) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.closeFile()
+}
+
+// orderKey возвращает order_uid заказа для ключа сообщения или nil, если его не прочитать.
+func orderKey(value []byte) []byte {
+	order := struct {
+		OrderUID string `json:"order_uid"`
+	}{}
+	if err := json.Unmarshal(value, &order); err != nil || order.OrderUID == "" {
+		return nil
+	}
+	return []byte(order.OrderUID)
+}
+
+func isDLQHeader(key string) bool {
+	for _, header := range dlqHeaders {
+		if strings.EqualFold(header, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Заголовки сообщения DLQ: причина отказа и позиция исходного сообщения.
+const (
+	HeaderDLQReason       = "dlq-reason"
+	HeaderDLQDetail       = "dlq-detail"
+	HeaderSourceTopic     = "source-topic"
+	HeaderSourcePartition = "source-partition"
+	HeaderSourceOffset    = "source-offset"
+)
+
+// dlqHeaders — заголовки, которые DLQ выставляет сама.
+var dlqHeaders = []string{HeaderDLQReason, HeaderDLQDetail, HeaderSourceTopic, HeaderSourcePartition, HeaderSourceOffset}
+
+// DeadLetterQueue копирует сообщения, которые нельзя обработать автоматически, в топик DLQ.
+// Ключ, тело и заголовки исходного сообщения (content-type, schema-version, trace context)
+// сохраняются без изменений, поэтому сообщение можно отправить повторно как есть; заголовки
+// DLQ добавляются после них.
+type DeadLetterQueue struct {
+	writer Writer
+	topic  string
+	log    *slog.Logger
+}
+
+// NewDeadLetterQueue создает DLQ поверх writer. topic используется только в трассировке и логах.
+func NewDeadLetterQueue(writer Writer, topic string, log *slog.Logger) *DeadLetterQueue {
+	log.Debug("Initializing Kafka Dead Letter Queue", slog.String("topic", topic))
+	return &DeadLetterQueue{writer: writer, topic: topic, log: log}
+}
+
+// NewDLQWriter создает writer для топика kafka.dlq_topic с подтверждением всех реплик.
+func NewDLQWriter(cfg *config.Config) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.DLQTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+}
+
+// Send записывает сообщение в DLQ.
+func (q *DeadLetterQueue) Send(ctx context.Context, letter domain.DeadLetter) error {
+	ctx, span := tracer.Start(ctx, "DeadLetterQueue.Send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(q.topic),
+		),
+	)
+	defer span.End()
+
+	headers := make([]kafka.Header, 0, len(letter.Headers)+len(dlqHeaders))
+	for _, h := range letter.Headers {
+		// Сообщение, уже побывавшее в DLQ, получает заголовки DLQ заново
+		if !slices.Contains(dlqHeaders, h.Key) {
+			headers = append(headers, kafka.Header{Key: h.Key, Value: h.Value})
+		}
+	}
+	headers = append(headers,
+		kafka.Header{Key: HeaderDLQReason, Value: []byte(letter.Reason)},
+		kafka.Header{Key: HeaderDLQDetail, Value: []byte(letter.Detail)},
+		kafka.Header{Key: HeaderSourceTopic, Value: []byte(letter.Topic)},
+		kafka.Header{Key: HeaderSourcePartition, Value: []byte(strconv.Itoa(letter.Partition))},
+		kafka.Header{Key: HeaderSourceOffset, Value: []byte(strconv.FormatInt(letter.Offset, 10))},
+	)
+	msg := kafka.Message{Key: letter.Key, Value: letter.Value, Headers: headers}
+
+	if err := q.writer.WriteMessages(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to write message")
+		// Сообщение нельзя коммитить, пока оно не в DLQ: отправку имеет смысл повторять
+		return fmt.Errorf("%w: failed to send message to DLQ: %w", domain.ErrUnavailable, err)
+	}
+
+	q.log.WarnContext(ctx, "Message sent to DLQ",
+		slog.String("reason", letter.Reason),
+		slog.String("source_topic", letter.Topic),
+		slog.Int("source_partition", letter.Partition),
+		slog.Int64("source_offset", letter.Offset))
+	return nil
+}
+
+// Close закрывает writer, дожидаясь отправки буферизованных сообщений.
+func (q *DeadLetterQueue) Close() error {
+	return q.writer.Close()
+}
@@ -0,0 +1,154 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("order_service/internal/infrastructure/kafka/publisher")
+
+// HeaderEventType — заголовок сообщения с типом события, чтобы получатель мог
+// отфильтровать события без разбора тела.
+const HeaderEventType = "event-type"
+
+// Writer — часть kafka.Writer, нужная publisher'у. В тестах подменяется фейком.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Publisher отправляет события outbox в Kafka.
+type Publisher struct {
+	writer Writer
+	topic  string
+	log    *slog.Logger
+}
+
+// NewPublisher создает publisher поверх writer. topic используется только в трассировке:
+// топик задается в самом writer.
+func NewPublisher(writer Writer, topic string, log *slog.Logger) *Publisher {
+	log.Debug("Initializing Kafka Publisher", slog.String("topic", topic))
+	return &Publisher{writer: writer, topic: topic, log: log}
+}
+
+// NewKafkaWriter создает writer для топика outbox.topic. Сообщения распределяются
+// по партициям хешем ключа (order_uid), поэтому события одного заказа не переупорядочиваются.
+// Запись синхронная и ждет подтверждения всех реплик: событие считается опубликованным,
+// только когда его не потерять.
+func NewKafkaWriter(cfg *config.Config) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Outbox.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		MaxAttempts:  cfg.Outbox.WriteAttempts,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+}
+
+// Publish отправляет пачку одним вызовом в порядке событий. Заголовки события
+// (в том числе trace context исходного запроса) переносятся в заголовки сообщения.
+// Ошибки отдельных сообщений возвращаются как domain.PublishErrors: слишком большое
+// сообщение исключается из пачки, и остальные отправляются повторно.
+func (p *Publisher) Publish(ctx context.Context, events []domain.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "Publisher.Publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(p.topic),
+			semconv.MessagingBatchMessageCount(len(events)),
+		),
+	)
+	defer span.End()
+
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		headers := make([]kafka.Header, 0, len(event.Headers)+1)
+		headers = append(headers, kafka.Header{Key: HeaderEventType, Value: []byte(event.Type)})
+		for _, key := range slices.Sorted(maps.Keys(event.Headers)) {
+			headers = append(headers, kafka.Header{Key: key, Value: []byte(event.Headers[key])})
+		}
+
+		msgs = append(msgs, kafka.Message{
+			Key:     []byte(event.Key),
+			Value:   event.Payload,
+			Headers: headers,
+			Time:    event.CreatedAt,
+		})
+	}
+
+	failed := domain.PublishErrors{}
+	pending := events
+	for len(pending) > 0 {
+		err := p.writer.WriteMessages(ctx, msgs...)
+		if err == nil {
+			break
+		}
+
+		var tooLarge kafka.MessageTooLargeError
+		var writeErrors kafka.WriteErrors
+		switch {
+		case errors.As(err, &tooLarge):
+			// writer ничего не отправил: исключаем сообщение и повторяем остальные
+			i := tooLargeIndex(msgs, tooLarge)
+			failed[pending[i].ID] = err
+			pending = slices.Delete(slices.Clone(pending), i, i+1)
+			msgs = tooLarge.Remaining
+			continue
+		case errors.As(err, &writeErrors) && len(writeErrors) == len(msgs):
+			for i, writeErr := range writeErrors {
+				if writeErr != nil {
+					failed[pending[i].ID] = writeErr
+				}
+			}
+		case len(failed) == 0:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to write messages")
+			return fmt.Errorf("failed to publish %d events: %w", len(events), err)
+		default:
+			for _, event := range pending {
+				failed[event.ID] = err
+			}
+		}
+		break
+	}
+
+	if len(failed) > 0 {
+		span.RecordError(failed)
+		span.SetStatus(codes.Error, "failed to write some messages")
+		return failed
+	}
+	return nil
+}
+
+// tooLargeIndex находит в msgs сообщение, которое writer отверг как слишком большое:
+// Remaining — msgs без него в том же порядке.
+func tooLargeIndex(msgs []kafka.Message, err kafka.MessageTooLargeError) int {
+	for i, remaining := range err.Remaining {
+		if !bytes.Equal(msgs[i].Key, remaining.Key) || !bytes.Equal(msgs[i].Value, remaining.Value) ||
+			len(msgs[i].Headers) != len(remaining.Headers) {
+			return i
+		}
+	}
+	return len(err.Remaining)
+}
+
+// Close закрывает writer, дожидаясь отправки буферизованных сообщений.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,160 @@
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/kafka/publisher"
+	"order_service/internal/logger"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWriter запоминает отправленные сообщения вместо записи в Kafka. Ошибки из script
+// возвращаются по одной на вызов, после них — err.
+type fakeWriter struct {
+	messages []kafka.Message
+	script   []func(msgs []kafka.Message) error
+	err      error
+	closed   bool
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if len(w.script) > 0 {
+		next := w.script[0]
+		w.script = w.script[1:]
+		if err := next(msgs); err != nil {
+			return err
+		}
+	}
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	createdAt := time.Date(2024, 1, 7, 6, 22, 8, 0, time.UTC)
+	events := []domain.OutboxEvent{
+		{
+			ID: 1, Type: domain.EventOrderCreated, Key: "order_1", Payload: []byte(`{"order_uid":"order_1"}`),
+			Headers:   map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "tracestate": "a=b"},
+			CreatedAt: createdAt,
+		},
+		{ID: 2, Type: domain.EventOrderCreated, Key: "order_2", Payload: []byte(`{"order_uid":"order_2"}`), CreatedAt: createdAt},
+	}
+
+	writer := &fakeWriter{}
+	p := publisher.NewPublisher(writer, "orders.events", logger.Discard())
+	require.NoError(t, p.Publish(context.Background(), events))
+
+	require.Len(t, writer.messages, 2)
+	first := writer.messages[0]
+	require.Equal(t, "order_1", string(first.Key), "order_uid is the key, so one order's events share a partition")
+	require.Equal(t, `{"order_uid":"order_1"}`, string(first.Value))
+	require.Equal(t, createdAt, first.Time)
+	require.Equal(t, []kafka.Header{
+		{Key: publisher.HeaderEventType, Value: []byte(domain.EventOrderCreated)},
+		{Key: "traceparent", Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")},
+		{Key: "tracestate", Value: []byte("a=b")},
+	}, first.Headers)
+	require.Equal(t, "order_2", string(writer.messages[1].Key))
+
+	brokerDown := errors.New("broker unavailable")
+	failing := publisher.NewPublisher(&fakeWriter{err: brokerDown}, "orders.events", logger.Discard())
+	require.ErrorIs(t, failing.Publish(context.Background(), events), brokerDown)
+
+	require.NoError(t, p.Close())
+	require.True(t, writer.closed)
+}
+
+func TestPublishPartialFailure(t *testing.T) {
+	events := []domain.OutboxEvent{
+		{ID: 1, Type: domain.EventOrderCreated, Key: "order_1", Payload: []byte(`{}`)},
+		{ID: 2, Type: domain.EventOrderCreated, Key: "order_2", Payload: []byte(`{"big":true}`)},
+		{ID: 3, Type: domain.EventOrderCreated, Key: "order_3", Payload: []byte(`{}`)},
+	}
+
+	t.Run("write_errors", func(t *testing.T) {
+		leaderMoved := errors.New("not leader for partition")
+		writer := &fakeWriter{script: []func([]kafka.Message) error{
+			func([]kafka.Message) error { return kafka.WriteErrors{nil, leaderMoved, nil} },
+		}}
+		p := publisher.NewPublisher(writer, "orders.events", logger.Discard())
+
+		var failed domain.PublishErrors
+		require.ErrorAs(t, p.Publish(context.Background(), events), &failed)
+		require.Equal(t, domain.PublishErrors{2: leaderMoved}, failed)
+	})
+
+	t.Run("message_too_large", func(t *testing.T) {
+		// Как kafka.Writer: сообщение больше batch_bytes отвергается до отправки пачки
+		tooLarge := func(msgs []kafka.Message) error {
+			for i, msg := range msgs {
+				if string(msg.Value) == `{"big":true}` {
+					return kafka.MessageTooLargeError{Message: msg, Remaining: slices.Delete(slices.Clone(msgs), i, i+1)}
+				}
+			}
+			return nil
+		}
+		writer := &fakeWriter{script: []func([]kafka.Message) error{tooLarge, tooLarge}}
+		p := publisher.NewPublisher(writer, "orders.events", logger.Discard())
+
+		var failed domain.PublishErrors
+		require.ErrorAs(t, p.Publish(context.Background(), events), &failed)
+		require.Len(t, failed, 1)
+		require.ErrorIs(t, failed[2], kafka.MessageSizeTooLarge)
+
+		require.Len(t, writer.messages, 2, "the rest of the batch is published")
+		require.Equal(t, "order_1", string(writer.messages[0].Key))
+		require.Equal(t, "order_3", string(writer.messages[1].Key))
+	})
+}
+
+func TestDeadLetterQueueSend(t *testing.T) {
+	writer := &fakeWriter{}
+	dlq := publisher.NewDeadLetterQueue(writer, "orders.dlq", logger.Discard())
+
+	letter := domain.DeadLetter{
+		Topic: "orders", Partition: 3, Offset: 120,
+		Key: []byte("order_1"), Value: []byte(`{"order_uid":"order_1"}`),
+		Headers: []domain.MessageHeader{
+			{Key: "content-type", Value: []byte("application/json")},
+			{Key: "traceparent", Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")},
+			{Key: publisher.HeaderDLQReason, Value: []byte("stale")},
+		},
+		Reason: domain.DeadLetterConflict, Detail: "order order_1 is already saved with different content",
+	}
+	require.NoError(t, dlq.Send(context.Background(), letter))
+
+	require.Len(t, writer.messages, 1)
+	msg := writer.messages[0]
+	require.Equal(t, letter.Key, msg.Key)
+	require.Equal(t, letter.Value, msg.Value, "the original message is kept as is, so it can be re-sent")
+	require.Equal(t, []kafka.Header{
+		{Key: "content-type", Value: []byte("application/json")},
+		{Key: "traceparent", Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")},
+		{Key: publisher.HeaderDLQReason, Value: []byte("conflict")},
+		{Key: publisher.HeaderDLQDetail, Value: []byte(letter.Detail)},
+		{Key: publisher.HeaderSourceTopic, Value: []byte("orders")},
+		{Key: publisher.HeaderSourcePartition, Value: []byte("3")},
+		{Key: publisher.HeaderSourceOffset, Value: []byte("120")},
+	}, msg.Headers)
+
+	brokerDown := errors.New("broker unavailable")
+	failing := publisher.NewDeadLetterQueue(&fakeWriter{err: brokerDown}, "orders.dlq", logger.Discard())
+	err := failing.Send(context.Background(), letter)
+	require.ErrorIs(t, err, brokerDown)
+	require.ErrorIs(t, err, domain.ErrUnavailable, "sending to the DLQ is retried")
+}
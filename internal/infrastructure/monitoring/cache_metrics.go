@@ -0,0 +1,84 @@
+package monitoring
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PrometheusCacheMetrics struct {
+	hits        prometheus.Counter
+	misses      prometheus.Counter
+	evictions   prometheus.Counter
+	expirations prometheus.Counter
+	size        prometheus.Gauge
+}
+
+func NewPrometheusCacheMetrics() (*PrometheusCacheMetrics, error) {
+	metrics := &PrometheusCacheMetrics{
+		hits: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_cache_hits_total",
+				Help: "Количество попаданий в кеш",
+			}),
+
+		misses: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_cache_misses_total",
+				Help: "Количество промахов кеша",
+			}),
+
+		evictions: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_cache_evictions_total",
+				Help: "Количество вытеснений из кеша при превышении емкости",
+			}),
+
+		expirations: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_cache_expirations_total",
+				Help: "Количество записей, удаленных из кеша по TTL",
+			}),
+
+		size: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "app_cache_size",
+				Help: "Текущее количество записей в кеше",
+			}),
+	}
+
+	collectors := []prometheus.Collector{
+		metrics.hits,
+		metrics.misses,
+		metrics.evictions,
+		metrics.expirations,
+		metrics.size,
+	}
+	for _, collector := range collectors {
+		if err := prometheus.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to registered metric: %w", err)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (m *PrometheusCacheMetrics) IncHit() {
+	m.hits.Inc()
+}
+
+func (m *PrometheusCacheMetrics) IncMiss() {
+	m.misses.Inc()
+}
+
+func (m *PrometheusCacheMetrics) IncEviction() {
+	m.evictions.Inc()
+}
+
+func (m *PrometheusCacheMetrics) IncExpiration() {
+	m.expirations.Inc()
+}
+
+func (m *PrometheusCacheMetrics) SetSize(size int) {
+	m.size.Set(float64(size))
+}
@@ -0,0 +1,35 @@
+package monitoring_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/infrastructure/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusCacheMetrics(t *testing.T) {
+	metrics, err := monitoring.NewPrometheusCacheMetrics()
+	require.NoError(t, err)
+
+	metrics.IncHit()
+	metrics.IncHit()
+	metrics.IncMiss()
+	metrics.IncEviction()
+	metrics.IncExpiration()
+	metrics.SetSize(42)
+
+	metricsHandler := promhttp.Handler()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(w, req)
+
+	bodyStr := w.Body.String()
+	require.Contains(t, bodyStr, "app_cache_hits_total 2")
+	require.Contains(t, bodyStr, "app_cache_misses_total 1")
+	require.Contains(t, bodyStr, "app_cache_evictions_total 1")
+	require.Contains(t, bodyStr, "app_cache_expirations_total 1")
+	require.Contains(t, bodyStr, "app_cache_size 42")
+}
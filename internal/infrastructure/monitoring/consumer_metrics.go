@@ -0,0 +1,181 @@
+package monitoring
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PrometheusConsumerMetrics struct {
+	messagesConsumed   prometheus.Counter
+	decodeFailures     prometheus.Counter
+	validationFailures *prometheus.CounterVec
+	duplicates         prometheus.Counter
+	conflicts          prometheus.Counter
+	saveDuration       prometheus.Histogram
+	commitDuration     prometheus.Histogram
+	consumerLag        *prometheus.GaugeVec
+	readerLag          prometheus.Gauge
+
+	ordersByDeliveryService *prometheus.CounterVec
+	ordersByCurrency        *prometheus.CounterVec
+	ordersByLocale          *prometheus.CounterVec
+}
+
+func NewPrometheusConsumerMetrics() (*PrometheusConsumerMetrics, error) {
+	metrics := &PrometheusConsumerMetrics{
+		messagesConsumed: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_kafka_messages_consumed_total",
+				Help: "Количество сообщений, прочитанных из Kafka",
+			}),
+
+		decodeFailures: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_kafka_decode_failures_total",
+				Help: "Количество сообщений, которые не удалось декодировать",
+			}),
+
+		validationFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "app_order_validation_failures_total",
+				Help: "Количество заказов, не прошедших валидацию, по правилам",
+			},
+			[]string{"rule"},
+		),
+
+		duplicates: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_order_duplicates_total",
+				Help: "Количество повторно полученных заказов, пропущенных без сохранения",
+			}),
+
+		conflicts: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "app_order_conflicts_total",
+				Help: "Количество заказов, повторно полученных с другим содержимым",
+			}),
+
+		saveDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "app_order_save_duration_seconds",
+				Help:    "Время сохранения заказа",
+				Buckets: prometheus.DefBuckets,
+			},
+		),
+
+		commitDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "app_kafka_commit_duration_seconds",
+				Help:    "Время коммита смещения в Kafka",
+				Buckets: prometheus.DefBuckets,
+			},
+		),
+
+		consumerLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "app_kafka_consumer_lag",
+				Help: "Отставание consumer'а от конца партиции",
+			},
+			[]string{"partition"},
+		),
+
+		readerLag: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "app_kafka_reader_lag",
+				Help: "Отставание по статистике Kafka reader'а без разбивки по партициям",
+			}),
+
+		ordersByDeliveryService: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "app_orders_by_delivery_service_total",
+				Help: "Количество принятых заказов по службе доставки",
+			},
+			[]string{"delivery_service"},
+		),
+
+		ordersByCurrency: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "app_orders_by_currency_total",
+				Help: "Количество принятых заказов по валюте",
+			},
+			[]string{"currency"},
+		),
+
+		ordersByLocale: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "app_orders_by_locale_total",
+				Help: "Количество принятых заказов по локали",
+			},
+			[]string{"locale"},
+		),
+	}
+
+	collectors := []prometheus.Collector{
+		metrics.messagesConsumed,
+		metrics.decodeFailures,
+		metrics.validationFailures,
+		metrics.duplicates,
+		metrics.conflicts,
+		metrics.saveDuration,
+		metrics.commitDuration,
+		metrics.consumerLag,
+		metrics.readerLag,
+		metrics.ordersByDeliveryService,
+		metrics.ordersByCurrency,
+		metrics.ordersByLocale,
+	}
+	for _, collector := range collectors {
+		if err := prometheus.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to registered metric: %w", err)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (m *PrometheusConsumerMetrics) IncConsumed() {
+	m.messagesConsumed.Inc()
+}
+
+func (m *PrometheusConsumerMetrics) IncDecodeFailure() {
+	m.decodeFailures.Inc()
+}
+
+func (m *PrometheusConsumerMetrics) IncValidationFailure(rule string) {
+	m.validationFailures.WithLabelValues(rule).Inc()
+}
+
+func (m *PrometheusConsumerMetrics) IncDuplicate() {
+	m.duplicates.Inc()
+}
+
+func (m *PrometheusConsumerMetrics) IncConflict() {
+	m.conflicts.Inc()
+}
+
+func (m *PrometheusConsumerMetrics) ObserveSave(start time.Time) {
+	m.saveDuration.Observe(time.Since(start).Seconds())
+}
+
+func (m *PrometheusConsumerMetrics) ObserveCommit(start time.Time) {
+	m.commitDuration.Observe(time.Since(start).Seconds())
+}
+
+func (m *PrometheusConsumerMetrics) SetLag(partition int, lag int64) {
+	m.consumerLag.WithLabelValues(strconv.Itoa(partition)).Set(float64(lag))
+}
+
+func (m *PrometheusConsumerMetrics) SetReaderLag(lag int64) {
+	m.readerLag.Set(float64(lag))
+}
+
+func (m *PrometheusConsumerMetrics) IncOrder(order *domain.Order) {
+	m.ordersByDeliveryService.WithLabelValues(order.DeliveryService).Inc()
+	m.ordersByCurrency.WithLabelValues(order.Currency).Inc()
+	m.ordersByLocale.WithLabelValues(order.Locale).Inc()
+}
@@ -0,0 +1,58 @@
+package monitoring_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusConsumerMetrics(t *testing.T) {
+	metrics, err := monitoring.NewPrometheusConsumerMetrics()
+	require.NoError(t, err)
+
+	order := &domain.Order{
+		Locale:          "en",
+		DeliveryService: "meest",
+		Payment:         domain.Payment{Currency: "USD"},
+	}
+
+	for i := 0; i < 3; i++ {
+		metrics.IncConsumed()
+		metrics.ObserveCommit(time.Now())
+	}
+	metrics.IncDecodeFailure()
+	metrics.IncValidationFailure(domain.ValidationRule(domain.ErrNoItems))
+	metrics.IncDuplicate()
+	metrics.IncDuplicate()
+	metrics.IncConflict()
+	metrics.ObserveSave(time.Now())
+	metrics.SetLag(2, 7)
+	metrics.SetReaderLag(4)
+	metrics.IncOrder(order)
+	metrics.IncOrder(order)
+
+	metricsHandler := promhttp.Handler()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(w, req)
+
+	bodyStr := w.Body.String()
+	require.Contains(t, bodyStr, "app_kafka_messages_consumed_total 3")
+	require.Contains(t, bodyStr, "app_kafka_decode_failures_total 1")
+	require.Contains(t, bodyStr, `app_order_validation_failures_total{rule="items_required"} 1`)
+	require.Contains(t, bodyStr, "app_order_duplicates_total 2")
+	require.Contains(t, bodyStr, "app_order_conflicts_total 1")
+	require.Contains(t, bodyStr, "app_order_save_duration_seconds_count 1")
+	require.Contains(t, bodyStr, "app_kafka_commit_duration_seconds_count 3")
+	require.Contains(t, bodyStr, `app_kafka_consumer_lag{partition="2"} 7`)
+	require.Contains(t, bodyStr, "app_kafka_reader_lag 4")
+	require.Contains(t, bodyStr, `app_orders_by_delivery_service_total{delivery_service="meest"} 2`)
+	require.Contains(t, bodyStr, `app_orders_by_currency_total{currency="USD"} 2`)
+	require.Contains(t, bodyStr, `app_orders_by_locale_total{locale="en"} 2`)
+}
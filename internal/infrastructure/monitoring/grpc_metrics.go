@@ -0,0 +1,47 @@
+package monitoring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PrometheusGRPCMetrics struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func NewPrometheusGRPCMetrics() (*PrometheusGRPCMetrics, error) {
+	metrics := &PrometheusGRPCMetrics{
+		requestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "app_grpc_requests_total",
+				Help: "Количество gRPC вызовов по методам и кодам ответа",
+			},
+			[]string{"method", "code"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "app_grpc_request_duration_seconds",
+				Help:    "Время обработки gRPC вызова",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"method"},
+		),
+	}
+
+	if err := prometheus.Register(metrics.requestsTotal); err != nil {
+		return nil, fmt.Errorf("failed to registered metric: %w", err)
+	}
+	if err := prometheus.Register(metrics.requestDuration); err != nil {
+		return nil, fmt.Errorf("failed to registered metric: %w", err)
+	}
+
+	return metrics, nil
+}
+
+func (m *PrometheusGRPCMetrics) ObserveRPC(method, code string, start time.Time) {
+	m.requestsTotal.WithLabelValues(method, code).Inc()
+	m.requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,31 @@
+package monitoring_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"order_service/internal/infrastructure/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusGRPCMetrics(t *testing.T) {
+	metrics, err := monitoring.NewPrometheusGRPCMetrics()
+	require.NoError(t, err)
+
+	metrics.ObserveRPC("/order.v1.OrderService/GetOrder", "OK", time.Now())
+	metrics.ObserveRPC("/order.v1.OrderService/GetOrder", "NotFound", time.Now())
+	metrics.ObserveRPC("/order.v1.OrderService/GetOrder", "OK", time.Now())
+
+	metricsHandler := promhttp.Handler()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(w, req)
+
+	bodyStr := w.Body.String()
+	require.Contains(t, bodyStr, `app_grpc_requests_total{code="OK",method="/order.v1.OrderService/GetOrder"} 2`)
+	require.Contains(t, bodyStr, `app_grpc_requests_total{code="NotFound",method="/order.v1.OrderService/GetOrder"} 1`)
+	require.Contains(t, bodyStr, `app_grpc_request_duration_seconds_count{method="/order.v1.OrderService/GetOrder"} 3`)
+}
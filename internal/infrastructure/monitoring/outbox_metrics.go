@@ -0,0 +1,59 @@
+package monitoring
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PrometheusOutboxMetrics struct {
+	published       prometheus.Counter
+	publishFailures prometheus.Counter
+	dead            prometheus.Counter
+	cleaned         prometheus.Counter
+}
+
+func NewPrometheusOutboxMetrics() (*PrometheusOutboxMetrics, error) {
+	metrics := &PrometheusOutboxMetrics{
+		published: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_outbox_published_total",
+			Help: "Количество событий outbox, опубликованных в Kafka",
+		}),
+		publishFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_outbox_publish_failures_total",
+			Help: "Количество неудачных попыток опубликовать пачку событий outbox",
+		}),
+		dead: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_outbox_dead_total",
+			Help: "Количество событий outbox, исчерпавших попытки публикации",
+		}),
+		cleaned: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "app_outbox_cleaned_total",
+			Help: "Количество удаленных опубликованных событий outbox",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{metrics.published, metrics.publishFailures, metrics.dead, metrics.cleaned} {
+		if err := prometheus.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to registered metric: %w", err)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (m *PrometheusOutboxMetrics) AddPublished(n int) {
+	m.published.Add(float64(n))
+}
+
+func (m *PrometheusOutboxMetrics) IncPublishFailure() {
+	m.publishFailures.Inc()
+}
+
+func (m *PrometheusOutboxMetrics) AddDead(n int) {
+	m.dead.Add(float64(n))
+}
+
+func (m *PrometheusOutboxMetrics) AddCleaned(n int) {
+	m.cleaned.Add(float64(n))
+}
@@ -0,0 +1,33 @@
+package monitoring_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"order_service/internal/infrastructure/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusOutboxMetrics(t *testing.T) {
+	metrics, err := monitoring.NewPrometheusOutboxMetrics()
+	require.NoError(t, err)
+
+	metrics.AddPublished(100)
+	metrics.AddPublished(3)
+	metrics.IncPublishFailure()
+	metrics.AddDead(2)
+	metrics.AddCleaned(42)
+
+	metricsHandler := promhttp.Handler()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(w, req)
+
+	bodyStr := w.Body.String()
+	require.Contains(t, bodyStr, `app_outbox_published_total 103`)
+	require.Contains(t, bodyStr, `app_outbox_publish_failures_total 1`)
+	require.Contains(t, bodyStr, `app_outbox_dead_total 2`)
+	require.Contains(t, bodyStr, `app_outbox_cleaned_total 42`)
+}
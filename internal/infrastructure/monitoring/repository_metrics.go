@@ -0,0 +1,45 @@
+package monitoring
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+type PrometheusRepositoryMetrics struct {
+	queryDuration *prometheus.HistogramVec
+}
+
+func NewPrometheusRepositoryMetrics() (*PrometheusRepositoryMetrics, error) {
+	metrics := &PrometheusRepositoryMetrics{
+		queryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "app_db_query_duration_seconds",
+				Help:    "Время выполнения SQL-запроса",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"query"},
+		),
+	}
+
+	if err := prometheus.Register(metrics.queryDuration); err != nil {
+		return nil, fmt.Errorf("failed to registered metric: %w", err)
+	}
+
+	return metrics, nil
+}
+
+func (m *PrometheusRepositoryMetrics) ObserveQuery(query string, start time.Time) {
+	m.queryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
+
+// RegisterDBStats экспортирует статистику пула соединений sql.DB (go_sql_* метрики).
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	if err := prometheus.Register(collectors.NewDBStatsCollector(db, dbName)); err != nil {
+		return fmt.Errorf("failed to registered metric: %w", err)
+	}
+	return nil
+}
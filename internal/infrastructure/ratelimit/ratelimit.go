@@ -0,0 +1,114 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом token bucket.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+)
+
+// bucket хранит число токенов на момент last. Токены восполняются лениво при обращении.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type TokenBucketLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   int
+	buckets *simplelru.LRU[string, *bucket]
+}
+
+// NewTokenBucketLimiter создает limiter, который пропускает в среднем rps запросов в секунду
+// на ключ и допускает всплески до burst запросов. Хранится не больше maxKeys bucket'ов,
+// давно не активные вытесняются первыми. TTL bucket'ам не нужен: токены восполняются
+// при обращении, и bucket неактивного клиента наполнится полностью.
+func NewTokenBucketLimiter(rps float64, burst, maxKeys int) *TokenBucketLimiter {
+	// Ошибку NewLRU возвращает только для неположительного maxKeys, его отсекает проверка конфигурации
+	buckets, _ := simplelru.NewLRU[string, *bucket](maxKeys, nil)
+	return &TokenBucketLimiter{
+		rate:    rps,
+		burst:   burst,
+		buckets: buckets,
+	}
+}
+
+// Allow списывает токен из bucket'а ключа, если он есть.
+func (l *TokenBucketLimiter) Allow(key string) domain.RateLimitDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+	}
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	decision := domain.RateLimitDecision{Limit: l.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = l.duration(1 - b.tokens)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.ResetAfter = l.duration(float64(l.burst) - b.tokens)
+
+	l.buckets.Add(key, b)
+
+	return decision
+}
+
+// duration возвращает время, за которое восполнится tokens токенов.
+func (l *TokenBucketLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// ReloadableLimiter — limiter маршрута, лимит которого меняется во время работы.
+// Пока лимит не задан, запросы пропускаются без проверки.
+type ReloadableLimiter struct {
+	mu      sync.Mutex
+	limiter atomic.Pointer[TokenBucketLimiter]
+	maxKeys int
+}
+
+// NewReloadableLimiter создает limiter без лимита. Bucket'ов хранится не больше maxKeys.
+func NewReloadableLimiter(maxKeys int) *ReloadableLimiter {
+	return &ReloadableLimiter{maxKeys: maxKeys}
+}
+
+// SetLimit задает лимит rps запросов в секунду со всплесками до burst. При rps <= 0
+// или burst <= 0 лимит снимается. Новый лимит начинается с полных bucket'ов, прежний
+// limiter не держит горутин и освобождается сборщиком мусора.
+func (l *ReloadableLimiter) SetLimit(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.limiter.Load()
+	if current != nil && current.rate == rps && current.burst == burst {
+		return
+	}
+
+	var next *TokenBucketLimiter
+	if rps > 0 && burst > 0 {
+		next = NewTokenBucketLimiter(rps, burst, l.maxKeys)
+	}
+	l.limiter.Store(next)
+}
+
+// Allow проверяет запрос по текущему лимиту.
+func (l *ReloadableLimiter) Allow(key string) domain.RateLimitDecision {
+	limiter := l.limiter.Load()
+	if limiter == nil {
+		return domain.RateLimitDecision{Allowed: true, Unlimited: true}
+	}
+	return limiter.Allow(key)
+}
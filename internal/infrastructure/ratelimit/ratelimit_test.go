@@ -0,0 +1,82 @@
+package ratelimit_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"order_service/internal/infrastructure/ratelimit"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	limiter := ratelimit.NewTokenBucketLimiter(1, 2, 10)
+
+	first := limiter.Allow("client")
+	require.True(t, first.Allowed)
+	require.Equal(t, 2, first.Limit)
+	require.Equal(t, 1, first.Remaining)
+
+	second := limiter.Allow("client")
+	require.True(t, second.Allowed)
+	require.Equal(t, 0, second.Remaining)
+
+	third := limiter.Allow("client")
+	require.False(t, third.Allowed)
+	require.Equal(t, 0, third.Remaining)
+	require.Greater(t, third.RetryAfter, 900*time.Millisecond)
+	require.LessOrEqual(t, third.RetryAfter, time.Second)
+	require.Greater(t, third.ResetAfter, third.RetryAfter)
+
+	// Bucket'ы разных ключей независимы
+	require.True(t, limiter.Allow("other").Allowed)
+}
+
+func TestTokenBucketLimiterRefill(t *testing.T) {
+	limiter := ratelimit.NewTokenBucketLimiter(20, 1, 10)
+
+	require.True(t, limiter.Allow("client").Allowed)
+	require.False(t, limiter.Allow("client").Allowed)
+
+	time.Sleep(60 * time.Millisecond)
+	require.True(t, limiter.Allow("client").Allowed)
+}
+
+func TestReloadableLimiter(t *testing.T) {
+	limiter := ratelimit.NewReloadableLimiter(10)
+
+	// Без лимита запросы пропускаются без проверки
+	decision := limiter.Allow("client")
+	require.True(t, decision.Allowed)
+	require.True(t, decision.Unlimited)
+
+	limiter.SetLimit(1, 1)
+	require.True(t, limiter.Allow("client").Allowed)
+	require.False(t, limiter.Allow("client").Allowed)
+
+	// Тот же лимит не сбрасывает bucket'ы
+	limiter.SetLimit(1, 1)
+	require.False(t, limiter.Allow("client").Allowed)
+
+	limiter.SetLimit(1, 3)
+	decision = limiter.Allow("client")
+	require.True(t, decision.Allowed)
+	require.False(t, decision.Unlimited)
+	require.Equal(t, 3, decision.Limit)
+
+	limiter.SetLimit(0, 0)
+	require.True(t, limiter.Allow("client").Unlimited)
+}
+
+func TestReloadableLimiterNoGoroutines(t *testing.T) {
+	limiter := ratelimit.NewReloadableLimiter(10)
+	before := runtime.NumGoroutine()
+
+	// Каждая перезагрузка лимита создает новый limiter; прежние не должны оставлять горутин
+	for i := range 100 {
+		limiter.SetLimit(float64(i+1), i+1)
+		limiter.Allow("client")
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// compatible проверяет, что читатель со схемой reader прочитает данные, записанные схемой writer.
+func compatible(format Format, reader, writer any) error {
+	switch format {
+	case FormatJSON:
+		return jsonCompatible(reader.(map[string]any), writer.(map[string]any), "$")
+	case FormatAvro:
+		return avro.NewSchemaCompatibility().Compatible(reader.(avro.Schema), writer.(avro.Schema))
+	case FormatProtobuf:
+		return protoCompatible(reader.(protoreflect.MessageDescriptor), writer.(protoreflect.MessageDescriptor), map[protoreflect.FullName]bool{})
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// jsonCompatible сравнивает JSON Schema по свойствам: поле, обязательное для читателя,
+// должно быть обязательным и у писателя, а тип общего поля — допускаться читателем.
+// Новые необязательные поля и удаленные поля совместимы.
+func jsonCompatible(reader, writer map[string]any, path string) error {
+	readerTypes, writerTypes := jsonTypes(reader), jsonTypes(writer)
+	if len(readerTypes) > 0 && len(writerTypes) > 0 {
+		for _, t := range writerTypes {
+			// Целые числа — частный случай number
+			if !slices.Contains(readerTypes, t) && (t != "integer" || !slices.Contains(readerTypes, "number")) {
+				return fmt.Errorf("%s: type %v does not accept %v", path, readerTypes, writerTypes)
+			}
+		}
+	}
+
+	writerRequired := jsonStrings(writer["required"])
+	for _, name := range jsonStrings(reader["required"]) {
+		if !slices.Contains(writerRequired, name) {
+			return fmt.Errorf("%s.%s: required field may be missing in written data", path, name)
+		}
+	}
+
+	readerProps, _ := reader["properties"].(map[string]any)
+	writerProps, _ := writer["properties"].(map[string]any)
+	for name, readerProp := range readerProps {
+		readerSchema, ok := readerProp.(map[string]any)
+		if !ok {
+			continue
+		}
+		writerSchema, ok := writerProps[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := jsonCompatible(readerSchema, writerSchema, path+"."+name); err != nil {
+			return err
+		}
+	}
+
+	readerItems, readerOk := reader["items"].(map[string]any)
+	writerItems, writerOk := writer["items"].(map[string]any)
+	if readerOk && writerOk {
+		return jsonCompatible(readerItems, writerItems, path+"[]")
+	}
+	return nil
+}
+
+// jsonTypes возвращает значение "type" схемы как список.
+func jsonTypes(schema map[string]any) []string {
+	if t, ok := schema["type"].(string); ok {
+		return []string{t}
+	}
+	return jsonStrings(schema["type"])
+}
+
+// jsonStrings переводит JSON-массив строк в []string.
+func jsonStrings(value any) []string {
+	list, _ := value.([]any)
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// protoWireGroups — виды полей, значения которых взаимозаменяемы на уровне wire-формата.
+var protoWireGroups = map[protoreflect.Kind]int{
+	protoreflect.Int32Kind: 1, protoreflect.Int64Kind: 1, protoreflect.Uint32Kind: 1,
+	protoreflect.Uint64Kind: 1, protoreflect.BoolKind: 1, protoreflect.EnumKind: 1,
+	protoreflect.Sint32Kind: 2, protoreflect.Sint64Kind: 2,
+	protoreflect.Fixed32Kind: 3, protoreflect.Sfixed32Kind: 3,
+	protoreflect.Fixed64Kind: 4, protoreflect.Sfixed64Kind: 4,
+	protoreflect.StringKind: 5, protoreflect.BytesKind: 5,
+	protoreflect.FloatKind: 6, protoreflect.DoubleKind: 7,
+	protoreflect.MessageKind: 8, protoreflect.GroupKind: 9,
+}
+
+// protoCompatible сравнивает поля сообщений по номерам: у поля с тем же номером должны
+// совпадать wire-формат и повторяемость. Переименование, добавление и удаление полей совместимы.
+func protoCompatible(reader, writer protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) error {
+	if seen[reader.FullName()] {
+		return nil
+	}
+	seen[reader.FullName()] = true
+
+	readerFields, writerFields := reader.Fields(), writer.Fields()
+	for i := 0; i < readerFields.Len(); i++ {
+		readerField := readerFields.Get(i)
+		writerField := writerFields.ByNumber(readerField.Number())
+		if writerField == nil {
+			continue
+		}
+		if protoWireGroups[readerField.Kind()] != protoWireGroups[writerField.Kind()] {
+			return fmt.Errorf("%s: field %d changes type from %s to %s",
+				reader.FullName(), readerField.Number(), writerField.Kind(), readerField.Kind())
+		}
+		if readerField.IsList() != writerField.IsList() || readerField.IsMap() != writerField.IsMap() {
+			return fmt.Errorf("%s: field %d changes cardinality", reader.FullName(), readerField.Number())
+		}
+		if readerField.Message() != nil && writerField.Message() != nil {
+			if err := protoCompatible(readerField.Message(), writerField.Message(), seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	orderv1 "order_service/internal/gen/order/v1"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// decoder декодирует тело сообщения в заказ.
+type decoder func(data []byte) (*domain.Order, error)
+
+// protoDecoders — сообщения Protobuf, которые сервис умеет переводить в domain.Order.
+var protoDecoders = map[protoreflect.FullName]decoder{
+	"order.v1.Order": func(data []byte) (*domain.Order, error) {
+		msg := &orderv1.Order{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return nil, err
+		}
+		return protoconv.OrderFromProto(msg), nil
+	},
+}
+
+// decodeJSON декодирует заказ из JSON.
+func decodeJSON(data []byte) (*domain.Order, error) {
+	order := domain.Order{}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// loadJSONSchema читает JSON Schema. Схема нужна только для проверки совместимости:
+// тело сообщения декодируется в domain.Order напрямую.
+func loadJSONSchema(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json schema: %w", err)
+	}
+	definition := map[string]any{}
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("failed to parse json schema: %w", err)
+	}
+	return definition, nil
+}
+
+// loadAvroSchema читает схему Avro. Сообщение читается схемой писателя в обобщенное
+// значение и переводится в заказ через JSON: имена полей схемы совпадают с JSON-тегами
+// domain.Order, а поля, которых нет в заказе, отбрасываются.
+func loadAvroSchema(path string) (avro.Schema, decoder, error) {
+	schema, err := avro.ParseFiles(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse avro schema: %w", err)
+	}
+
+	decode := func(data []byte) (*domain.Order, error) {
+		var generic any
+		if err := avro.Unmarshal(schema, data, &generic); err != nil {
+			return nil, err
+		}
+		bridged, err := json.Marshal(generic)
+		if err != nil {
+			return nil, err
+		}
+		return decodeJSON(bridged)
+	}
+	return schema, decode, nil
+}
+
+// loadProtoSchema находит дескриптор сообщения, собранного в сервис, и его декодер.
+func loadProtoSchema(message string) (protoreflect.MessageDescriptor, decoder, error) {
+	name := protoreflect.FullName(message)
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protobuf message %q: %w", message, err)
+	}
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is not a protobuf message", message)
+	}
+	decode, ok := protoDecoders[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("protobuf message %q cannot be converted to an order", message)
+	}
+	return msgDesc, decode, nil
+}
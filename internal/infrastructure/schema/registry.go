@@ -0,0 +1,298 @@
+// Package schema — файловая замена schema registry: схемы сообщений с заказами по идентификатору,
+// декодеры JSON, Avro и Protobuf и проверка совместимости версий одного subject.
+package schema
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	"order_service/config"
+	"order_service/internal/domain"
+)
+
+// Заголовки сообщения с типом содержимого и идентификатором схемы.
+const (
+	HeaderContentType   = "content-type"
+	HeaderSchemaVersion = "schema-version"
+)
+
+// Типы содержимого сообщений.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeAvro     = "application/avro"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Format — формат схемы.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatAvro     Format = "avro"
+	FormatProtobuf Format = "protobuf"
+)
+
+// contentTypes — типы содержимого, допустимые для формата.
+var contentTypes = map[Format][]string{
+	FormatJSON:     {ContentTypeJSON},
+	FormatAvro:     {ContentTypeAvro, "avro/binary"},
+	FormatProtobuf: {ContentTypeProtobuf, "application/protobuf"},
+}
+
+// Compatibility — правило совместимости соседних версий subject, как в Confluent Schema Registry.
+type Compatibility string
+
+const (
+	// CompatibilityNone — версии не проверяются.
+	CompatibilityNone Compatibility = "NONE"
+	// CompatibilityBackward — новая версия читает данные, записанные предыдущей.
+	CompatibilityBackward Compatibility = "BACKWARD"
+	// CompatibilityForward — предыдущая версия читает данные, записанные новой.
+	CompatibilityForward Compatibility = "FORWARD"
+	// CompatibilityFull — и BACKWARD, и FORWARD.
+	CompatibilityFull Compatibility = "FULL"
+)
+
+// Entry — схема в файле реестра. Файл схемы указывается относительно файла реестра,
+// для Protobuf вместо файла задается полное имя сообщения, собранного в сервис.
+type Entry struct {
+	ID      int    `json:"id"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+	Format  Format `json:"format"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// registryFile — содержимое файла реестра.
+type registryFile struct {
+	Compatibility Compatibility `json:"compatibility"`
+	Schemas       []Entry       `json:"schemas"`
+}
+
+// schema — загруженная схема: разобранное описание и декодер сообщений.
+type schema struct {
+	Entry
+	definition any
+	decode     decoder
+}
+
+// Registry хранит схемы по идентификатору и декодирует сообщения с заказами.
+type Registry struct {
+	schemas map[int]*schema
+	// unversioned — схема сообщений без заголовков: JSON, обязательные поля берутся
+	// из JSON Schema с наибольшим id
+	unversioned *schema
+	strict      atomic.Pointer[config.StrictDecoding]
+	log         *slog.Logger
+}
+
+// LoadRegistry читает реестр из файла schema.registry_file, разбирает схемы и проверяет,
+// что соседние версии каждого subject совместимы по правилу compatibility.
+func LoadRegistry(cfg config.Schema, log *slog.Logger) (*Registry, error) {
+	path := cfg.RegistryFile
+	log.Debug("Loading schema registry", slog.String("file", path), slog.Bool("strict", cfg.Strict.Enabled))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema registry: %w", err)
+	}
+	file := registryFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse schema registry: %w", err)
+	}
+	if file.Compatibility == "" {
+		file.Compatibility = CompatibilityBackward
+	}
+
+	registry := &Registry{
+		schemas:     make(map[int]*schema, len(file.Schemas)),
+		unversioned: &schema{Entry: Entry{Format: FormatJSON}, decode: decodeJSON},
+		log:         log,
+	}
+	registry.SetStrict(cfg.Strict)
+	subjects := map[string][]*schema{}
+	for _, entry := range file.Schemas {
+		if _, ok := registry.schemas[entry.ID]; ok {
+			return nil, fmt.Errorf("duplicate schema id %d", entry.ID)
+		}
+		s, err := loadSchema(filepath.Dir(path), entry)
+		if err != nil {
+			return nil, fmt.Errorf("schema %d: %w", entry.ID, err)
+		}
+		registry.schemas[entry.ID] = s
+		subjects[entry.Subject] = append(subjects[entry.Subject], s)
+		if s.Format == FormatJSON && entry.ID > registry.unversioned.ID {
+			registry.unversioned.ID = entry.ID
+			registry.unversioned.definition = s.definition
+		}
+	}
+
+	for subject, versions := range subjects {
+		if err := checkSubject(file.Compatibility, versions); err != nil {
+			return nil, fmt.Errorf("subject %q: %w", subject, err)
+		}
+	}
+
+	log.Info("Schema registry is loaded",
+		slog.Int("schemas", len(registry.schemas)), slog.String("compatibility", string(file.Compatibility)))
+	return registry, nil
+}
+
+// loadSchema разбирает схему записи реестра и готовит ее декодер.
+func loadSchema(dir string, entry Entry) (*schema, error) {
+	if entry.Subject == "" || entry.Version <= 0 {
+		return nil, fmt.Errorf("subject and a positive version are required")
+	}
+
+	var (
+		s   = &schema{Entry: entry}
+		err error
+	)
+	switch entry.Format {
+	case FormatJSON:
+		s.definition, err = loadJSONSchema(filepath.Join(dir, entry.File))
+		s.decode = decodeJSON
+	case FormatAvro:
+		s.definition, s.decode, err = loadAvroSchema(filepath.Join(dir, entry.File))
+	case FormatProtobuf:
+		s.definition, s.decode, err = loadProtoSchema(entry.Message)
+	default:
+		err = fmt.Errorf("unknown format %q", entry.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkSubject проверяет совместимость соседних версий subject.
+func checkSubject(compatibility Compatibility, versions []*schema) error {
+	slices.SortFunc(versions, func(a, b *schema) int { return cmp.Compare(a.Version, b.Version) })
+
+	for i := 1; i < len(versions); i++ {
+		previous, current := versions[i-1], versions[i]
+		if previous.Version == current.Version {
+			return fmt.Errorf("duplicate version %d", current.Version)
+		}
+		if previous.Format != current.Format {
+			return fmt.Errorf("version %d changes format from %s to %s", current.Version, previous.Format, current.Format)
+		}
+
+		var err error
+		switch compatibility {
+		case CompatibilityNone:
+		case CompatibilityBackward:
+			err = compatible(current.Format, current.definition, previous.definition)
+		case CompatibilityForward:
+			err = compatible(current.Format, previous.definition, current.definition)
+		case CompatibilityFull:
+			err = compatible(current.Format, current.definition, previous.definition)
+			if err == nil {
+				err = compatible(current.Format, previous.definition, current.definition)
+			}
+		default:
+			return fmt.Errorf("unknown compatibility %q", compatibility)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: version %d: %w", domain.ErrIncompatibleSchema, current.Version, err)
+		}
+	}
+	return nil
+}
+
+// DecodeOrder декодирует сообщение схемой с идентификатором schemaID. Сообщение без заголовков
+// читается как JSON без версии, как до появления реестра. В строгом режиме сообщение сверх
+// лимитов отклоняется, а JSON — при неизвестных, отсутствующих или неверно типизированных полях.
+func (r *Registry) DecodeOrder(contentType, schemaID string, data []byte) (*domain.Order, error) {
+	strict := r.strict.Load()
+	if strict.Enabled && strict.MaxPayloadSize > 0 && len(data) > strict.MaxPayloadSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", domain.ErrPayloadTooLarge, len(data), strict.MaxPayloadSize)
+	}
+
+	s, err := r.lookup(contentType, schemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *domain.Order
+	if strict.Enabled && s.Format == FormatJSON {
+		jsonSchema, _ := s.definition.(map[string]any)
+		order, err = decodeStrictJSON(data, jsonSchema)
+	} else {
+		order, err = s.decode(data)
+	}
+	if err != nil {
+		if s == r.unversioned {
+			return nil, fmt.Errorf("failed to decode unversioned json message: %w", err)
+		}
+		return nil, fmt.Errorf("failed to decode %s message with schema %d: %w", s.Format, s.ID, err)
+	}
+
+	if strict.Enabled && strict.MaxItems > 0 && len(order.Items) > strict.MaxItems {
+		return nil, domain.FieldErrors{{
+			Path: "items",
+			Err:  fmt.Errorf("%w: %d, limit is %d", domain.ErrTooManyItems, len(order.Items), strict.MaxItems),
+		}}
+	}
+	return order, nil
+}
+
+// SetStrict меняет настройки строгого декодирования. Безопасен для вызова во время
+// декодирования: сообщение, которое уже декодируется, проверяется по прежним настройкам.
+func (r *Registry) SetStrict(strict config.StrictDecoding) {
+	r.strict.Store(&strict)
+}
+
+// lookup находит схему сообщения по заголовкам.
+func (r *Registry) lookup(contentType, schemaID string) (*schema, error) {
+	mediaType := ContentTypeJSON
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedContentType, contentType)
+		}
+		mediaType = parsed
+	}
+	if !knownContentType(mediaType) {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedContentType, mediaType)
+	}
+
+	if schemaID == "" {
+		if mediaType != ContentTypeJSON {
+			return nil, fmt.Errorf("%w: %s header is required for %s", domain.ErrUnknownSchema, HeaderSchemaVersion, mediaType)
+		}
+		return r.unversioned, nil
+	}
+
+	id, err := strconv.Atoi(schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema id %q", domain.ErrUnknownSchema, schemaID)
+	}
+	s, ok := r.schemas[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: schema id %d is not registered", domain.ErrUnknownSchema, id)
+	}
+	if !slices.Contains(contentTypes[s.Format], mediaType) {
+		return nil, fmt.Errorf("%w: schema %d is %s, message is %s", domain.ErrUnsupportedContentType, id, s.Format, mediaType)
+	}
+	return s, nil
+}
+
+// knownContentType сообщает, есть ли формат с таким типом содержимого.
+func knownContentType(mediaType string) bool {
+	for _, types := range contentTypes {
+		if slices.Contains(types, mediaType) {
+			return true
+		}
+	}
+	return false
+}
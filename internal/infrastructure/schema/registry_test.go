@@ -0,0 +1,151 @@
+package schema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"order_service/config"
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/schema"
+	"order_service/internal/logger"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	registryFile = "../../../config/schemas/registry.json"
+	orderFile    = "../kafka/producer/orders/order1.json"
+)
+
+// avroValue переводит JSON заказа в значение для кодирования Avro: числа становятся int64 (long).
+func avroValue(t *testing.T, data []byte) any {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	require.NoError(t, decoder.Decode(&value))
+
+	var convert func(v any) any
+	convert = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, field := range v {
+				v[key] = convert(field)
+			}
+		case []any:
+			for i, item := range v {
+				v[i] = convert(item)
+			}
+		case json.Number:
+			n, err := v.Int64()
+			require.NoError(t, err)
+			return n
+		}
+		return v
+	}
+	return convert(value)
+}
+
+func TestDecodeOrder(t *testing.T) {
+	registry, err := schema.LoadRegistry(config.Schema{RegistryFile: registryFile}, logger.Discard())
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(orderFile)
+	require.NoError(t, err)
+	expected := domain.Order{}
+	require.NoError(t, json.Unmarshal(data, &expected))
+
+	avroSchema, err := avro.ParseFiles("../../../config/schemas/order.v1.avsc")
+	require.NoError(t, err)
+	avroData, err := avro.Marshal(avroSchema, avroValue(t, data))
+	require.NoError(t, err)
+
+	protoData, err := proto.Marshal(protoconv.OrderToProto(&expected))
+	require.NoError(t, err)
+
+	tbl := []struct {
+		name        string
+		contentType string
+		schemaID    string
+		data        []byte
+	}{
+		{name: "json_without_headers", data: data},
+		{name: "json", contentType: "application/json; charset=utf-8", schemaID: "1", data: data},
+		{name: "avro", contentType: schema.ContentTypeAvro, schemaID: "2", data: avroData},
+		{name: "protobuf", contentType: schema.ContentTypeProtobuf, schemaID: "3", data: protoData},
+	}
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			order, err := registry.DecodeOrder(testCase.contentType, testCase.schemaID, testCase.data)
+			require.NoError(t, err)
+			require.Equal(t, &expected, order)
+		})
+	}
+
+	t.Run("unknown_schema", func(t *testing.T) {
+		_, err := registry.DecodeOrder(schema.ContentTypeJSON, "42", data)
+		require.ErrorIs(t, err, domain.ErrUnknownSchema)
+		_, err = registry.DecodeOrder(schema.ContentTypeJSON, "v1", data)
+		require.ErrorIs(t, err, domain.ErrUnknownSchema)
+	})
+
+	t.Run("schema_version_required_for_binary_formats", func(t *testing.T) {
+		_, err := registry.DecodeOrder(schema.ContentTypeAvro, "", avroData)
+		require.ErrorIs(t, err, domain.ErrUnknownSchema)
+	})
+
+	t.Run("content_type_mismatch", func(t *testing.T) {
+		_, err := registry.DecodeOrder(schema.ContentTypeProtobuf, "2", avroData)
+		require.ErrorIs(t, err, domain.ErrUnsupportedContentType)
+		_, err = registry.DecodeOrder("text/plain", "", data)
+		require.ErrorIs(t, err, domain.ErrUnsupportedContentType)
+	})
+
+	t.Run("corrupted_payload", func(t *testing.T) {
+		_, err := registry.DecodeOrder(schema.ContentTypeAvro, "2", avroData[:10])
+		require.Error(t, err)
+	})
+}
+
+func TestLoadRegistryCompatibility(t *testing.T) {
+	tbl := []struct {
+		file         string
+		incompatible bool
+		invalid      bool
+	}{
+		// Новое поле Avro со значением по умолчанию совместимо в обе стороны
+		{file: "avro_evolved.json"},
+		{file: "avro_full.json"},
+		// Без значения по умолчанию новая версия не прочитает старые сообщения
+		{file: "avro_no_default.json", incompatible: true},
+		// integer -> number расширяет тип: новая версия читает старые данные, но не наоборот
+		{file: "json_evolved.json"},
+		{file: "json_widened_forward.json", incompatible: true},
+		{file: "json_required.json", incompatible: true},
+		{file: "json_retyped.json", incompatible: true},
+		{file: "json_retyped_none.json"},
+		{file: "format_changed.json", invalid: true},
+		{file: "duplicate_id.json", invalid: true},
+		// Сообщение без перевода в заказ сервис декодировать не умеет
+		{file: "protobuf_unknown.json", invalid: true},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.file, func(t *testing.T) {
+			_, err := schema.LoadRegistry(config.Schema{RegistryFile: "testdata/" + testCase.file}, logger.Discard())
+			switch {
+			case testCase.incompatible:
+				require.ErrorIs(t, err, domain.ErrIncompatibleSchema)
+			case testCase.invalid:
+				require.Error(t, err)
+				require.NotErrorIs(t, err, domain.ErrIncompatibleSchema)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
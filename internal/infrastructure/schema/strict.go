@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"order_service/internal/domain"
+)
+
+var orderType = reflect.TypeFor[domain.Order]()
+
+// decodeStrictJSON декодирует JSON заказа, отклоняя неизвестные поля, поля неверного типа
+// и отсутствующие поля, обязательные по jsonSchema. Возвращает все найденные ошибки
+// с путями полей. jsonSchema может быть nil — тогда обязательные поля не проверяются.
+func decodeStrictJSON(data []byte, jsonSchema map[string]any) (*domain.Order, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var errs domain.FieldErrors
+	checkFields(generic, orderType, "", &errs)
+	if jsonSchema != nil {
+		checkRequired(generic, jsonSchema, "", &errs)
+	}
+	if len(errs) > 0 {
+		slices.SortStableFunc(errs, func(a, b *domain.FieldError) int { return strings.Compare(a.Path, b.Path) })
+		return nil, errs
+	}
+
+	return decodeJSON(data)
+}
+
+// checkFields сверяет значение с типом поля domain.Order: находит неизвестные поля и значения
+// не того типа. null допускается везде, как и в encoding/json.
+func checkFields(value any, t reflect.Type, path string, errs *domain.FieldErrors) {
+	if value == nil {
+		return
+	}
+
+	invalid := func(expected string) {
+		*errs = append(*errs, &domain.FieldError{
+			Path: rootPath(path),
+			Err:  fmt.Errorf("%w: expected %s", domain.ErrInvalidFieldType, expected),
+		})
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			invalid("object")
+			return
+		}
+		fields := jsonFields(t)
+		for name, fieldValue := range object {
+			fieldType, ok := fields[name]
+			if !ok {
+				*errs = append(*errs, &domain.FieldError{Path: joinPath(path, name), Err: domain.ErrUnknownField})
+				continue
+			}
+			checkFields(fieldValue, fieldType, joinPath(path, name), errs)
+		}
+	case reflect.Slice:
+		list, ok := value.([]any)
+		if !ok {
+			invalid("array")
+			return
+		}
+		for i, item := range list {
+			checkFields(item, t.Elem(), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			invalid("string")
+		}
+	case reflect.Int, reflect.Int64:
+		number, ok := value.(json.Number)
+		if !ok {
+			invalid("integer")
+			return
+		}
+		if _, err := number.Int64(); err != nil {
+			invalid("integer")
+		}
+	}
+}
+
+// jsonFields возвращает поля структуры по JSON-именам. Встроенная структура без имени в теге
+// раскрывается, как это делает encoding/json.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-" || !field.IsExported():
+		case name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct:
+			for embeddedName, embeddedType := range jsonFields(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+		case name == "":
+			fields[field.Name] = field.Type
+		default:
+			fields[name] = field.Type
+		}
+	}
+	return fields
+}
+
+// checkRequired находит поля, обязательные по JSON Schema ("required"), которых нет в сообщении.
+// Отсутствующее поле отличается от пустого: опечатка в имени блока дает пустой блок, который
+// валидация заказа не замечает.
+func checkRequired(value any, jsonSchema map[string]any, path string, errs *domain.FieldErrors) {
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range jsonStrings(jsonSchema["required"]) {
+			if _, ok := value[name]; !ok {
+				*errs = append(*errs, &domain.FieldError{Path: joinPath(path, name), Err: domain.ErrMissingField})
+			}
+		}
+		properties, _ := jsonSchema["properties"].(map[string]any)
+		for name, propertySchema := range properties {
+			propertySchema, ok := propertySchema.(map[string]any)
+			if !ok {
+				continue
+			}
+			if fieldValue, ok := value[name]; ok {
+				checkRequired(fieldValue, propertySchema, joinPath(path, name), errs)
+			}
+		}
+	case []any:
+		itemSchema, ok := jsonSchema["items"].(map[string]any)
+		if !ok {
+			return
+		}
+		for i, item := range value {
+			checkRequired(item, itemSchema, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+// joinPath добавляет имя поля к пути.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// rootPath обозначает корень сообщения, у которого нет имени поля.
+func rootPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
@@ -0,0 +1,157 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"order_service/config"
+	"order_service/internal/delivery/protoconv"
+	"order_service/internal/domain"
+	"order_service/internal/infrastructure/schema"
+	"order_service/internal/logger"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var strictCfg = config.Schema{
+	RegistryFile: registryFile,
+	Strict:       config.StrictDecoding{Enabled: true, MaxPayloadSize: 4096, MaxItems: 3},
+}
+
+// mutateOrder меняет JSON заказа из orderFile и возвращает результат.
+func mutateOrder(t *testing.T, mutate func(order map[string]any)) []byte {
+	data, err := os.ReadFile(orderFile)
+	require.NoError(t, err)
+	order := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &order))
+	mutate(order)
+	data, err = json.Marshal(order)
+	require.NoError(t, err)
+	return data
+}
+
+// fieldErrors возвращает ошибки полей отклоненного сообщения по путям.
+func fieldErrors(t *testing.T, err error) map[string]error {
+	var errs domain.FieldErrors
+	require.True(t, errors.As(err, &errs), "expected field errors, got %v", err)
+	result := map[string]error{}
+	for _, fieldErr := range errs {
+		result[fieldErr.Path] = fieldErr.Err
+	}
+	return result
+}
+
+func TestStrictDecoding(t *testing.T) {
+	registry, err := schema.LoadRegistry(strictCfg, logger.Discard())
+	require.NoError(t, err)
+
+	t.Run("sample_orders_accepted", func(t *testing.T) {
+		files, err := filepath.Glob("../kafka/producer/orders/*.json")
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			_, err = registry.DecodeOrder("", "", data)
+			require.NoError(t, err, file)
+			_, err = registry.DecodeOrder(schema.ContentTypeJSON, "1", data)
+			require.NoError(t, err, file)
+		}
+	})
+
+	t.Run("misspelled_block", func(t *testing.T) {
+		data := mutateOrder(t, func(order map[string]any) {
+			order["delivry"] = order["delivery"]
+			delete(order, "delivery")
+		})
+		_, err := registry.DecodeOrder("", "", data)
+		require.ErrorIs(t, err, domain.ErrUnknownField)
+		require.ErrorIs(t, err, domain.ErrMissingField)
+
+		errs := fieldErrors(t, err)
+		require.Len(t, errs, 2)
+		require.ErrorIs(t, errs["delivry"], domain.ErrUnknownField)
+		require.ErrorIs(t, errs["delivery"], domain.ErrMissingField)
+	})
+
+	t.Run("nested_paths", func(t *testing.T) {
+		data := mutateOrder(t, func(order map[string]any) {
+			item := order["items"].([]any)[1].(map[string]any)
+			item["prise"] = item["price"]
+			delete(item, "price")
+			order["payment"].(map[string]any)["amount"] = 18.17
+			order["sm_id"] = "99"
+		})
+		_, err := registry.DecodeOrder(schema.ContentTypeJSON, "1", data)
+
+		errs := fieldErrors(t, err)
+		require.Len(t, errs, 4)
+		require.ErrorIs(t, errs["items[1].prise"], domain.ErrUnknownField)
+		require.ErrorIs(t, errs["items[1].price"], domain.ErrMissingField)
+		require.ErrorIs(t, errs["payment.amount"], domain.ErrInvalidFieldType)
+		require.ErrorIs(t, errs["sm_id"], domain.ErrInvalidFieldType)
+		require.Contains(t, err.Error(), "items[1].prise: unknown field")
+	})
+
+	t.Run("payload_too_large", func(t *testing.T) {
+		data := mutateOrder(t, func(order map[string]any) {
+			order["internal_signature"] = string(make([]byte, 5000))
+		})
+		_, err := registry.DecodeOrder("", "", data)
+		require.ErrorIs(t, err, domain.ErrPayloadTooLarge)
+	})
+
+	t.Run("too_many_items", func(t *testing.T) {
+		data := mutateOrder(t, func(order map[string]any) {
+			items := order["items"].([]any)
+			order["items"] = append(items, items[0], items[1])
+		})
+		_, err := registry.DecodeOrder("", "", data)
+		require.ErrorIs(t, err, domain.ErrTooManyItems)
+		require.ErrorIs(t, fieldErrors(t, err)["items"], domain.ErrTooManyItems)
+
+		// Лимит действует для всех форматов
+		order := domain.Order{}
+		require.NoError(t, json.Unmarshal(data, &order))
+		protoData, err := proto.Marshal(protoconv.OrderToProto(&order))
+		require.NoError(t, err)
+		_, err = registry.DecodeOrder(schema.ContentTypeProtobuf, "3", protoData)
+		require.ErrorIs(t, err, domain.ErrTooManyItems)
+	})
+
+	t.Run("lenient_when_disabled", func(t *testing.T) {
+		lenient, err := schema.LoadRegistry(config.Schema{RegistryFile: registryFile}, logger.Discard())
+		require.NoError(t, err)
+
+		data := mutateOrder(t, func(order map[string]any) {
+			order["delivry"] = order["delivery"]
+			delete(order, "delivery")
+		})
+		order, err := lenient.DecodeOrder("", "", data)
+		require.NoError(t, err)
+		require.Empty(t, order.Delivery.Name)
+	})
+
+	t.Run("set_strict", func(t *testing.T) {
+		reloaded, err := schema.LoadRegistry(config.Schema{RegistryFile: registryFile}, logger.Discard())
+		require.NoError(t, err)
+
+		data := mutateOrder(t, func(order map[string]any) {
+			order["delivry"] = order["delivery"]
+		})
+		_, err = reloaded.DecodeOrder("", "", data)
+		require.NoError(t, err)
+
+		reloaded.SetStrict(strictCfg.Strict)
+		_, err = reloaded.DecodeOrder("", "", data)
+		require.ErrorIs(t, err, domain.ErrUnknownField)
+
+		reloaded.SetStrict(config.StrictDecoding{})
+		_, err = reloaded.DecodeOrder("", "", data)
+		require.NoError(t, err)
+	})
+}
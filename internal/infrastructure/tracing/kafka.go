@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KafkaHeaderCarrier адаптирует заголовки сообщения Kafka к propagation.TextMapCarrier,
+// чтобы переносить W3C trace context (traceparent, tracestate) между сервисами.
+type KafkaHeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = KafkaHeaderCarrier{}
+
+// NewKafkaHeaderCarrier создает carrier поверх заголовков сообщения.
+func NewKafkaHeaderCarrier(msg *kafka.Message) KafkaHeaderCarrier {
+	return KafkaHeaderCarrier{Headers: &msg.Headers}
+}
+
+// Get возвращает значение заголовка по ключу.
+func (c KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set записывает заголовок, заменяя существующее значение.
+func (c KafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.Headers {
+		if h.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys возвращает ключи всех заголовков.
+func (c KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,85 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"order_service/config"
+	"order_service/internal/infrastructure/tracing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tbl := []struct {
+		exporter  string
+		insecure  bool
+		expectErr bool
+	}{
+		{exporter: tracing.ExporterNone},
+		{exporter: tracing.ExporterStdout},
+		{exporter: tracing.ExporterOTLP},
+		{exporter: tracing.ExporterOTLP, insecure: true},
+		{exporter: "jaeger", expectErr: true},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.exporter, func(t *testing.T) {
+			cfg := &config.Config{
+				Tracing: config.Tracing{
+					Exporter:    testCase.exporter,
+					Endpoint:    "localhost:4318",
+					Insecure:    testCase.insecure,
+					ServiceName: "order_service_test",
+					SampleRatio: 1,
+				},
+			}
+
+			tp, err := tracing.NewTracerProvider(context.Background(), cfg)
+			if testCase.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, tp.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestKafkaHeaderCarrier(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	propagator := propagation.TraceContext{}
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "produce")
+	span.End()
+
+	// Инжектим контекст в заголовки сообщения, как это делает producer
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	propagator.Inject(ctx, tracing.NewKafkaHeaderCarrier(&msg))
+
+	require.Len(t, msg.Headers, 1)
+	require.Contains(t, tracing.NewKafkaHeaderCarrier(&msg).Keys(), "traceparent")
+
+	// Извлекаем контекст на стороне consumer'а
+	extracted := propagator.Extract(context.Background(), tracing.NewKafkaHeaderCarrier(&msg))
+	_, child := tp.Tracer("test").Start(extracted, "consume")
+	child.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	require.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	require.True(t, trace.SpanContextFromContext(extracted).IsRemote())
+
+	// Глобальный пропагатор, настроенный NewTracerProvider, тоже понимает traceparent
+	_, err := tracing.NewTracerProvider(context.Background(), &config.Config{})
+	require.NoError(t, err)
+	require.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
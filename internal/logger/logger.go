@@ -1,67 +1,167 @@
-// Package logger предоставляет глобальные логгеры для разных уровней логирования.
-// Используется во всех частях микросервиса без подключения сторонних библиотек.
-// в .env DEBUG=true — для разработки. DEBUG=false — для продакшена.
+// Package logger создает структурированный логгер на базе log/slog.
+// Логгер внедряется в компоненты через конструкторы, а поля запроса
+// (request_id, order_uid, partition, offset) переносятся через context.Context
+// и автоматически добавляются к записям, сделанным методами *Context.
 package logger
 
 import (
-	"log"
-	"os"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
 
 	"order_service/config"
-)
 
-var (
-	// InfoLogger используется для логирования общей информации
-	// о ходе работы приложения (запуск, действия пользователя и т.п.).
-	InfoLogger *log.Logger
+	"go.opentelemetry.io/otel/trace"
+)
 
-	// ErrorLogger выводит сообщения об ошибках, которые требуют внимания.
-	ErrorLogger *log.Logger
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
 
-	// DebugLogger предназначен для отладки — можно отключить в продакшене.
-	DebugLogger *log.Logger
+// Ключи полей, переносимых через контекст.
+const (
+	KeyRequestID = "request_id"
+	KeyOrderUID  = "order_uid"
+	KeyPartition = "partition"
+	KeyOffset    = "offset"
+	KeyTraceID   = "trace_id"
 )
 
-// InitLogger инициализирует глобальные логгеры.
-// Достаточно вызвать один раз при запуске приложения (например, в main).
-func InitLogger(cfg *config.Config) {
-	debug := cfg.Serv.Debug
+type ctxKey struct{}
 
-	InfoLogger = log.New(os.Stdout, "[INFO]  ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(os.Stderr, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile)
+// New создает логгер с JSON или текстовым обработчиком.
+// Уровень логирования задается через level и может меняться во время работы.
+func New(w io.Writer, format string, level *slog.LevelVar) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format: %q", format)
+	}
+
+	return slog.New(contextHandler{Handler: handler}), nil
+}
+
+// Discard возвращает логгер, который ничего не пишет. Удобен в тестах.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
 
-	if debug {
-		DebugLogger = log.New(os.Stdout, "[DEBUG] ", log.Ldate|log.Ltime|log.Lshortfile)
-	} else {
-		// Открываем /dev/null как io.Writer
-		nullFile, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
-		if err != nil {
-			// На всякий случай, чтобы не упасть, отправим в stderr
-			DebugLogger = log.New(os.Stderr, "[DEBUG-OFF-FAILSAFE] ", log.Ldate|log.Ltime|log.Lshortfile)
+// ParseLevel разбирает уровень логирования: debug, info, warn, error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	return level, nil
+}
 
-			return
+// WithAttrs возвращает контекст, к полям которого добавлены attrs.
+// Поле с уже существующим ключом заменяется новым значением.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, attr := range existing {
+		if !containsKey(attrs, attr.Key) {
+			merged = append(merged, attr)
 		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, ctxKey{}, merged)
+}
 
-		DebugLogger = log.New(nullFile, "", 0)
+// WithRequestID добавляет в контекст идентификатор HTTP-запроса.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithAttrs(ctx, slog.String(KeyRequestID, requestID))
+}
+
+// WithOrderUID добавляет в контекст order_uid обрабатываемого заказа.
+func WithOrderUID(ctx context.Context, orderUID string) context.Context {
+	return WithAttrs(ctx, slog.String(KeyOrderUID, orderUID))
+}
+
+// WithMessage добавляет в контекст координаты сообщения Kafka.
+func WithMessage(ctx context.Context, partition int, offset int64) context.Context {
+	return WithAttrs(ctx, slog.Int(KeyPartition, partition), slog.Int64(KeyOffset, offset))
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку.
+func RequestID(ctx context.Context) string {
+	attrs, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == KeyRequestID {
+			return attrs[i].Value.String()
+		}
 	}
+	return ""
 }
 
-// Info логирует информационное сообщение.
-func Info(message string) {
-	InfoLogger.Println(message)
+func containsKey(attrs []slog.Attr, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
 }
 
-// Warn логирует предупреждение.
-func Warn(message string) {
-	InfoLogger.Println("[WARNING] " + message)
+// contextHandler дополняет записи полями из контекста и trace_id текущего span'а.
+type contextHandler struct {
+	slog.Handler
 }
 
-// Error логирует сообщение об ошибке.
-func Error(message string) {
-	ErrorLogger.Println(message)
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(ctxKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String(KeyTraceID, sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
 }
 
-// Debug logs a debug-level message using DebugLogger.
-func Debug(message string) {
-	DebugLogger.Println(message)
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// NewFromConfig создает логгер по секции log конфигурации. Уровень задается LevelFromConfig.
+func NewFromConfig(w io.Writer, cfg *config.Config) (*slog.Logger, *slog.LevelVar, error) {
+	parsed, err := LevelFromConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	level := new(slog.LevelVar)
+	level.Set(parsed)
+
+	log, err := New(w, cfg.Log.Format, level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return log, level, nil
+}
+
+// LevelFromConfig возвращает уровень логирования из log.level.
+// Если уровень не задан, используется debug в режиме отладки и info иначе.
+func LevelFromConfig(cfg *config.Config) (slog.Level, error) {
+	switch {
+	case cfg.Log.Level != "":
+		return ParseLevel(cfg.Log.Level)
+	case cfg.Serv.Debug:
+		return slog.LevelDebug, nil
+	default:
+		return slog.LevelInfo, nil
+	}
 }
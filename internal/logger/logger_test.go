@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"order_service/config"
+	"order_service/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+
+	log, err := logger.New(&buf, logger.FormatJSON, level)
+	require.NoError(t, err)
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	ctx = logger.WithMessage(ctx, 2, 42)
+	ctx = logger.WithOrderUID(ctx, "first")
+	ctx = logger.WithOrderUID(ctx, "second")
+
+	log.InfoContext(ctx, "hello")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "hello", record["msg"])
+	require.Equal(t, "req-1", record[logger.KeyRequestID])
+	require.Equal(t, "second", record[logger.KeyOrderUID])
+	require.EqualValues(t, 2, record[logger.KeyPartition])
+	require.EqualValues(t, 42, record[logger.KeyOffset])
+	require.Equal(t, "req-1", logger.RequestID(ctx))
+}
+
+func TestRuntimeLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+
+	log, err := logger.New(&buf, logger.FormatText, level)
+	require.NoError(t, err)
+
+	log.Info("skipped")
+	require.Empty(t, buf.String())
+
+	level.Set(slog.LevelDebug)
+	log.Debug("written")
+	require.Contains(t, buf.String(), "msg=written")
+}
+
+func TestNewFromConfig(t *testing.T) {
+	tbl := []struct {
+		name          string
+		cfg           *config.Config
+		expectedLevel slog.Level
+		expectErr     bool
+	}{
+		{
+			name:          "explicit_level",
+			cfg:           &config.Config{Log: config.Log{Level: "warn", Format: "json"}},
+			expectedLevel: slog.LevelWarn,
+		},
+		{
+			name:          "debug_mode_default",
+			cfg:           &config.Config{Serv: config.Server{Debug: true}},
+			expectedLevel: slog.LevelDebug,
+		},
+		{
+			name:          "production_default",
+			cfg:           &config.Config{},
+			expectedLevel: slog.LevelInfo,
+		},
+		{
+			name:      "invalid_level",
+			cfg:       &config.Config{Log: config.Log{Level: "verbose"}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid_format",
+			cfg:       &config.Config{Log: config.Log{Format: "xml"}},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range tbl {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, level, err := logger.NewFromConfig(&bytes.Buffer{}, testCase.cfg)
+			if testCase.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedLevel, level.Level())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/ledger.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/ledger.go -destination=internal/mock/ledger.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "order_service/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMessageLedger is a mock of MessageLedger interface.
+type MockMessageLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageLedgerMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageLedgerMockRecorder is the mock recorder for MockMessageLedger.
+type MockMessageLedgerMockRecorder struct {
+	mock *MockMessageLedger
+}
+
+// NewMockMessageLedger creates a new mock instance.
+func NewMockMessageLedger(ctrl *gomock.Controller) *MockMessageLedger {
+	mock := &MockMessageLedger{ctrl: ctrl}
+	mock.recorder = &MockMessageLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageLedger) EXPECT() *MockMessageLedgerMockRecorder {
+	return m.recorder
+}
+
+// CheckMessage mocks base method.
+func (m *MockMessageLedger) CheckMessage(ctx context.Context, msg domain.ProcessedMessage) (domain.MessageOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckMessage", ctx, msg)
+	ret0, _ := ret[0].(domain.MessageOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckMessage indicates an expected call of CheckMessage.
+func (mr *MockMessageLedgerMockRecorder) CheckMessage(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMessage", reflect.TypeOf((*MockMessageLedger)(nil).CheckMessage), ctx, msg)
+}
+
+// CleanupMessages mocks base method.
+func (m *MockMessageLedger) CleanupMessages(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanupMessages", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CleanupMessages indicates an expected call of CleanupMessages.
+func (mr *MockMessageLedgerMockRecorder) CleanupMessages(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupMessages", reflect.TypeOf((*MockMessageLedger)(nil).CleanupMessages), ctx, before)
+}
+
+// RecordMessage mocks base method.
+func (m *MockMessageLedger) RecordMessage(ctx context.Context, msg domain.ProcessedMessage, outcome domain.MessageOutcome) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMessage", ctx, msg, outcome)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMessage indicates an expected call of RecordMessage.
+func (mr *MockMessageLedgerMockRecorder) RecordMessage(ctx, msg, outcome any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMessage", reflect.TypeOf((*MockMessageLedger)(nil).RecordMessage), ctx, msg, outcome)
+}
+
+// MockDeadLetterQueue is a mock of DeadLetterQueue interface.
+type MockDeadLetterQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterQueueMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterQueueMockRecorder is the mock recorder for MockDeadLetterQueue.
+type MockDeadLetterQueueMockRecorder struct {
+	mock *MockDeadLetterQueue
+}
+
+// NewMockDeadLetterQueue creates a new mock instance.
+func NewMockDeadLetterQueue(ctrl *gomock.Controller) *MockDeadLetterQueue {
+	mock := &MockDeadLetterQueue{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterQueue) EXPECT() *MockDeadLetterQueueMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockDeadLetterQueue) Send(ctx context.Context, letter domain.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, letter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockDeadLetterQueueMockRecorder) Send(ctx, letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockDeadLetterQueue)(nil).Send), ctx, letter)
+}
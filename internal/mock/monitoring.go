@@ -1,15 +1,16 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: /home/alexey/programming/projects/order_service/internal/domain/monitoring.go
+// Source: internal/domain/monitoring.go
 //
 // Generated by this command:
 //
-//	mockgen -source=/home/alexey/programming/projects/order_service/internal/domain/monitoring.go -destination=monitoring.go -package=mock
+//	mockgen -source=internal/domain/monitoring.go -destination=internal/mock/monitoring.go -package=mock
 //
 
 // Package mock is a generated GoMock package.
 package mock
 
 import (
+	domain "order_service/internal/domain"
 	reflect "reflect"
 	time "time"
 
@@ -63,3 +64,375 @@ func (mr *MockHTTPMetricsMockRecorder) ObserveRequest(start any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveRequest", reflect.TypeOf((*MockHTTPMetrics)(nil).ObserveRequest), start)
 }
+
+// MockGRPCMetrics is a mock of GRPCMetrics interface.
+type MockGRPCMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockGRPCMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockGRPCMetricsMockRecorder is the mock recorder for MockGRPCMetrics.
+type MockGRPCMetricsMockRecorder struct {
+	mock *MockGRPCMetrics
+}
+
+// NewMockGRPCMetrics creates a new mock instance.
+func NewMockGRPCMetrics(ctrl *gomock.Controller) *MockGRPCMetrics {
+	mock := &MockGRPCMetrics{ctrl: ctrl}
+	mock.recorder = &MockGRPCMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGRPCMetrics) EXPECT() *MockGRPCMetricsMockRecorder {
+	return m.recorder
+}
+
+// ObserveRPC mocks base method.
+func (m *MockGRPCMetrics) ObserveRPC(method, code string, start time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveRPC", method, code, start)
+}
+
+// ObserveRPC indicates an expected call of ObserveRPC.
+func (mr *MockGRPCMetricsMockRecorder) ObserveRPC(method, code, start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveRPC", reflect.TypeOf((*MockGRPCMetrics)(nil).ObserveRPC), method, code, start)
+}
+
+// MockConsumerMetrics is a mock of ConsumerMetrics interface.
+type MockConsumerMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockConsumerMetricsMockRecorder is the mock recorder for MockConsumerMetrics.
+type MockConsumerMetricsMockRecorder struct {
+	mock *MockConsumerMetrics
+}
+
+// NewMockConsumerMetrics creates a new mock instance.
+func NewMockConsumerMetrics(ctrl *gomock.Controller) *MockConsumerMetrics {
+	mock := &MockConsumerMetrics{ctrl: ctrl}
+	mock.recorder = &MockConsumerMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerMetrics) EXPECT() *MockConsumerMetricsMockRecorder {
+	return m.recorder
+}
+
+// IncConflict mocks base method.
+func (m *MockConsumerMetrics) IncConflict() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncConflict")
+}
+
+// IncConflict indicates an expected call of IncConflict.
+func (mr *MockConsumerMetricsMockRecorder) IncConflict() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncConflict", reflect.TypeOf((*MockConsumerMetrics)(nil).IncConflict))
+}
+
+// IncConsumed mocks base method.
+func (m *MockConsumerMetrics) IncConsumed() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncConsumed")
+}
+
+// IncConsumed indicates an expected call of IncConsumed.
+func (mr *MockConsumerMetricsMockRecorder) IncConsumed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncConsumed", reflect.TypeOf((*MockConsumerMetrics)(nil).IncConsumed))
+}
+
+// IncDecodeFailure mocks base method.
+func (m *MockConsumerMetrics) IncDecodeFailure() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncDecodeFailure")
+}
+
+// IncDecodeFailure indicates an expected call of IncDecodeFailure.
+func (mr *MockConsumerMetricsMockRecorder) IncDecodeFailure() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncDecodeFailure", reflect.TypeOf((*MockConsumerMetrics)(nil).IncDecodeFailure))
+}
+
+// IncDuplicate mocks base method.
+func (m *MockConsumerMetrics) IncDuplicate() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncDuplicate")
+}
+
+// IncDuplicate indicates an expected call of IncDuplicate.
+func (mr *MockConsumerMetricsMockRecorder) IncDuplicate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncDuplicate", reflect.TypeOf((*MockConsumerMetrics)(nil).IncDuplicate))
+}
+
+// IncOrder mocks base method.
+func (m *MockConsumerMetrics) IncOrder(order *domain.Order) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncOrder", order)
+}
+
+// IncOrder indicates an expected call of IncOrder.
+func (mr *MockConsumerMetricsMockRecorder) IncOrder(order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncOrder", reflect.TypeOf((*MockConsumerMetrics)(nil).IncOrder), order)
+}
+
+// IncValidationFailure mocks base method.
+func (m *MockConsumerMetrics) IncValidationFailure(rule string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncValidationFailure", rule)
+}
+
+// IncValidationFailure indicates an expected call of IncValidationFailure.
+func (mr *MockConsumerMetricsMockRecorder) IncValidationFailure(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncValidationFailure", reflect.TypeOf((*MockConsumerMetrics)(nil).IncValidationFailure), rule)
+}
+
+// ObserveCommit mocks base method.
+func (m *MockConsumerMetrics) ObserveCommit(start time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveCommit", start)
+}
+
+// ObserveCommit indicates an expected call of ObserveCommit.
+func (mr *MockConsumerMetricsMockRecorder) ObserveCommit(start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveCommit", reflect.TypeOf((*MockConsumerMetrics)(nil).ObserveCommit), start)
+}
+
+// ObserveSave mocks base method.
+func (m *MockConsumerMetrics) ObserveSave(start time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveSave", start)
+}
+
+// ObserveSave indicates an expected call of ObserveSave.
+func (mr *MockConsumerMetricsMockRecorder) ObserveSave(start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveSave", reflect.TypeOf((*MockConsumerMetrics)(nil).ObserveSave), start)
+}
+
+// SetLag mocks base method.
+func (m *MockConsumerMetrics) SetLag(partition int, lag int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLag", partition, lag)
+}
+
+// SetLag indicates an expected call of SetLag.
+func (mr *MockConsumerMetricsMockRecorder) SetLag(partition, lag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLag", reflect.TypeOf((*MockConsumerMetrics)(nil).SetLag), partition, lag)
+}
+
+// SetReaderLag mocks base method.
+func (m *MockConsumerMetrics) SetReaderLag(lag int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetReaderLag", lag)
+}
+
+// SetReaderLag indicates an expected call of SetReaderLag.
+func (mr *MockConsumerMetricsMockRecorder) SetReaderLag(lag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaderLag", reflect.TypeOf((*MockConsumerMetrics)(nil).SetReaderLag), lag)
+}
+
+// MockRepositoryMetrics is a mock of RepositoryMetrics interface.
+type MockRepositoryMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMetricsMockRecorder is the mock recorder for MockRepositoryMetrics.
+type MockRepositoryMetricsMockRecorder struct {
+	mock *MockRepositoryMetrics
+}
+
+// NewMockRepositoryMetrics creates a new mock instance.
+func NewMockRepositoryMetrics(ctrl *gomock.Controller) *MockRepositoryMetrics {
+	mock := &MockRepositoryMetrics{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryMetrics) EXPECT() *MockRepositoryMetricsMockRecorder {
+	return m.recorder
+}
+
+// ObserveQuery mocks base method.
+func (m *MockRepositoryMetrics) ObserveQuery(query string, start time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveQuery", query, start)
+}
+
+// ObserveQuery indicates an expected call of ObserveQuery.
+func (mr *MockRepositoryMetricsMockRecorder) ObserveQuery(query, start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveQuery", reflect.TypeOf((*MockRepositoryMetrics)(nil).ObserveQuery), query, start)
+}
+
+// MockCacheMetrics is a mock of CacheMetrics interface.
+type MockCacheMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheMetricsMockRecorder is the mock recorder for MockCacheMetrics.
+type MockCacheMetricsMockRecorder struct {
+	mock *MockCacheMetrics
+}
+
+// NewMockCacheMetrics creates a new mock instance.
+func NewMockCacheMetrics(ctrl *gomock.Controller) *MockCacheMetrics {
+	mock := &MockCacheMetrics{ctrl: ctrl}
+	mock.recorder = &MockCacheMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheMetrics) EXPECT() *MockCacheMetricsMockRecorder {
+	return m.recorder
+}
+
+// IncEviction mocks base method.
+func (m *MockCacheMetrics) IncEviction() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncEviction")
+}
+
+// IncEviction indicates an expected call of IncEviction.
+func (mr *MockCacheMetricsMockRecorder) IncEviction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncEviction", reflect.TypeOf((*MockCacheMetrics)(nil).IncEviction))
+}
+
+// IncExpiration mocks base method.
+func (m *MockCacheMetrics) IncExpiration() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncExpiration")
+}
+
+// IncExpiration indicates an expected call of IncExpiration.
+func (mr *MockCacheMetricsMockRecorder) IncExpiration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncExpiration", reflect.TypeOf((*MockCacheMetrics)(nil).IncExpiration))
+}
+
+// IncHit mocks base method.
+func (m *MockCacheMetrics) IncHit() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncHit")
+}
+
+// IncHit indicates an expected call of IncHit.
+func (mr *MockCacheMetricsMockRecorder) IncHit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncHit", reflect.TypeOf((*MockCacheMetrics)(nil).IncHit))
+}
+
+// IncMiss mocks base method.
+func (m *MockCacheMetrics) IncMiss() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncMiss")
+}
+
+// IncMiss indicates an expected call of IncMiss.
+func (mr *MockCacheMetricsMockRecorder) IncMiss() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncMiss", reflect.TypeOf((*MockCacheMetrics)(nil).IncMiss))
+}
+
+// SetSize mocks base method.
+func (m *MockCacheMetrics) SetSize(size int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSize", size)
+}
+
+// SetSize indicates an expected call of SetSize.
+func (mr *MockCacheMetricsMockRecorder) SetSize(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSize", reflect.TypeOf((*MockCacheMetrics)(nil).SetSize), size)
+}
+
+// MockOutboxMetrics is a mock of OutboxMetrics interface.
+type MockOutboxMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxMetricsMockRecorder is the mock recorder for MockOutboxMetrics.
+type MockOutboxMetricsMockRecorder struct {
+	mock *MockOutboxMetrics
+}
+
+// NewMockOutboxMetrics creates a new mock instance.
+func NewMockOutboxMetrics(ctrl *gomock.Controller) *MockOutboxMetrics {
+	mock := &MockOutboxMetrics{ctrl: ctrl}
+	mock.recorder = &MockOutboxMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxMetrics) EXPECT() *MockOutboxMetricsMockRecorder {
+	return m.recorder
+}
+
+// AddCleaned mocks base method.
+func (m *MockOutboxMetrics) AddCleaned(n int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddCleaned", n)
+}
+
+// AddCleaned indicates an expected call of AddCleaned.
+func (mr *MockOutboxMetricsMockRecorder) AddCleaned(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCleaned", reflect.TypeOf((*MockOutboxMetrics)(nil).AddCleaned), n)
+}
+
+// AddDead mocks base method.
+func (m *MockOutboxMetrics) AddDead(n int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddDead", n)
+}
+
+// AddDead indicates an expected call of AddDead.
+func (mr *MockOutboxMetricsMockRecorder) AddDead(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDead", reflect.TypeOf((*MockOutboxMetrics)(nil).AddDead), n)
+}
+
+// AddPublished mocks base method.
+func (m *MockOutboxMetrics) AddPublished(n int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddPublished", n)
+}
+
+// AddPublished indicates an expected call of AddPublished.
+func (mr *MockOutboxMetricsMockRecorder) AddPublished(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPublished", reflect.TypeOf((*MockOutboxMetrics)(nil).AddPublished), n)
+}
+
+// IncPublishFailure mocks base method.
+func (m *MockOutboxMetrics) IncPublishFailure() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncPublishFailure")
+}
+
+// IncPublishFailure indicates an expected call of IncPublishFailure.
+func (mr *MockOutboxMetricsMockRecorder) IncPublishFailure() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncPublishFailure", reflect.TypeOf((*MockOutboxMetrics)(nil).IncPublishFailure))
+}
@@ -41,6 +41,21 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindOrdersByEmail mocks base method.
+func (m *MockOrderRepository) FindOrdersByEmail(ctx context.Context, email string) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrdersByEmail", ctx, email)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrdersByEmail indicates an expected call of FindOrdersByEmail.
+func (mr *MockOrderRepositoryMockRecorder) FindOrdersByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrdersByEmail", reflect.TypeOf((*MockOrderRepository)(nil).FindOrdersByEmail), ctx, email)
+}
+
 // GetOrder mocks base method.
 func (m *MockOrderRepository) GetOrder(ctx context.Context, orderUID string) (*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -72,11 +87,12 @@ func (mr *MockOrderRepositoryMockRecorder) GetOrders(ctx, quantity any) *gomock.
 }
 
 // SaveOrder mocks base method.
-func (m *MockOrderRepository) SaveOrder(ctx context.Context, order *domain.Order) error {
+func (m *MockOrderRepository) SaveOrder(ctx context.Context, order *domain.Order) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SaveOrder", ctx, order)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SaveOrder indicates an expected call of SaveOrder.
@@ -41,6 +41,21 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// FindOrdersByEmail mocks base method.
+func (m *MockOrderService) FindOrdersByEmail(ctx context.Context, email string) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrdersByEmail", ctx, email)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrdersByEmail indicates an expected call of FindOrdersByEmail.
+func (mr *MockOrderServiceMockRecorder) FindOrdersByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrdersByEmail", reflect.TypeOf((*MockOrderService)(nil).FindOrdersByEmail), ctx, email)
+}
+
 // GetOrder mocks base method.
 func (m *MockOrderService) GetOrder(ctx context.Context, orderUID string) (*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -56,12 +71,28 @@ func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, orderUID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, orderUID)
 }
 
+// ListOrders mocks base method.
+func (m *MockOrderService) ListOrders(ctx context.Context, limit int) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, limit)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderServiceMockRecorder) ListOrders(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderService)(nil).ListOrders), ctx, limit)
+}
+
 // SaveOrder mocks base method.
-func (m *MockOrderService) SaveOrder(ctx context.Context, order *domain.Order) error {
+func (m *MockOrderService) SaveOrder(ctx context.Context, order *domain.Order) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SaveOrder", ctx, order)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SaveOrder indicates an expected call of SaveOrder.
@@ -69,3 +100,18 @@ func (mr *MockOrderServiceMockRecorder) SaveOrder(ctx, order any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrder", reflect.TypeOf((*MockOrderService)(nil).SaveOrder), ctx, order)
 }
+
+// WatchOrderEvents mocks base method.
+func (m *MockOrderService) WatchOrderEvents(ctx context.Context, afterID uint64) (<-chan domain.OrderEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchOrderEvents", ctx, afterID)
+	ret0, _ := ret[0].(<-chan domain.OrderEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchOrderEvents indicates an expected call of WatchOrderEvents.
+func (mr *MockOrderServiceMockRecorder) WatchOrderEvents(ctx, afterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchOrderEvents", reflect.TypeOf((*MockOrderService)(nil).WatchOrderEvents), ctx, afterID)
+}
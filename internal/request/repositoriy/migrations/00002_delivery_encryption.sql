@@ -0,0 +1,13 @@
+-- +goose Up
+-- Персональные данные delivery (name, phone, address, email) шифруются приложением,
+-- поэтому поиск по email идет через blind index. Существующие строки шифруются
+-- командой backfill (make encrypt-backfill) после применения миграции.
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS email_bidx VARCHAR;
+
+CREATE INDEX IF NOT EXISTS delivery_email_bidx_idx ON delivery (email_bidx);
+
+-- +goose Down
+-- Зашифрованные значения остаются зашифрованными: откат удаляет только индекс.
+DROP INDEX IF EXISTS delivery_email_bidx_idx;
+
+ALTER TABLE delivery DROP COLUMN IF EXISTS email_bidx;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- События для других сервисов пишутся в одной транзакции с заказом и публикуются
+-- в Kafka отдельным relay. Опубликованные строки удаляются по истечении outbox.retention.
+-- Relay забирает события по одному, не держа транзакцию на время публикации: claimed_until
+-- скрывает выданное событие от других relay и задерживает повтор после неудачи. Событие,
+-- исчерпавшее outbox.max_attempts, получает failed_at и больше не публикуется.
+CREATE TABLE
+    IF NOT EXISTS outbox (
+        id BIGSERIAL PRIMARY KEY,
+        event_type VARCHAR NOT NULL,
+        event_key VARCHAR NOT NULL,
+        payload JSONB NOT NULL,
+        headers JSONB NOT NULL DEFAULT '{}',
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+        attempts INTEGER NOT NULL DEFAULT 0,
+        last_error VARCHAR,
+        claimed_until TIMESTAMPTZ,
+        failed_at TIMESTAMPTZ,
+        published_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE published_at IS NULL AND failed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_pending_key_idx ON outbox (event_key, id) WHERE published_at IS NULL AND failed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_failed_idx ON outbox (id) WHERE failed_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_published_at_idx ON outbox (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Журнал обработанных сообщений Kafka. Позиция (topic, partition, offset) отсекает повторную
+-- доставку того же сообщения, а хеш сохраненного заказа — повторную отправку того же заказа.
+-- Для каждого order_uid сохраненной может быть только одна версия: заказ с тем же order_uid
+-- и другим хешем считается конфликтом.
+CREATE TABLE
+    IF NOT EXISTS processed_messages (
+        topic VARCHAR NOT NULL,
+        partition INTEGER NOT NULL,
+        "offset" BIGINT NOT NULL,
+        order_uid VARCHAR NOT NULL,
+        content_hash CHAR(64) NOT NULL,
+        outcome VARCHAR NOT NULL,
+        processed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+        PRIMARY KEY (topic, partition, "offset")
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS processed_messages_saved_order_idx ON processed_messages (order_uid) WHERE outcome = 'saved';
+
+-- Записи старше kafka.ledger_retention удаляются
+CREATE INDEX IF NOT EXISTS processed_messages_processed_at_idx ON processed_messages (processed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_messages;
@@ -0,0 +1,91 @@
+// Package migrations содержит миграции схемы БД и применяет их через goose при старте сервиса.
+// Файлы миграций встроены в бинарник, поэтому отдельная установка goose не нужна.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Режимы применения миграций при старте (postgres.migrations)
+const (
+	// ModeAuto применяет недостающие миграции.
+	ModeAuto = "auto"
+	// ModeVerify только сверяет версию схемы и не дает запуститься при расхождении.
+	ModeVerify = "verify"
+	// ModeOff не трогает схему.
+	ModeOff = "off"
+)
+
+// ErrVersionMismatch — версия схемы БД не совпадает с миграциями сервиса.
+var ErrVersionMismatch = errors.New("database schema version mismatch")
+
+// FS — встроенные файлы миграций.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// NewProvider создает goose-провайдер встроенных миграций для db. Одновременный запуск
+// нескольких экземпляров сервиса сериализуется advisory lock'ом в Postgres.
+func NewProvider(db *sql.DB) (*goose.Provider, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// Run приводит схему db к встроенным миграциям в режиме mode.
+func Run(ctx context.Context, db *sql.DB, mode string, log *slog.Logger) error {
+	switch mode {
+	case ModeOff, "":
+		log.Info("Database migrations are disabled")
+		return nil
+	case ModeAuto, ModeVerify:
+	default:
+		return fmt.Errorf("unknown migrations mode: %q", mode)
+	}
+
+	provider, err := NewProvider(db)
+	if err != nil {
+		return err
+	}
+
+	if mode == ModeAuto {
+		results, err := provider.Up(ctx)
+		for _, result := range results {
+			log.Info("Migration applied", slog.Int64("version", result.Source.Version),
+				slog.String("file", result.Source.Path), slog.Duration("duration", result.Duration))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	current, latest, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+	pending, err := provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check pending migrations: %w", err)
+	}
+	// Версия выше последней миграции значит, что БД мигрировал более новый сервис
+	if current != latest || pending {
+		return fmt.Errorf("%w: database version %d, service migrations up to %d", ErrVersionMismatch, current, latest)
+	}
+
+	log.Info("Database schema is up to date", slog.String("mode", mode), slog.Int64("version", current))
+	return nil
+}
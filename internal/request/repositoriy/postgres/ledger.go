@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"order_service/internal/domain"
+)
+
+// Имена запросов журнала обработанных сообщений для метрик
+const (
+	queryCheckMessage    = "check_message"
+	queryRecordMessage   = "record_message"
+	queryCleanupMessages = "cleanup_messages"
+)
+
+const (
+	checkProcessedMessage = `
+	SELECT
+		EXISTS (
+			SELECT 1 FROM processed_messages
+			WHERE topic = $1 AND partition = $2 AND "offset" = $3
+		) AS seen,
+		(
+			SELECT content_hash FROM processed_messages
+			WHERE order_uid = $4 AND outcome = 'saved'
+		) AS saved_hash
+	`
+
+	// Конфликт по позиции означает повторную доставку и игнорируется. Второй сохраненный
+	// order_uid нарушает processed_messages_saved_order_idx и возвращается как ErrConflict.
+	insertProcessedMessage = `
+	INSERT INTO processed_messages (topic, partition, "offset", order_uid, content_hash, outcome)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (topic, partition, "offset") DO NOTHING
+	`
+
+	deleteProcessedMessages = `
+	DELETE FROM processed_messages WHERE processed_at < $1
+	`
+)
+
+// CheckMessage ищет сообщение в журнале по позиции и сохраненную версию заказа по order_uid.
+// Обходится одним запросом по индексам и не трогает таблицы заказов.
+func (r *RequestRepositoryPostgres) CheckMessage(ctx context.Context, msg domain.ProcessedMessage) (domain.MessageOutcome, error) {
+	result := struct {
+		Seen      bool           `db:"seen"`
+		SavedHash sql.NullString `db:"saved_hash"`
+	}{}
+
+	queryCtx, end := r.startQuery(ctx, queryCheckMessage)
+	err := r.db.GetContext(queryCtx, &result, checkProcessedMessage, msg.Topic, msg.Partition, msg.Offset, msg.OrderUID)
+	err = end(err)
+	if err != nil {
+		return domain.OutcomeNew, fmt.Errorf("failed to check processed message: %w", err)
+	}
+
+	switch {
+	case result.Seen:
+		return domain.OutcomeDuplicate, nil
+	case !result.SavedHash.Valid:
+		return domain.OutcomeNew, nil
+	case result.SavedHash.String == msg.ContentHash:
+		return domain.OutcomeDuplicate, nil
+	default:
+		return domain.OutcomeConflict, nil
+	}
+}
+
+// RecordMessage записывает итог обработки сообщения в журнал.
+func (r *RequestRepositoryPostgres) RecordMessage(ctx context.Context, msg domain.ProcessedMessage, outcome domain.MessageOutcome) error {
+	queryCtx, end := r.startQuery(ctx, queryRecordMessage)
+	_, err := r.db.ExecContext(queryCtx, insertProcessedMessage,
+		msg.Topic, msg.Partition, msg.Offset, msg.OrderUID, msg.ContentHash, string(outcome))
+	err = end(err)
+	if err != nil {
+		return fmt.Errorf("failed to record processed message: %w", err)
+	}
+	return nil
+}
+
+// CleanupMessages удаляет записи журнала, сделанные раньше before.
+func (r *RequestRepositoryPostgres) CleanupMessages(ctx context.Context, before time.Time) (int, error) {
+	queryCtx, end := r.startQuery(ctx, queryCleanupMessages)
+	result, err := r.db.ExecContext(queryCtx, deleteProcessedMessages, before)
+	err = end(err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete processed messages: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted processed messages: %w", err)
+	}
+	return int(deleted), nil
+}
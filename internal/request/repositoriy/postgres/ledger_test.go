@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageLedger(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { cleanRepo(testDB) })
+
+	saved := domain.ProcessedMessage{Topic: "orders", Partition: 0, Offset: 10, OrderUID: "order_1", ContentHash: "aaa"}
+
+	t.Run("new_message", func(t *testing.T) {
+		outcome, err := repo.CheckMessage(ctx, saved)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeNew, outcome)
+		require.NoError(t, repo.RecordMessage(ctx, saved, domain.OutcomeSaved))
+	})
+
+	t.Run("redelivered_message", func(t *testing.T) {
+		outcome, err := repo.CheckMessage(ctx, saved)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+		// Повторная запись той же позиции игнорируется
+		require.NoError(t, repo.RecordMessage(ctx, saved, domain.OutcomeSaved))
+	})
+
+	t.Run("same_order_resent", func(t *testing.T) {
+		resent := saved
+		resent.Offset = 11
+		outcome, err := repo.CheckMessage(ctx, resent)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+		require.NoError(t, repo.RecordMessage(ctx, resent, domain.OutcomeDuplicate))
+	})
+
+	t.Run("conflicting_order_resent", func(t *testing.T) {
+		conflicting := saved
+		conflicting.Offset = 12
+		conflicting.ContentHash = "bbb"
+		outcome, err := repo.CheckMessage(ctx, conflicting)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeConflict, outcome)
+
+		// Вторая сохраненная версия заказа отвергается индексом
+		require.ErrorIs(t, repo.RecordMessage(ctx, conflicting, domain.OutcomeSaved), domain.ErrConflict)
+		require.NoError(t, repo.RecordMessage(ctx, conflicting, domain.OutcomeConflict))
+	})
+
+	var count int
+	require.NoError(t, testDB.Get(&count, `SELECT count(*) FROM processed_messages`))
+	require.Equal(t, 3, count)
+
+	t.Run("cleanup", func(t *testing.T) {
+		deleted, err := repo.CleanupMessages(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+
+		deleted, err = repo.CleanupMessages(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 3, deleted)
+	})
+}
@@ -0,0 +1,30 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"order_service/internal/logger"
+	"order_service/internal/request/repositoriy/migrations"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { cleanRepo(testDB) })
+
+	// TestMain уже применил миграции
+	require.NoError(t, migrations.Run(ctx, testDB.DB, migrations.ModeVerify, logger.Discard()))
+	require.NoError(t, migrations.Run(ctx, testDB.DB, migrations.ModeAuto, logger.Discard()))
+
+	provider, err := migrations.NewProvider(testDB.DB)
+	require.NoError(t, err)
+	_, err = provider.Down(ctx)
+	require.NoError(t, err)
+
+	// Схема отстает от сервиса: verify не дает запуститься, auto догоняет
+	require.ErrorIs(t, migrations.Run(ctx, testDB.DB, migrations.ModeVerify, logger.Discard()), migrations.ErrVersionMismatch)
+	require.NoError(t, migrations.Run(ctx, testDB.DB, migrations.ModeAuto, logger.Discard()))
+	require.NoError(t, migrations.Run(ctx, testDB.DB, migrations.ModeVerify, logger.Discard()))
+}
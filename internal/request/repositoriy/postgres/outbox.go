@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Имена запросов outbox для метрик
+const (
+	queryInsertOutbox        = "insert_outbox"
+	queryClaimOutbox         = "claim_outbox"
+	queryMarkOutboxPublished = "mark_outbox_published"
+	queryMarkOutboxFailed    = "mark_outbox_failed"
+	queryCleanupOutbox       = "cleanup_outbox"
+)
+
+// maxOutboxErrorLen ограничивает текст последней ошибки, сохраняемый в outbox.
+const maxOutboxErrorLen = 1024
+
+const (
+	insertRowIntoOutbox = `
+	INSERT INTO outbox (event_type, event_key, payload, headers)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	claimOutboxRows = `
+	UPDATE outbox
+	SET claimed_until = now() + $2::bigint * interval '1 millisecond'
+	WHERE id IN (
+		SELECT o.id
+		FROM outbox o
+		WHERE o.published_at IS NULL AND o.failed_at IS NULL
+			AND (o.claimed_until IS NULL OR o.claimed_until < now())
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox p
+				WHERE p.event_key = o.event_key AND p.id < o.id
+					AND p.published_at IS NULL AND p.failed_at IS NULL
+			)
+		ORDER BY o.id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, event_type, event_key, payload, headers, created_at, attempts
+	`
+
+	markOutboxPublished = `
+	UPDATE outbox
+	SET published_at = now(), claimed_until = NULL, last_error = NULL
+	WHERE id = ANY($1::bigint[])
+	`
+
+	markOutboxFailed = `
+	UPDATE outbox
+	SET attempts = outbox.attempts + 1,
+		last_error = f.last_error,
+		claimed_until = now() + f.retry_after_ms * interval '1 millisecond',
+		failed_at = CASE WHEN f.dead THEN now() END
+	FROM unnest($1::bigint[], $2::varchar[], $3::bigint[], $4::boolean[]) AS f(id, last_error, retry_after_ms, dead)
+	WHERE outbox.id = f.id
+	`
+
+	deletePublishedOutboxRows = `
+	DELETE FROM outbox
+	WHERE published_at < $1
+	`
+)
+
+// outboxRow — строка outbox в том виде, в котором она хранится в БД.
+type outboxRow struct {
+	ID        int64     `db:"id"`
+	Type      string    `db:"event_type"`
+	Key       string    `db:"event_key"`
+	Payload   []byte    `db:"payload"`
+	Headers   []byte    `db:"headers"`
+	CreatedAt time.Time `db:"created_at"`
+	Attempts  int       `db:"attempts"`
+}
+
+// insertOrderCreated записывает событие order.created в транзакции сохранения заказа.
+func (r *RequestRepositoryPostgres) insertOrderCreated(ctx context.Context, tx *sqlx.Tx, order *domain.Order) error {
+	payload, headers, err := orderCreatedEvent(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	queryCtx, end := r.startQuery(ctx, queryInsertOutbox)
+	_, err = tx.ExecContext(queryCtx, insertRowIntoOutbox, domain.EventOrderCreated, order.OrderUID, string(payload), string(headers))
+	err = end(err)
+	if err != nil {
+		return fmt.Errorf("failed to insert row into outbox: %w", err)
+	}
+	return nil
+}
+
+// orderCreatedEvent кодирует событие order.created и его заголовки для outbox.
+// Вместе с событием сохраняется trace context, чтобы trace продолжился у получателей.
+func orderCreatedEvent(ctx context.Context, order *domain.Order) (payload, headers []byte, err error) {
+	payload, err = json.Marshal(domain.OrderCreatedPayload{
+		Type:       domain.EventOrderCreated,
+		OrderUID:   order.OrderUID,
+		OccurredAt: time.Now().UTC(),
+		Order:      order.Redacted(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	headers, err = json.Marshal(carrier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode outbox headers: %w", err)
+	}
+	return payload, headers, nil
+}
+
+// ClaimOutbox забирает до limit готовых к публикации событий и скрывает их от других relay
+// на время lease. Транзакция короткая: публикация идет уже после ее фиксации, а если relay
+// не запишет результат, события снова выдаются по истечении lease.
+func (r *RequestRepositoryPostgres) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	rows := []outboxRow{}
+	queryCtx, end := r.startQuery(ctx, queryClaimOutbox)
+	err := r.db.SelectContext(queryCtx, &rows, claimOutboxRows, limit, lease.Milliseconds())
+	err = end(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox rows: %w", err)
+	}
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	events := make([]domain.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		headers := map[string]string{}
+		if err := json.Unmarshal(row.Headers, &headers); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox headers: %w", err)
+		}
+		events = append(events, domain.OutboxEvent{
+			ID: row.ID, Type: row.Type, Key: row.Key, Payload: row.Payload,
+			Headers: headers, CreatedAt: row.CreatedAt, Attempts: row.Attempts,
+		})
+	}
+	return events, nil
+}
+
+// CompleteOutbox в одной транзакции помечает опубликованные события и записывает неудачные
+// попытки: число попыток, текст ошибки, время следующей выдачи и, для исчерпавших попытки,
+// failed_at.
+func (r *RequestRepositoryPostgres) CompleteOutbox(ctx context.Context, published []int64, failed []domain.OutboxFailure) error {
+	if len(published) == 0 && len(failed) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", dbError(err))
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.log.ErrorContext(ctx, "Failed to rollback transaction", slog.Any("error", err))
+		}
+	}()
+
+	if len(published) > 0 {
+		queryCtx, end := r.startQuery(ctx, queryMarkOutboxPublished)
+		_, err = tx.ExecContext(queryCtx, markOutboxPublished, published)
+		err = end(err)
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox rows published: %w", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		ids := make([]int64, 0, len(failed))
+		lastErrors := make([]string, 0, len(failed))
+		retryAfter := make([]int64, 0, len(failed))
+		dead := make([]bool, 0, len(failed))
+		for _, failure := range failed {
+			lastError := failure.Error
+			if len(lastError) > maxOutboxErrorLen {
+				lastError = strings.ToValidUTF8(lastError[:maxOutboxErrorLen], "")
+			}
+			ids = append(ids, failure.ID)
+			lastErrors = append(lastErrors, lastError)
+			retryAfter = append(retryAfter, failure.RetryAfter.Milliseconds())
+			dead = append(dead, failure.Dead)
+		}
+
+		queryCtx, end := r.startQuery(ctx, queryMarkOutboxFailed)
+		_, err = tx.ExecContext(queryCtx, markOutboxFailed, ids, lastErrors, retryAfter, dead)
+		err = end(err)
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox rows failed: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+	return nil
+}
+
+// CleanupOutbox удаляет события, опубликованные раньше before.
+func (r *RequestRepositoryPostgres) CleanupOutbox(ctx context.Context, before time.Time) (int, error) {
+	queryCtx, end := r.startQuery(ctx, queryCleanupOutbox)
+	result, err := r.db.ExecContext(queryCtx, deletePublishedOutboxRows, before)
+	err = end(err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete outbox rows: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted outbox rows: %w", err)
+	}
+	return int(deleted), nil
+}
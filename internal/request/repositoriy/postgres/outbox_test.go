@@ -0,0 +1,138 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { cleanRepo(testDB) })
+
+	// ids возвращает ID событий
+	ids := func(events []domain.OutboxEvent) []int64 {
+		result := make([]int64, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.ID)
+		}
+		return result
+	}
+
+	t.Run("written_with_order", func(t *testing.T) {
+		saveOrder(t, repo, testOrders[0])
+		// Повторно присланный заказ не порождает второе событие
+		require.False(t, saveOrder(t, repo, testOrders[0]))
+		saveOrder(t, repo, testOrders[1])
+
+		var count int
+		require.NoError(t, testDB.Get(&count, `SELECT count(*) FROM outbox`))
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("publish_failure_is_recorded", func(t *testing.T) {
+		events, err := repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		// Выданные события скрыты от другого relay до конца lease
+		claimed, err := repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Empty(t, claimed)
+
+		require.NoError(t, repo.CompleteOutbox(ctx, nil, []domain.OutboxFailure{
+			{ID: events[0].ID, Error: "broker unavailable"},
+			{ID: events[1].ID, Error: "broker unavailable"},
+		}))
+
+		var failed struct {
+			Attempts  int    `db:"attempts"`
+			LastError string `db:"last_error"`
+		}
+		require.NoError(t, testDB.Get(&failed, `SELECT attempts, last_error FROM outbox ORDER BY id LIMIT 1`))
+		require.Equal(t, 1, failed.Attempts)
+		require.Equal(t, "broker unavailable", failed.LastError)
+	})
+
+	t.Run("published_in_order", func(t *testing.T) {
+		events, err := repo.ClaimOutbox(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.NoError(t, repo.CompleteOutbox(ctx, ids(events), nil))
+
+		next, err := repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		require.NoError(t, repo.CompleteOutbox(ctx, ids(next), nil))
+		events = append(events, next...)
+
+		require.Equal(t, testOrders[0].OrderUID, events[0].Key)
+		require.Equal(t, testOrders[1].OrderUID, events[1].Key)
+		require.Equal(t, domain.EventOrderCreated, events[0].Type)
+		require.Equal(t, 1, events[0].Attempts)
+
+		var payload domain.OrderCreatedPayload
+		require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+		require.Equal(t, testOrders[0].OrderUID, payload.OrderUID)
+		require.Equal(t, testOrders[0].Redacted(), payload.Order, "outbox is not encrypted, so PII is masked")
+
+		// Опубликованные события больше не выдаются
+		events, err = repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+
+	t.Run("retry_delay_and_dead_event", func(t *testing.T) {
+		for range 2 {
+			_, err := testDB.Exec(`INSERT INTO outbox (event_type, event_key, payload) VALUES ($1, 'order_x', '{}')`,
+				domain.EventOrderCreated)
+			require.NoError(t, err)
+		}
+
+		// Второе событие заказа ждет публикации первого
+		events, err := repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		first := events[0]
+
+		require.NoError(t, repo.CompleteOutbox(ctx, nil, []domain.OutboxFailure{
+			{ID: first.ID, Error: "message too large", RetryAfter: time.Hour},
+		}))
+		events, err = repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Empty(t, events, "failed event waits for its retry delay and still holds back the next one")
+
+		// Событие, исчерпавшее попытки, больше не выдается и не задерживает следующие
+		require.NoError(t, repo.CompleteOutbox(ctx, nil, []domain.OutboxFailure{
+			{ID: first.ID, Error: "message too large", Dead: true},
+		}))
+		events, err = repo.ClaimOutbox(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Greater(t, events[0].ID, first.ID)
+		require.NoError(t, repo.CompleteOutbox(ctx, ids(events), nil))
+
+		var dead struct {
+			Attempts int  `db:"attempts"`
+			Failed   bool `db:"failed"`
+		}
+		require.NoError(t, testDB.Get(&dead, `SELECT attempts, failed_at IS NOT NULL AS failed FROM outbox WHERE id = $1`, first.ID))
+		require.Equal(t, 2, dead.Attempts)
+		require.True(t, dead.Failed)
+	})
+
+	t.Run("cleanup_published", func(t *testing.T) {
+		deleted, err := repo.CleanupOutbox(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, deleted, "recently published events are kept")
+
+		deleted, err = repo.CleanupOutbox(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 3, deleted, "dead events are kept for inspection")
+	})
+}
@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"order_service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Имена операций OrderRepositoryPgx для метрик. Запросы пакета уходят в БД
+// за одно обращение, поэтому время измеряется для пакета целиком.
+const (
+	queryGetOrderBatch  = "get_order_batch"
+	queryGetOrdersBatch = "get_orders_batch"
+	querySaveOrder      = "save_order"
+)
+
+// queryInsertOrderWithEvent — имя подготовленного запроса insertRowIntoOrdersWithEvent.
+const queryInsertOrderWithEvent = "insert_order_with_event"
+
+// insertRowIntoOrdersWithEvent сохраняет заказ целиком одним запросом. delivery, payment, items
+// и событие order.created вставляются только вместе с новой строкой orders: повторно присланный
+// заказ ничего не меняет и не дополняет уже сохраненные items. Позиции передаются массивами
+// по столбцам.
+const insertRowIntoOrdersWithEvent = `
+	WITH inserted AS (
+		INSERT INTO orders
+			(order_uid, track_number, entry, locale, internal_signature,
+			customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (order_uid) DO NOTHING
+		RETURNING order_uid
+	), inserted_delivery AS (
+		INSERT INTO delivery
+			(order_uid, name, phone, zip, city, address, region, email, email_bidx)
+		SELECT order_uid, $12::varchar, $13::varchar, $14::varchar, $15::varchar,
+			$16::varchar, $17::varchar, $18::varchar, $19::varchar
+		FROM inserted
+	), inserted_payment AS (
+		INSERT INTO payment
+			(order_uid, transaction, request_id, currency, provider, amount,
+			payment_dt, bank, delivery_cost, goods_total, custom_fee)
+		SELECT order_uid, $20::varchar, $21::varchar, $22::varchar, $23::varchar, $24::integer,
+			$25::integer, $26::varchar, $27::integer, $28::integer, $29::integer
+		FROM inserted
+	), inserted_items AS (
+		INSERT INTO items
+			(order_uid, chrt_id, track_number, price, rid,
+			name, sale, size, total_price, nm_id, brand, status)
+		SELECT inserted.order_uid, item.*
+		FROM inserted
+		CROSS JOIN unnest(
+			$30::integer[], $31::varchar[], $32::integer[], $33::varchar[], $34::varchar[], $35::integer[],
+			$36::varchar[], $37::integer[], $38::integer[], $39::varchar[], $40::integer[]
+		) AS item (chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status)
+		ON CONFLICT (order_uid, chrt_id) DO NOTHING
+	)
+	INSERT INTO outbox (event_type, event_key, payload, headers)
+	SELECT $41::varchar, order_uid, $42::jsonb, $43::jsonb
+	FROM inserted
+	`
+
+// pgxStatements — запросы OrderRepositoryPgx, подготавливаемые на каждом соединении пула.
+// Подготовленный запрос вызывается по имени, имена совпадают с именами запросов в метриках.
+var pgxStatements = map[string]string{
+	queryInsertOrderWithEvent: insertRowIntoOrdersWithEvent,
+	queryGetOrder:             getRowFromOrdersDeliveryAndPayment,
+	queryGetOrderItems:        getRowsFromItemsByOrderUID,
+	queryGetRecentOrders:      getActualRowsFromOrders,
+	queryGetOrders:            getRowsFromOrdersDeliveryAndPayment,
+	queryGetOrdersItems:       getRowsFromItemsByOrderUIDs,
+	queryFindByEmail:          getOrderUIDsByEmailIndex,
+}
+
+// OrderRepositoryPgx — репозиторий заказов на pgxpool с теми же таблицами, шифрованием
+// и событиями outbox, что у RequestRepositoryPostgres. Запросы подготовлены на соединениях
+// пула (см. NewPool), а запросы одной операции отправляются пакетом или объединяются: сохранение
+// заказа занимает одно обращение к БД вместо 5+N, чтение заказа — одно вместо двух.
+type OrderRepositoryPgx struct {
+	pool    *pgxpool.Pool
+	cipher  domain.FieldCipher
+	metrics domain.RepositoryMetrics
+	log     *slog.Logger
+}
+
+// NewOrderRepositoryPgx создает репозиторий заказов на пуле, созданном NewPool.
+// Персональные данные в delivery (name, phone, address, email) шифруются через cipher.
+func NewOrderRepositoryPgx(pool *pgxpool.Pool, cipher domain.FieldCipher, metrics domain.RepositoryMetrics, log *slog.Logger) *OrderRepositoryPgx {
+	log.Debug("Initializing OrderRepositoryPgx with connection pool")
+	return &OrderRepositoryPgx{pool: pool, cipher: cipher, metrics: metrics, log: log}
+}
+
+// GetOrder получает заказ и его items одним пакетом.
+func (r *OrderRepositoryPgx) GetOrder(ctx context.Context, orderUID string) (*domain.Order, error) {
+	var (
+		orderData domain.OrderWithoutItems
+		itemsData []domain.Item
+	)
+	batch := &pgx.Batch{}
+	batch.Queue(queryGetOrder, orderUID).Query(func(rows pgx.Rows) (err error) {
+		orderData, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[domain.OrderWithoutItems])
+		return err
+	})
+	batch.Queue(queryGetOrderItems, orderUID).Query(func(rows pgx.Rows) (err error) {
+		itemsData, err = pgx.CollectRows(rows, pgx.RowToStructByName[domain.Item])
+		return err
+	})
+
+	queryCtx, end := traceQuery(ctx, r.metrics, queryGetOrderBatch)
+	err := end(r.pool.SendBatch(queryCtx, batch).Close())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to select order: %w", err)
+	}
+	if err := decryptDelivery(r.cipher, &orderData); err != nil {
+		return nil, err
+	}
+
+	order, err := assembleOrder(orderUID, &orderData, itemsData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble order: %w", err)
+	}
+
+	return order, nil
+}
+
+// GetOrders получает список заказов с ограничением по количеству (сортировка по дате создания DESC).
+func (r *OrderRepositoryPgx) GetOrders(ctx context.Context, quantity int) ([]*domain.Order, error) {
+	orderUIDs, err := r.selectOrderUIDs(ctx, queryGetRecentOrders, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select orders rows: %w", err)
+	}
+	if len(orderUIDs) == 0 {
+		return []*domain.Order{}, nil
+	}
+
+	return r.getOrdersByUIDs(ctx, orderUIDs)
+}
+
+// FindOrdersByEmail ищет заказы по email получателя через blind index,
+// не расшифровывая колонку email в БД.
+func (r *OrderRepositoryPgx) FindOrdersByEmail(ctx context.Context, email string) ([]*domain.Order, error) {
+	orderUIDs, err := r.selectOrderUIDs(ctx, queryFindByEmail, r.cipher.BlindIndex(email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select orders by email: %w", err)
+	}
+	if len(orderUIDs) == 0 {
+		return nil, domain.ErrOrdersNotFound
+	}
+
+	return r.getOrdersByUIDs(ctx, orderUIDs)
+}
+
+// selectOrderUIDs выполняет подготовленный запрос query, возвращающий order_uid.
+func (r *OrderRepositoryPgx) selectOrderUIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	queryCtx, end := traceQuery(ctx, r.metrics, query)
+	rows, err := r.pool.Query(queryCtx, query, args...)
+	var orderUIDs []string
+	if err == nil {
+		orderUIDs, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	return orderUIDs, end(err)
+}
+
+// getOrdersByUIDs получает заказы вместе с items одним пакетом в порядке orderUIDs.
+func (r *OrderRepositoryPgx) getOrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*domain.Order, error) {
+	var (
+		ordersData []*domain.OrderWithoutItems
+		itemsData  []domain.Item
+	)
+	batch := &pgx.Batch{}
+	batch.Queue(queryGetOrders, orderUIDs).Query(func(rows pgx.Rows) (err error) {
+		ordersData, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[domain.OrderWithoutItems])
+		return err
+	})
+	batch.Queue(queryGetOrdersItems, orderUIDs).Query(func(rows pgx.Rows) (err error) {
+		itemsData, err = pgx.CollectRows(rows, pgx.RowToStructByName[domain.Item])
+		return err
+	})
+
+	queryCtx, end := traceQuery(ctx, r.metrics, queryGetOrdersBatch)
+	if err := end(r.pool.SendBatch(queryCtx, batch).Close()); err != nil {
+		return nil, fmt.Errorf("failed to select orders: %w", err)
+	}
+	for _, orderData := range ordersData {
+		if err := decryptDelivery(r.cipher, orderData); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := assembleOrders(orderUIDs, ordersData, itemsData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// SaveOrder сохраняет новый заказ вместе с событием order.created в outbox одним запросом.
+// Повторно присланный заказ не вставляется и не порождает второе событие, тогда inserted равен false.
+func (r *OrderRepositoryPgx) SaveOrder(ctx context.Context, order *domain.Order) (bool, error) {
+	delivery, err := encryptDelivery(r.cipher, order.OrderUID, order.Delivery)
+	if err != nil {
+		return false, err
+	}
+	payload, headers, err := orderCreatedEvent(ctx, order)
+	if err != nil {
+		return false, err
+	}
+
+	items := newItemColumns(order.Items)
+	queryCtx, end := traceQuery(ctx, r.metrics, querySaveOrder)
+	tag, err := r.pool.Exec(queryCtx, queryInsertOrderWithEvent,
+		order.OrderUID, order.TrackNumber, order.Entry, order.Locale, order.InternalSignature,
+		order.CustomerID, order.DeliveryService, order.ShardKey, order.SmID, order.DateCreated, order.OofShard,
+		delivery.Name, delivery.Phone, order.Zip, order.City,
+		delivery.Address, order.Region, delivery.Email, r.cipher.BlindIndex(order.Email),
+		order.Transaction, order.RequestID, order.Currency, order.Provider, order.Amount,
+		order.PaymentDt, order.Bank, order.DeliveryCost, order.GoodsTotal, order.CustomFee,
+		items.chrtID, items.trackNumber, items.price, items.rid, items.name, items.sale,
+		items.size, items.totalPrice, items.nmID, items.brand, items.status,
+		domain.EventOrderCreated, string(payload), string(headers))
+	if err := end(err); err != nil {
+		return false, fmt.Errorf("failed to save order: %w", err)
+	}
+
+	// Событие вставляется только вместе с новым заказом
+	return tag.RowsAffected() > 0, nil
+}
+
+// itemColumns — позиции заказа, разложенные по столбцам items для unnest.
+type itemColumns struct {
+	chrtID, price, sale, totalPrice, nmID, status []int
+	trackNumber, rid, name, size, brand           []string
+}
+
+func newItemColumns(items []domain.Item) itemColumns {
+	columns := itemColumns{}
+	for _, item := range items {
+		columns.chrtID = append(columns.chrtID, item.ChrtID)
+		columns.trackNumber = append(columns.trackNumber, item.TrackNumber)
+		columns.price = append(columns.price, item.Price)
+		columns.rid = append(columns.rid, item.Rid)
+		columns.name = append(columns.name, item.Name)
+		columns.sale = append(columns.sale, item.Sale)
+		columns.size = append(columns.size, item.Size)
+		columns.totalPrice = append(columns.totalPrice, item.TotalPrice)
+		columns.nmID = append(columns.nmID, item.NmID)
+		columns.brand = append(columns.brand, item.Brand)
+		columns.status = append(columns.status, item.Status)
+	}
+	return columns
+}
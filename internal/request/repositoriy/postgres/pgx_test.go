@@ -0,0 +1,147 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"order_service/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepositoryPgx(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { cleanRepo(testDB) })
+
+	t.Run("save_order", func(t *testing.T) {
+		require.True(t, saveOrder(t, pgxRepo, testOrders[0]))
+		// Повторный заказ не вставляется и не порождает второе событие
+		require.False(t, saveOrder(t, pgxRepo, testOrders[0]))
+		require.False(t, saveOrder(t, pgxRepo, resentOrder(testOrders[0])))
+
+		var events int
+		require.NoError(t, testDB.Get(&events, `SELECT count(*) FROM outbox WHERE event_key = $1`, testOrders[0].OrderUID))
+		require.Equal(t, 1, events)
+	})
+
+	t.Run("get_order", func(t *testing.T) {
+		order, err := pgxRepo.GetOrder(ctx, testOrders[0].OrderUID)
+		require.NoError(t, err)
+		require.Equal(t, *testOrders[0], *order)
+
+		// Строки, записанные pgxpool, читаются репозиторием на sqlx
+		order, err = repo.GetOrder(ctx, testOrders[0].OrderUID)
+		require.NoError(t, err)
+		require.Equal(t, *testOrders[0], *order)
+	})
+
+	t.Run("get_nonexistent_order", func(t *testing.T) {
+		order, err := pgxRepo.GetOrder(ctx, "nonexistent_order")
+		require.ErrorIs(t, err, domain.ErrOrderNotFound)
+		require.Nil(t, order)
+	})
+
+	t.Run("get_orders", func(t *testing.T) {
+		saveOrder(t, repo, testOrders[1])
+		orders, err := pgxRepo.GetOrders(ctx, len(testOrders))
+		require.NoError(t, err)
+		require.Equal(t, []*domain.Order{testOrders[1], testOrders[0]}, orders)
+	})
+
+	t.Run("find_orders_by_email", func(t *testing.T) {
+		orders, err := pgxRepo.FindOrdersByEmail(ctx, " TEST@gmail.com")
+		require.NoError(t, err)
+		require.Equal(t, []*domain.Order{testOrders[0]}, orders)
+
+		_, err = pgxRepo.FindOrdersByEmail(ctx, "unknown@gmail.com")
+		require.ErrorIs(t, err, domain.ErrOrdersNotFound)
+	})
+
+	t.Run("get_orders_empty", func(t *testing.T) {
+		cleanRepo(testDB)
+		orders, err := pgxRepo.GetOrders(ctx, len(testOrders))
+		require.NoError(t, err)
+		require.Empty(t, orders)
+	})
+}
+
+func TestPoolSettings(t *testing.T) {
+	ctx := context.Background()
+
+	conn, err := testPool.Acquire(ctx)
+	require.NoError(t, err)
+	defer conn.Release()
+
+	var applicationName, statementTimeout string
+	require.NoError(t, conn.QueryRow(ctx, `SHOW application_name`).Scan(&applicationName))
+	require.NoError(t, conn.QueryRow(ctx, `SHOW statement_timeout`).Scan(&statementTimeout))
+	require.Equal(t, "order_service_test", applicationName)
+	require.Equal(t, "10s", statementTimeout)
+
+	var prepared int
+	require.NoError(t, conn.QueryRow(ctx, `SELECT count(*) FROM pg_prepared_statements WHERE name LIKE '%order%'`).Scan(&prepared))
+	require.Positive(t, prepared)
+
+	require.Equal(t, 10, testDB.Stats().MaxOpenConnections)
+}
+
+// BenchmarkOrderRepository сравнивает репозитории заказов на sqlx и pgxpool:
+//
+//	go test -run '^$' -bench OrderRepository -benchmem ./internal/request/repositoriy/postgres/
+func BenchmarkOrderRepository(b *testing.B) {
+	ctx := context.Background()
+	repos := []struct {
+		name string
+		repo domain.OrderRepository
+	}{
+		{name: "sqlx", repo: repo},
+		{name: "pgxpool", repo: pgxRepo},
+	}
+
+	for _, r := range repos {
+		b.Run(r.name+"/save_order", func(b *testing.B) {
+			b.Cleanup(func() { cleanRepo(testDB) })
+			for i := 0; b.Loop(); i++ {
+				saveOrder(b, r.repo, benchOrder(fmt.Sprintf("bench_%s_%d", r.name, i)))
+			}
+		})
+
+		b.Run(r.name+"/get_order", func(b *testing.B) {
+			b.Cleanup(func() { cleanRepo(testDB) })
+			order := benchOrder("bench_" + r.name)
+			saveOrder(b, r.repo, order)
+			for b.Loop() {
+				_, err := r.repo.GetOrder(ctx, order.OrderUID)
+				require.NoError(b, err)
+			}
+		})
+
+		b.Run(r.name+"/get_orders", func(b *testing.B) {
+			b.Cleanup(func() { cleanRepo(testDB) })
+			const quantity = 50
+			for i := range quantity {
+				saveOrder(b, r.repo, benchOrder(fmt.Sprintf("bench_%s_%d", r.name, i)))
+			}
+			for b.Loop() {
+				orders, err := r.repo.GetOrders(ctx, quantity)
+				require.NoError(b, err)
+				require.Len(b, orders, quantity)
+			}
+		})
+	}
+}
+
+// benchOrder возвращает копию testOrders[0] с order_uid uid и тремя items.
+func benchOrder(uid string) *domain.Order {
+	order := *testOrders[0]
+	order.OrderUID = uid
+	order.Items = make([]domain.Item, 3)
+	for i := range order.Items {
+		item := testOrders[0].Items[0]
+		item.OrderUID = uid
+		item.ChrtID += i
+		order.Items[i] = item
+	}
+	return &order
+}
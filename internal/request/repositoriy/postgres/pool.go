@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"order_service/config"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// Connect подключается к БД через database/sql и настраивает пул по секции postgres.pool.
+// Нулевые значения оставляют настройки database/sql по умолчанию.
+func Connect(ctx context.Context, cfg *config.Config) (*sqlx.DB, error) {
+	db, err := sqlx.ConnectContext(ctx, "pgx", config.GetDbConnString(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", dbError(err))
+	}
+
+	pool := cfg.Db.Pool
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	// SetMaxIdleConns(0) не оставляет простаивающих соединений вовсе, а не снимает ограничение
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	return db, nil
+}
+
+// NewPool создает пул pgxpool для OrderRepositoryPgx по секции postgres.pool. У pgxpool нет
+// ограничения на число простаивающих соединений, поэтому max_idle_conns к нему не применяется:
+// простаивающие соединения закрываются по conn_max_idle_time, а min_idle_conns соединений пул
+// держит открытыми наготове. На каждом новом соединении подготавливаются запросы репозитория,
+// поэтому пул создается после миграций.
+func NewPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(config.GetDbConnString(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+
+	pool := cfg.Db.Pool
+	if pool.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(min(pool.MaxOpenConns, math.MaxInt32)) // #nosec G115 -- ограничено выше
+	}
+	if pool.MinIdleConns > 0 {
+		poolCfg.MinIdleConns = int32(min(pool.MinIdleConns, math.MaxInt32)) // #nosec G115 -- ограничено выше
+	}
+	if pool.ConnMaxLifetime > 0 {
+		poolCfg.MaxConnLifetime = pool.ConnMaxLifetime
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = pool.ConnMaxIdleTime
+	}
+	poolCfg.AfterConnect = prepareStatements
+
+	db, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+	}
+	if err := db.Ping(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", dbError(err))
+	}
+	return db, nil
+}
+
+// prepareStatements подготавливает запросы pgxStatements на соединении.
+func prepareStatements(ctx context.Context, conn *pgx.Conn) error {
+	for name, sql := range pgxStatements {
+		if _, err := conn.Prepare(ctx, name, sql); err != nil {
+			return fmt.Errorf("failed to prepare %s: %w", name, err)
+		}
+	}
+	return nil
+}
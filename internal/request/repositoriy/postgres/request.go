@@ -5,23 +5,51 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"order_service/internal/domain"
-	"order_service/internal/logger"
 
 	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("order_service/internal/request/repositoriy/postgres")
+
 type RequestRepositoryPostgres struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	cipher  domain.FieldCipher
+	metrics domain.RepositoryMetrics
+	log     *slog.Logger
 }
 
-// NewRequestRepositoryPostgres создает новый PostgreSQL репозиторий с подключением к БД
-func NewRequestRepositoryPostgres(db *sqlx.DB) *RequestRepositoryPostgres {
-	logger.Debug("Initializing RequestRepositoryPostgres with database connection")
-	return &RequestRepositoryPostgres{db: db}
+// NewRequestRepositoryPostgres создает новый PostgreSQL репозиторий с подключением к БД.
+// Персональные данные в delivery (name, phone, address, email) шифруются через cipher.
+func NewRequestRepositoryPostgres(db *sqlx.DB, cipher domain.FieldCipher, metrics domain.RepositoryMetrics, log *slog.Logger) *RequestRepositoryPostgres {
+	log.Debug("Initializing RequestRepositoryPostgres with database connection")
+	return &RequestRepositoryPostgres{db: db, cipher: cipher, metrics: metrics, log: log}
 }
 
+// Имена запросов для метрик
+const (
+	queryInsertOrder     = "insert_order"
+	queryInsertDelivery  = "insert_delivery"
+	queryInsertPayment   = "insert_payment"
+	queryInsertItems     = "insert_items"
+	queryGetOrder        = "get_order"
+	queryGetOrderItems   = "get_order_items"
+	queryGetRecentOrders = "get_recent_orders"
+	queryGetOrders       = "get_orders"
+	queryGetOrdersItems  = "get_orders_items"
+	queryFindByEmail     = "find_orders_by_email"
+	queryGetOrderUIDs    = "get_order_uids"
+	queryBackfillSelect  = "backfill_select_delivery"
+	queryBackfillUpdate  = "backfill_update_delivery"
+)
+
 const (
 	// Запросы на вставку строк
 	insertRowIntoOrders = `
@@ -34,8 +62,8 @@ const (
 
 	insertRowIntoDelivery = `
 	INSERT INTO delivery
-		(order_uid, name, phone, zip, city, address, region, email)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		(order_uid, name, phone, zip, city, address, region, email, email_bidx)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	ON CONFLICT (order_uid) DO NOTHING
 	`
 
@@ -63,6 +91,20 @@ const (
 	LIMIT $1
 	`
 
+	getOrderUIDsAfter = `
+	SELECT order_uid
+	FROM orders
+	WHERE order_uid > $1
+	ORDER BY order_uid
+	LIMIT $2
+	`
+
+	getOrderUIDsByEmailIndex = `
+	SELECT order_uid
+	FROM delivery
+	WHERE email_bidx = $1
+	`
+
 	getRowFromOrdersDeliveryAndPayment = `
 	SELECT 
 		o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
@@ -106,18 +148,45 @@ const (
 	FROM items
 	WHERE order_uid = ANY($1::text[])
 	`
+
+	// Запросы для перешифрования delivery
+	getDeliveryBatch = `
+	SELECT order_uid, name, phone, address, email, COALESCE(email_bidx, '') AS email_bidx
+	FROM delivery
+	WHERE order_uid > $1
+	ORDER BY order_uid
+	LIMIT $2
+	`
+
+	updateDeliveryPII = `
+	UPDATE delivery
+	SET name = $2, phone = $3, address = $4, email = $5, email_bidx = $6
+	WHERE order_uid = $1
+	`
 )
 
 // GetOrder получает всю информацию о заказе.
 func (r *RequestRepositoryPostgres) GetOrder(ctx context.Context, orderUID string) (*domain.Order, error) {
 	// Получаем строку из orders delivery и payment
 	orderData := domain.OrderWithoutItems{}
-	if err := r.db.GetContext(ctx, &orderData, getRowFromOrdersDeliveryAndPayment, orderUID); err != nil {
+	queryCtx, end := r.startQuery(ctx, queryGetOrder)
+	err := r.db.GetContext(queryCtx, &orderData, getRowFromOrdersDeliveryAndPayment, orderUID)
+	err = end(err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to select order row: %w", err)
 	}
+	if err := decryptDelivery(r.cipher, &orderData); err != nil {
+		return nil, err
+	}
 
 	itemsData := []domain.Item{}
-	if err := r.db.SelectContext(ctx, &itemsData, getRowsFromItemsByOrderUID, orderUID); err != nil {
+	queryCtx, end = r.startQuery(ctx, queryGetOrderItems)
+	err = r.db.SelectContext(queryCtx, &itemsData, getRowsFromItemsByOrderUID, orderUID)
+	err = end(err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to select items rows: %w", err)
 	}
 
@@ -133,7 +202,10 @@ func (r *RequestRepositoryPostgres) GetOrder(ctx context.Context, orderUID strin
 func (r *RequestRepositoryPostgres) GetOrders(ctx context.Context, quantity int) ([]*domain.Order, error) {
 	orderUIDs := []string{}
 
-	if err := r.db.SelectContext(ctx, &orderUIDs, getActualRowsFromOrders, quantity); err != nil {
+	queryCtx, end := r.startQuery(ctx, queryGetRecentOrders)
+	err := r.db.SelectContext(queryCtx, &orderUIDs, getActualRowsFromOrders, quantity)
+	err = end(err)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrOrdersNotFound
 		} else {
@@ -141,13 +213,65 @@ func (r *RequestRepositoryPostgres) GetOrders(ctx context.Context, quantity int)
 		}
 	}
 
+	return r.getOrdersByUIDs(ctx, orderUIDs)
+}
+
+// FindOrdersByEmail ищет заказы по email получателя через blind index,
+// не расшифровывая колонку email в БД.
+func (r *RequestRepositoryPostgres) FindOrdersByEmail(ctx context.Context, email string) ([]*domain.Order, error) {
+	orderUIDs := []string{}
+
+	queryCtx, end := r.startQuery(ctx, queryFindByEmail)
+	err := r.db.SelectContext(queryCtx, &orderUIDs, getOrderUIDsByEmailIndex, r.cipher.BlindIndex(email))
+	err = end(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select orders by email: %w", err)
+	}
+	if len(orderUIDs) == 0 {
+		return nil, domain.ErrOrdersNotFound
+	}
+
+	return r.getOrdersByUIDs(ctx, orderUIDs)
+}
+
+// ListOrdersAfter получает до limit заказов с order_uid больше afterUID в порядке order_uid.
+// Передавая order_uid последнего заказа в следующий вызов, можно обойти все заказы пачками.
+func (r *RequestRepositoryPostgres) ListOrdersAfter(ctx context.Context, afterUID string, limit int) ([]*domain.Order, error) {
+	orderUIDs := []string{}
+
+	queryCtx, end := r.startQuery(ctx, queryGetOrderUIDs)
+	err := r.db.SelectContext(queryCtx, &orderUIDs, getOrderUIDsAfter, afterUID, limit)
+	err = end(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select order uids: %w", err)
+	}
+	if len(orderUIDs) == 0 {
+		return []*domain.Order{}, nil
+	}
+
+	return r.getOrdersByUIDs(ctx, orderUIDs)
+}
+
+// getOrdersByUIDs получает заказы вместе с items в порядке orderUIDs.
+func (r *RequestRepositoryPostgres) getOrdersByUIDs(ctx context.Context, orderUIDs []string) ([]*domain.Order, error) {
 	ordersData := []*domain.OrderWithoutItems{}
-	if err := r.db.SelectContext(ctx, &ordersData, getRowsFromOrdersDeliveryAndPayment, orderUIDs); err != nil {
+	queryCtx, end := r.startQuery(ctx, queryGetOrders)
+	err := r.db.SelectContext(queryCtx, &ordersData, getRowsFromOrdersDeliveryAndPayment, orderUIDs)
+	err = end(err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to select orders rows: %w", err)
 	}
+	for _, orderData := range ordersData {
+		if err := decryptDelivery(r.cipher, orderData); err != nil {
+			return nil, err
+		}
+	}
 
 	itemsData := []domain.Item{}
-	if err := r.db.SelectContext(ctx, &itemsData, getRowsFromItemsByOrderUIDs, orderUIDs); err != nil {
+	queryCtx, end = r.startQuery(ctx, queryGetOrdersItems)
+	err = r.db.SelectContext(queryCtx, &itemsData, getRowsFromItemsByOrderUIDs, orderUIDs)
+	err = end(err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to select items rows: %w", err)
 	}
 
@@ -159,21 +283,23 @@ func (r *RequestRepositoryPostgres) GetOrders(ctx context.Context, quantity int)
 	return orders, nil
 }
 
-// SaveOrder сохраняет новый заказ.
-func (r *RequestRepositoryPostgres) SaveOrder(ctx context.Context, order *domain.Order) error {
+// SaveOrder сохраняет новый заказ и в той же транзакции записывает в outbox событие order.created.
+// Повторно присланный заказ не вставляется, тогда inserted равен false.
+func (r *RequestRepositoryPostgres) SaveOrder(ctx context.Context, order *domain.Order) (bool, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return false, fmt.Errorf("failed to begin transaction: %w", dbError(err))
 	}
 	defer func() {
 		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
-			logger.ErrorLogger.Println("failed to rollback transaction:", err)
+			r.log.ErrorContext(ctx, "Failed to rollback transaction", slog.Any("error", err))
 		}
 	}()
 
 	// Вставляем строку в orders
-	_, err = tx.ExecContext(
-		ctx,
+	queryCtx, end := r.startQuery(ctx, queryInsertOrder)
+	result, err := tx.ExecContext(
+		queryCtx,
 		insertRowIntoOrders,
 		order.OrderUID,
 		order.TrackNumber,
@@ -187,30 +313,48 @@ func (r *RequestRepositoryPostgres) SaveOrder(ctx context.Context, order *domain
 		order.DateCreated,
 		order.OofShard,
 	)
+	err = end(err)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert row into orders: %w", err)
+	}
+	// Повторно присланный заказ не вставляется и не порождает второе событие; его delivery,
+	// payment и items уже сохранены, и присланные заново не должны их дополнять
+	inserted, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to insert row into orders: %w", err)
+		return false, fmt.Errorf("failed to count inserted orders: %w", err)
+	}
+	if inserted == 0 {
+		return false, nil
 	}
 
-	// Вставляем строку в delivery
+	// Вставляем строку в delivery с зашифрованными персональными данными
+	delivery, err := encryptDelivery(r.cipher, order.OrderUID, order.Delivery)
+	if err != nil {
+		return false, err
+	}
+	queryCtx, end = r.startQuery(ctx, queryInsertDelivery)
 	_, err = tx.ExecContext(
-		ctx,
+		queryCtx,
 		insertRowIntoDelivery,
 		order.OrderUID,
-		order.Name,
-		order.Phone,
+		delivery.Name,
+		delivery.Phone,
 		order.Zip,
 		order.City,
-		order.Address,
+		delivery.Address,
 		order.Region,
-		order.Email,
+		delivery.Email,
+		r.cipher.BlindIndex(order.Email),
 	)
+	err = end(err)
 	if err != nil {
-		return fmt.Errorf("failed to insert row into delivery: %w", err)
+		return false, fmt.Errorf("failed to insert row into delivery: %w", err)
 	}
 
 	// Вставляем строку в payment
+	queryCtx, end = r.startQuery(ctx, queryInsertPayment)
 	_, err = tx.ExecContext(
-		ctx,
+		queryCtx,
 		insertRowIntoPayment,
 		order.OrderUID,
 		order.Transaction,
@@ -224,27 +368,177 @@ func (r *RequestRepositoryPostgres) SaveOrder(ctx context.Context, order *domain
 		order.GoodsTotal,
 		order.CustomFee,
 	)
+	err = end(err)
 	if err != nil {
-		return fmt.Errorf("failed to insert row into payment: %w", err)
+		return false, fmt.Errorf("failed to insert row into payment: %w", err)
 	}
 
 	// Вставляем строки в items
 	for _, item := range order.Items {
-		_, err = tx.ExecContext(ctx, insertRowIntoItems,
+		queryCtx, end = r.startQuery(ctx, queryInsertItems)
+		_, err = tx.ExecContext(queryCtx, insertRowIntoItems,
 			order.OrderUID, item.ChrtID, item.TrackNumber, item.Price, item.Rid,
 			item.Name, item.Sale, item.Size, item.TotalPrice, item.NmID, item.Brand, item.Status)
+		err = end(err)
 		if err != nil {
-			return fmt.Errorf("failed to insert row into items: %w", err)
+			return false, fmt.Errorf("failed to insert row into items: %w", err)
 		}
 	}
 
+	if err := r.insertOrderCreated(ctx, tx, order); err != nil {
+		return false, err
+	}
+
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return false, fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+
+	return true, nil
+}
+
+// deliveryRow — персональные данные delivery в том виде, в котором они хранятся в БД.
+type deliveryRow struct {
+	OrderUID  string `db:"order_uid"`
+	Name      string `db:"name"`
+	Phone     string `db:"phone"`
+	Address   string `db:"address"`
+	Email     string `db:"email"`
+	EmailBidx string `db:"email_bidx"`
+}
+
+// BackfillEncryption шифрует персональные данные delivery, записанные открытым текстом
+// или старым ключом, и заполняет blind index. Строки обходятся пачками по batchSize,
+// каждая пачка обновляется в своей транзакции. Возвращает количество обновленных строк.
+func (r *RequestRepositoryPostgres) BackfillEncryption(ctx context.Context, batchSize int) (int, error) {
+	updated := 0
+	lastUID := ""
+
+	for {
+		rows := []deliveryRow{}
+		queryCtx, end := r.startQuery(ctx, queryBackfillSelect)
+		err := r.db.SelectContext(queryCtx, &rows, getDeliveryBatch, lastUID, batchSize)
+		err = end(err)
+		if err != nil {
+			return updated, fmt.Errorf("failed to select delivery rows: %w", err)
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+		lastUID = rows[len(rows)-1].OrderUID
+
+		n, err := r.reencryptBatch(ctx, rows)
+		updated += n
+		if err != nil {
+			return updated, err
+		}
+		r.log.InfoContext(ctx, "Encrypted delivery batch", slog.Int("updated", n), slog.Int("total", updated))
 	}
+}
 
+// reencryptBatch перешифровывает строки пачки, которым это нужно, в одной транзакции.
+func (r *RequestRepositoryPostgres) reencryptBatch(ctx context.Context, rows []deliveryRow) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", dbError(err))
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.log.ErrorContext(ctx, "Failed to rollback transaction", slog.Any("error", err))
+		}
+	}()
+
+	updated := 0
+	for _, row := range rows {
+		if row.EmailBidx != "" && !r.cipher.NeedsReencrypt(row.Name) && !r.cipher.NeedsReencrypt(row.Phone) &&
+			!r.cipher.NeedsReencrypt(row.Address) && !r.cipher.NeedsReencrypt(row.Email) {
+			continue
+		}
+
+		plain := domain.OrderWithoutItems{OrderUID: row.OrderUID, Name: row.Name, Phone: row.Phone, Address: row.Address, Email: row.Email}
+		if err := decryptDelivery(r.cipher, &plain); err != nil {
+			return 0, err
+		}
+		delivery, err := encryptDelivery(r.cipher, row.OrderUID, domain.Delivery{
+			Name: plain.Name, Phone: plain.Phone, Address: plain.Address, Email: plain.Email,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		queryCtx, end := r.startQuery(ctx, queryBackfillUpdate)
+		_, err = tx.ExecContext(queryCtx, updateDeliveryPII, row.OrderUID,
+			delivery.Name, delivery.Phone, delivery.Address, delivery.Email, r.cipher.BlindIndex(plain.Email))
+		err = end(err)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update delivery row: %w", err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+
+	return updated, nil
+}
+
+// encryptDelivery шифрует персональные поля delivery. AAD — "<order_uid>/<колонка>".
+func encryptDelivery(cipher domain.FieldCipher, orderUID string, d domain.Delivery) (domain.Delivery, error) {
+	for column, field := range deliveryPII(&d.Name, &d.Phone, &d.Address, &d.Email) {
+		encrypted, err := cipher.Encrypt(*field, orderUID+"/"+column)
+		if err != nil {
+			return d, fmt.Errorf("failed to encrypt delivery %s: %w", column, err)
+		}
+		*field = encrypted
+	}
+	return d, nil
+}
+
+// decryptDelivery расшифровывает персональные поля delivery на месте.
+func decryptDelivery(cipher domain.FieldCipher, o *domain.OrderWithoutItems) error {
+	for column, field := range deliveryPII(&o.Name, &o.Phone, &o.Address, &o.Email) {
+		decrypted, err := cipher.Decrypt(*field, o.OrderUID+"/"+column)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt delivery %s: %w", column, err)
+		}
+		*field = decrypted
+	}
 	return nil
 }
 
+// deliveryPII сопоставляет зашифрованные колонки delivery полям структуры.
+func deliveryPII(name, phone, address, email *string) map[string]*string {
+	return map[string]*string{"name": name, "phone": phone, "address": address, "email": email}
+}
+
+// startQuery открывает span для SQL-запроса, см. traceQuery.
+func (r *RequestRepositoryPostgres) startQuery(ctx context.Context, query string) (context.Context, func(error) error) {
+	return traceQuery(ctx, r.metrics, query)
+}
+
+// traceQuery открывает span для SQL-запроса. Возвращаемая функция завершает span,
+// записывает время выполнения запроса в метрики и приводит ошибку через dbError.
+func traceQuery(ctx context.Context, metrics domain.RepositoryMetrics, query string) (context.Context, func(error) error) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "postgres."+query,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQuerySummary(query),
+		),
+	)
+
+	return ctx, func(err error) error {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveQuery(query, start)
+		return dbError(err)
+	}
+}
+
 // assembleOrder собирает заказ из структур *domain.OrderWithoutItems и []domain.Item в domain.Order
 func assembleOrder(orderUID string, orderData *domain.OrderWithoutItems, itemsData []domain.Item) (*domain.Order, error) {
 	for _, item := range itemsData {
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"order_service/internal/domain"
+)
+
+const (
+	// subscriberBuffer — сколько событий может накопиться у подписчика, прежде чем он будет отключен.
+	subscriberBuffer = 64
+	// eventLogSize — сколько последних событий хранится для возобновления подписки.
+	eventLogSize = 1024
+)
+
+// orderBroker ведет ограниченный журнал событий заказов и рассылает их подписчикам
+// внутри процесса.
+type orderBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan domain.OrderEvent]struct{}
+	events      []domain.OrderEvent
+	size        int
+	nextID      uint64
+}
+
+// newOrderBroker создает журнал на size событий. Нумерация начинается с текущего времени
+// в микросекундах: после перезапуска ID продолжают расти, и клиент со старым Last-Event-ID
+// получит ErrEventsExpired, а не чужие события с совпавшими номерами.
+func newOrderBroker(size int) *orderBroker {
+	return &orderBroker{
+		subscribers: make(map[chan domain.OrderEvent]struct{}),
+		events:      make([]domain.OrderEvent, 0, size),
+		size:        size,
+		nextID:      uint64(time.Now().UnixMicro()),
+	}
+}
+
+// subscribe регистрирует подписчика до отмены ctx. События из журнала с ID больше afterID
+// попадают в канал до новых, поэтому между повтором и подпиской ничего не теряется.
+func (b *orderBroker) subscribe(ctx context.Context, afterID uint64) (<-chan domain.OrderEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []domain.OrderEvent
+	if afterID != 0 {
+		var err error
+		if replay, err = b.since(afterID); err != nil {
+			return nil, err
+		}
+	}
+
+	ch := make(chan domain.OrderEvent, len(replay)+subscriberBuffer)
+	for _, event := range replay {
+		ch <- event
+	}
+	b.subscribers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(ch)
+	}()
+
+	return ch, nil
+}
+
+// since возвращает события после afterID. Вызывается под b.mu.
+func (b *orderBroker) since(afterID uint64) ([]domain.OrderEvent, error) {
+	oldest := b.nextID
+	if len(b.events) > 0 {
+		oldest = b.events[0].ID
+	}
+	if afterID+1 < oldest || afterID >= b.nextID {
+		return nil, domain.ErrEventsExpired
+	}
+
+	for i, event := range b.events {
+		if event.ID > afterID {
+			return append([]domain.OrderEvent(nil), b.events[i:]...), nil
+		}
+	}
+	return nil, nil
+}
+
+// publish добавляет событие в журнал и рассылает его. Не блокируется: подписчик
+// с переполненным буфером отключается, чтобы медленный клиент не задерживал сохранение заказов.
+func (b *orderBroker) publish(eventType string, order *domain.Order) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event := domain.OrderEvent{ID: b.nextID, Type: eventType, Order: order, Time: time.Now()}
+	b.nextID++
+
+	if len(b.events) == b.size {
+		copy(b.events, b.events[1:])
+		b.events = b.events[:len(b.events)-1]
+	}
+	b.events = append(b.events, event)
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *orderBroker) unsubscribe(ch chan domain.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+)
+
+// OrderIngester сохраняет заказы из Kafka, отсекая повторы по журналу обработанных сообщений.
+// Повторная доставка сообщения и повторная отправка заказа с тем же содержимым пропускаются
+// до обращения к таблицам заказов. Заказ с уже сохраненным order_uid и другим содержимым
+// не сохраняется: он учитывается в метрике и копируется в DLQ для разбора. Заказы, сохраненные
+// в обход журнала (через gRPC, orderctl import или до его появления), сравниваются с сохраненной
+// версией, когда репозиторий не вставляет заказ. Записи журнала старше kafka.ledger_retention удаляются.
+type OrderIngester struct {
+	service   domain.OrderService
+	ledger    domain.MessageLedger
+	dlq       domain.DeadLetterQueue
+	metrics   domain.ConsumerMetrics
+	log       *slog.Logger
+	retention time.Duration
+}
+
+// Задержки между повторами обработки сообщения в IngestWithRetry.
+const (
+	ingestRetryMin = 100 * time.Millisecond
+	ingestRetryMax = 30 * time.Second
+)
+
+// ledgerCleanupInterval — как часто удаляются записи журнала старше retention.
+const ledgerCleanupInterval = 10 * time.Minute
+
+// NewOrderIngester создает OrderIngester.
+func NewOrderIngester(
+	service domain.OrderService, ledger domain.MessageLedger, dlq domain.DeadLetterQueue,
+	metrics domain.ConsumerMetrics, cfg *config.Config, log *slog.Logger,
+) *OrderIngester {
+	log.Debug("Initializing OrderIngester")
+	return &OrderIngester{
+		service:   service,
+		ledger:    ledger,
+		dlq:       dlq,
+		metrics:   metrics,
+		log:       log,
+		retention: cfg.LedgerRetention,
+	}
+}
+
+// Ingest обрабатывает проверенный заказ из сообщения и возвращает итог обработки.
+func (i *OrderIngester) Ingest(ctx context.Context, msg *domain.OrderMessage) (domain.MessageOutcome, error) {
+	record, err := domain.NewProcessedMessage(msg)
+	if err != nil {
+		return domain.OutcomeNew, fmt.Errorf("failed to hash order: %w", err)
+	}
+
+	outcome, err := i.ledger.CheckMessage(ctx, record)
+	if err != nil {
+		return domain.OutcomeNew, err
+	}
+	if outcome != domain.OutcomeNew {
+		return i.skip(ctx, msg, record, outcome)
+	}
+
+	start := time.Now()
+	inserted, err := i.service.SaveOrder(ctx, msg.Order)
+	if err != nil {
+		return domain.OutcomeNew, err
+	}
+	i.metrics.ObserveSave(start)
+	if !inserted {
+		// Заказ сохранен без записи в журнале: сравниваем с сохраненной версией
+		stored, err := i.storedOutcome(ctx, record)
+		if err != nil {
+			return domain.OutcomeNew, err
+		}
+		return i.skip(ctx, msg, record, stored)
+	}
+
+	err = i.ledger.RecordMessage(ctx, record, domain.OutcomeSaved)
+	if errors.Is(err, domain.ErrConflict) {
+		// Тот же order_uid успел сохранить другой экземпляр. Проверяем заново,
+		// совпадает ли его версия с нашей
+		recheck, checkErr := i.ledger.CheckMessage(ctx, record)
+		if checkErr != nil {
+			return domain.OutcomeNew, checkErr
+		}
+		if recheck == domain.OutcomeNew {
+			return domain.OutcomeNew, err
+		}
+		return i.skip(ctx, msg, record, recheck)
+	}
+	if err != nil {
+		return domain.OutcomeNew, err
+	}
+
+	i.metrics.IncOrder(msg.Order)
+	return domain.OutcomeSaved, nil
+}
+
+// IngestWithRetry повторяет Ingest с экспоненциальной задержкой, пока сообщение не обработано
+// или не отменен ctx. Следующее сообщение партиции читать нельзя, пока не обработано текущее:
+// коммит более позднего смещения пропустил бы его. Повторяются только временные ошибки
+// (domain.ErrorCode.Transient): с остальными сообщение отправляется в DLQ и возвращается
+// OutcomeFailed. Ошибка возвращается только при отмене ctx.
+func (i *OrderIngester) IngestWithRetry(ctx context.Context, msg *domain.OrderMessage) (domain.MessageOutcome, error) {
+	delay := ingestRetryMin
+	for {
+		outcome, err := i.Ingest(ctx, msg)
+		if err == nil {
+			return outcome, nil
+		}
+		if ctx.Err() != nil {
+			return domain.OutcomeNew, err
+		}
+
+		if !domain.CodeOf(err).Transient() {
+			// Повтор не поможет: сообщение разбирается вручную по копии в DLQ
+			dlqErr := i.dlq.Send(ctx, deadLetter(msg, domain.DeadLetterFailed, err.Error()))
+			if dlqErr == nil {
+				i.log.ErrorContext(ctx, "Error saving order, message sent to DLQ",
+					slog.Any("error", err), slog.String("order_uid", msg.Order.OrderUID))
+				return domain.OutcomeFailed, nil
+			}
+			err = dlqErr
+		}
+
+		i.log.ErrorContext(ctx, "Error saving order, retrying",
+			slog.Any("error", err), slog.Duration("retry_in", delay))
+		select {
+		case <-ctx.Done():
+			return domain.OutcomeNew, err
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, ingestRetryMax)
+	}
+}
+
+// RunCleanup удаляет устаревшие записи журнала каждые ledgerCleanupInterval до отмены ctx.
+func (i *OrderIngester) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(ledgerCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := i.Cleanup(ctx); err != nil {
+				i.log.ErrorContext(ctx, "Failed to clean up processed messages", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Cleanup удаляет записи журнала, сделанные раньше, чем retention назад. Повтор заказа, запись
+// о котором удалена, все равно не сохранится второй раз: его отсечет сравнение с сохраненной версией.
+func (i *OrderIngester) Cleanup(ctx context.Context) (int, error) {
+	deleted, err := i.ledger.CleanupMessages(ctx, time.Now().Add(-i.retention))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		i.log.InfoContext(ctx, "Cleaned up processed messages", slog.Int("deleted", deleted))
+	}
+	return deleted, nil
+}
+
+// storedOutcome сравнивает хеш заказа из сообщения с хешем сохраненной версии.
+func (i *OrderIngester) storedOutcome(ctx context.Context, record domain.ProcessedMessage) (domain.MessageOutcome, error) {
+	stored, err := i.service.GetOrder(ctx, record.OrderUID)
+	if err != nil {
+		return domain.OutcomeNew, err
+	}
+	hash, err := domain.ContentHash(stored)
+	if err != nil {
+		return domain.OutcomeNew, fmt.Errorf("failed to hash stored order: %w", err)
+	}
+	if hash != record.ContentHash {
+		return domain.OutcomeConflict, nil
+	}
+	return domain.OutcomeDuplicate, nil
+}
+
+// skip обрабатывает повтор или конфликт, найденные в журнале или по сохраненной версии заказа.
+func (i *OrderIngester) skip(
+	ctx context.Context, msg *domain.OrderMessage, record domain.ProcessedMessage, outcome domain.MessageOutcome,
+) (domain.MessageOutcome, error) {
+	if outcome == domain.OutcomeConflict {
+		// Сначала DLQ: если запись в журнал не удастся, повторная обработка
+		// отправит сообщение еще раз, а не потеряет его
+		detail := "order " + msg.Order.OrderUID + " is already saved with different content"
+		if err := i.dlq.Send(ctx, deadLetter(msg, domain.DeadLetterConflict, detail)); err != nil {
+			return domain.OutcomeNew, err
+		}
+		i.metrics.IncConflict()
+		i.log.WarnContext(ctx, "Conflicting order re-sent, message sent to DLQ",
+			slog.String("order_uid", msg.Order.OrderUID), slog.String("content_hash", record.ContentHash))
+	} else {
+		i.metrics.IncDuplicate()
+		i.log.InfoContext(ctx, "Duplicate order skipped", slog.String("order_uid", msg.Order.OrderUID))
+	}
+
+	if err := i.ledger.RecordMessage(ctx, record, outcome); err != nil {
+		return domain.OutcomeNew, err
+	}
+	return outcome, nil
+}
+
+// deadLetter копирует исходное сообщение с заголовками в запись для DLQ.
+func deadLetter(msg *domain.OrderMessage, reason, detail string) domain.DeadLetter {
+	return domain.DeadLetter{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   msg.Headers,
+		Reason:    reason,
+		Detail:    detail,
+	}
+}
@@ -0,0 +1,240 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+	"order_service/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// ingestMocks — зависимости OrderIngester для одного подтеста.
+type ingestMocks struct {
+	service *mock.MockOrderService
+	ledger  *mock.MockMessageLedger
+	dlq     *mock.MockDeadLetterQueue
+	metrics *mock.MockConsumerMetrics
+}
+
+func newIngester(t *testing.T) (*usecase.OrderIngester, ingestMocks) {
+	ctrl := gomock.NewController(t)
+	m := ingestMocks{
+		service: mock.NewMockOrderService(ctrl),
+		ledger:  mock.NewMockMessageLedger(ctrl),
+		dlq:     mock.NewMockDeadLetterQueue(ctrl),
+		metrics: mock.NewMockConsumerMetrics(ctrl),
+	}
+	cfg := &config.Config{Kafka: config.Kafka{LedgerRetention: time.Hour}}
+	return usecase.NewOrderIngester(m.service, m.ledger, m.dlq, m.metrics, cfg, logger.Discard()), m
+}
+
+func TestIngest(t *testing.T) {
+	order := &domain.Order{OrderUID: "order_1", TrackNumber: "WBILMTESTTRACK"}
+	msg := &domain.OrderMessage{
+		Topic: "orders", Partition: 1, Offset: 42,
+		Key: []byte("order_1"), Value: []byte(`{"order_uid":"order_1"}`),
+		Headers: []domain.MessageHeader{{Key: "content-type", Value: []byte("application/json")}},
+		Order:   order,
+	}
+	record, err := domain.NewProcessedMessage(msg)
+	require.NoError(t, err)
+
+	t.Run("new_order_saved", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(true, nil),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeSaved).Return(nil),
+		)
+		m.metrics.EXPECT().ObserveSave(gomock.Any())
+		m.metrics.EXPECT().IncOrder(order)
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeSaved, outcome)
+	})
+
+	t.Run("duplicate_skipped", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeDuplicate, nil)
+		m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeDuplicate).Return(nil)
+		m.metrics.EXPECT().IncDuplicate()
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+	})
+
+	t.Run("conflict_sent_to_dlq", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeConflict, nil)
+		gomock.InOrder(
+			m.dlq.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, letter domain.DeadLetter) error {
+					require.Equal(t, domain.DeadLetterConflict, letter.Reason)
+					require.Equal(t, msg.Value, letter.Value, "the original message is kept for replay")
+					require.Equal(t, msg.Key, letter.Key)
+					require.Equal(t, msg.Headers, letter.Headers, "the original headers are needed to decode the message")
+					require.Equal(t, int64(42), letter.Offset)
+					return nil
+				}),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeConflict).Return(nil),
+		)
+		m.metrics.EXPECT().IncConflict()
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeConflict, outcome)
+	})
+
+	t.Run("dlq_failure_not_recorded", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeConflict, nil)
+		m.dlq.EXPECT().Send(gomock.Any(), gomock.Any()).Return(domain.ErrUnavailable)
+
+		_, err := ingester.Ingest(context.Background(), msg)
+		require.ErrorIs(t, err, domain.ErrUnavailable)
+	})
+
+	t.Run("concurrent_save_rechecked", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(true, nil),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeSaved).Return(domain.ErrConflict),
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeDuplicate, nil),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeDuplicate).Return(nil),
+		)
+		m.metrics.EXPECT().ObserveSave(gomock.Any())
+		m.metrics.EXPECT().IncDuplicate()
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+	})
+
+	t.Run("saved_without_ledger_duplicate", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(false, nil),
+			m.service.EXPECT().GetOrder(gomock.Any(), order.OrderUID).Return(order, nil),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeDuplicate).Return(nil),
+		)
+		m.metrics.EXPECT().ObserveSave(gomock.Any())
+		m.metrics.EXPECT().IncDuplicate()
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+	})
+
+	t.Run("saved_without_ledger_conflict", func(t *testing.T) {
+		// Заказ сохранен через gRPC или orderctl import, и журнал о нем не знает
+		ingester, m := newIngester(t)
+		stored := &domain.Order{OrderUID: "order_1", TrackNumber: "OTHERTRACK"}
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(false, nil),
+			m.service.EXPECT().GetOrder(gomock.Any(), order.OrderUID).Return(stored, nil),
+			m.dlq.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, letter domain.DeadLetter) error {
+					require.Equal(t, domain.DeadLetterConflict, letter.Reason)
+					return nil
+				}),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeConflict).Return(nil),
+		)
+		m.metrics.EXPECT().ObserveSave(gomock.Any())
+		m.metrics.EXPECT().IncConflict()
+
+		outcome, err := ingester.Ingest(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeConflict, outcome)
+	})
+
+	t.Run("save_failed", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil)
+		m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(false, domain.ErrUnavailable)
+
+		_, err := ingester.Ingest(context.Background(), msg)
+		require.ErrorIs(t, err, domain.ErrUnavailable)
+	})
+}
+
+func TestIngestWithRetry(t *testing.T) {
+	order := &domain.Order{OrderUID: "order_1", TrackNumber: "WBILMTESTTRACK"}
+	msg := &domain.OrderMessage{Topic: "orders", Partition: 1, Offset: 42, Order: order}
+	record, err := domain.NewProcessedMessage(msg)
+	require.NoError(t, err)
+
+	t.Run("retried_until_processed", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, domain.ErrUnavailable),
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeDuplicate, nil),
+			m.ledger.EXPECT().RecordMessage(gomock.Any(), record, domain.OutcomeDuplicate).Return(nil),
+		)
+		m.metrics.EXPECT().IncDuplicate()
+
+		outcome, err := ingester.IngestWithRetry(context.Background(), msg)
+		require.NoError(t, err)
+		require.Equal(t, domain.OutcomeDuplicate, outcome)
+	})
+
+	t.Run("permanent_error_sent_to_dlq", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		gomock.InOrder(
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(false, domain.ErrInternalServer),
+			// Первая отправка в DLQ не удалась: повторяется, а не коммитится без копии
+			m.dlq.EXPECT().Send(gomock.Any(), gomock.Any()).Return(domain.ErrUnavailable),
+			m.ledger.EXPECT().CheckMessage(gomock.Any(), record).Return(domain.OutcomeNew, nil),
+			m.service.EXPECT().SaveOrder(gomock.Any(), order).Return(false, domain.ErrInternalServer),
+			m.dlq.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, letter domain.DeadLetter) error {
+					require.Equal(t, domain.DeadLetterFailed, letter.Reason)
+					require.Contains(t, letter.Detail, domain.ErrInternalServer.Error())
+					require.Equal(t, int64(42), letter.Offset)
+					return nil
+				}),
+		)
+
+		outcome, err := ingester.IngestWithRetry(context.Background(), msg)
+		require.NoError(t, err, "the message is committed")
+		require.Equal(t, domain.OutcomeFailed, outcome)
+	})
+
+	t.Run("stopped_by_context", func(t *testing.T) {
+		ingester, m := newIngester(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		m.ledger.EXPECT().CheckMessage(gomock.Any(), record).DoAndReturn(
+			func(context.Context, domain.ProcessedMessage) (domain.MessageOutcome, error) {
+				cancel()
+				return domain.OutcomeNew, domain.ErrUnavailable
+			})
+
+		_, err := ingester.IngestWithRetry(ctx, msg)
+		require.ErrorIs(t, err, domain.ErrUnavailable, "the message is left uncommitted")
+	})
+}
+
+func TestIngestCleanup(t *testing.T) {
+	ingester, m := newIngester(t)
+	m.ledger.EXPECT().CleanupMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int, error) {
+			require.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+			return 3, nil
+		})
+
+	deleted, err := ingester.Cleanup(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+}
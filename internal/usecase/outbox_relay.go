@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+)
+
+// outboxCleanupInterval — как часто relay удаляет опубликованные события старше retention.
+const outboxCleanupInterval = 10 * time.Minute
+
+// outboxPublishTimeout ограничивает публикацию одной пачки. События пачки выдаются relay
+// на вдвое больший срок, чтобы их не забрал другой relay, пока эта публикация не кончилась.
+const outboxPublishTimeout = time.Minute
+
+// OutboxRelay публикует события outbox во внешний брокер. Событие, которое не удалось
+// отправить, повторяется с экспоненциальной задержкой, не задерживая события других заказов;
+// более поздние события того же заказа ждут его публикации. После max_attempts попыток
+// событие помечается failed и больше не публикуется. Доставка — at-least-once.
+type OutboxRelay struct {
+	repo         domain.OutboxRepository
+	publisher    domain.EventPublisher
+	metrics      domain.OutboxMetrics
+	log          *slog.Logger
+	batchSize    int
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+	maxAttempts  int
+	retention    time.Duration
+}
+
+// NewOutboxRelay создает relay с параметрами из секции outbox.
+func NewOutboxRelay(repo domain.OutboxRepository, publisher domain.EventPublisher, metrics domain.OutboxMetrics, cfg *config.Config, log *slog.Logger) *OutboxRelay {
+	log.Debug("Initializing OutboxRelay")
+	return &OutboxRelay{
+		repo:         repo,
+		publisher:    publisher,
+		metrics:      metrics,
+		log:          log,
+		batchSize:    cfg.Outbox.BatchSize,
+		pollInterval: cfg.Outbox.PollInterval,
+		maxBackoff:   cfg.Outbox.MaxBackoff,
+		maxAttempts:  cfg.Outbox.MaxAttempts,
+		retention:    cfg.Outbox.Retention,
+	}
+}
+
+// Run публикует события до отмены ctx. Пока в outbox есть полные пачки, следующая
+// берется сразу, иначе relay ждет poll_interval.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.log.InfoContext(ctx, "Outbox relay is started")
+
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+	cleanup := time.NewTicker(outboxCleanupInterval)
+	defer cleanup.Stop()
+
+	backoff := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.InfoContext(ctx, "Outbox relay is stopped")
+			return
+		case <-cleanup.C:
+			if _, err := r.Cleanup(ctx); err != nil {
+				r.log.ErrorContext(ctx, "Failed to clean up outbox", slog.Any("error", err))
+			}
+		case <-poll.C:
+			published, err := r.PublishBatch(ctx)
+			delay := r.pollInterval
+			switch {
+			case err != nil:
+				backoff = r.nextBackoff(backoff)
+				delay = backoff
+				r.log.WarnContext(ctx, "Failed to publish outbox events, retrying",
+					slog.Any("error", err), slog.Duration("retry_in", delay))
+			case published > 0 && published == r.batchSize:
+				backoff = 0
+				delay = 0
+			default:
+				backoff = 0
+			}
+			poll.Reset(delay)
+		}
+	}
+}
+
+// PublishBatch забирает пачку событий, публикует ее и записывает результат каждого события.
+// Возвращает число опубликованных событий. Ошибка возвращается, если не удалось обратиться
+// к outbox или брокер отверг всю пачку; неудачи отдельных событий только записываются.
+func (r *OutboxRelay) PublishBatch(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimOutbox(ctx, r.batchSize, 2*outboxPublishTimeout)
+	if err != nil {
+		r.metrics.IncPublishFailure()
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	publishErr := r.publisher.Publish(publishCtx, events)
+	cancel()
+
+	var eventErrors domain.PublishErrors
+	batchFailed := publishErr != nil && !errors.As(publishErr, &eventErrors)
+	if batchFailed {
+		eventErrors = make(domain.PublishErrors, len(events))
+		for _, event := range events {
+			eventErrors[event.ID] = publishErr
+		}
+	}
+
+	published := make([]int64, 0, len(events))
+	failed := make([]domain.OutboxFailure, 0, len(eventErrors))
+	dead := 0
+	for _, event := range events {
+		eventErr, ok := eventErrors[event.ID]
+		if !ok {
+			published = append(published, event.ID)
+			continue
+		}
+
+		attempts := event.Attempts + 1
+		failure := domain.OutboxFailure{
+			ID:         event.ID,
+			Error:      eventErr.Error(),
+			RetryAfter: r.retryDelay(attempts),
+			Dead:       attempts >= r.maxAttempts,
+		}
+		if failure.Dead {
+			dead++
+			r.log.ErrorContext(ctx, "Outbox event failed permanently",
+				slog.Int64("event_id", event.ID), slog.String("event_type", event.Type),
+				slog.String("event_key", event.Key), slog.Int("attempts", attempts),
+				slog.Any("error", eventErr))
+		}
+		failed = append(failed, failure)
+	}
+
+	// результат пишем даже после отмены ctx: иначе опубликованные события выдадутся повторно
+	completeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), outboxPublishTimeout)
+	defer cancel()
+	if err := r.repo.CompleteOutbox(completeCtx, published, failed); err != nil {
+		r.metrics.IncPublishFailure()
+		return 0, err
+	}
+
+	if len(published) > 0 {
+		r.metrics.AddPublished(len(published))
+		r.log.DebugContext(ctx, "Published outbox events", slog.Int("count", len(published)))
+	}
+	if dead > 0 {
+		r.metrics.AddDead(dead)
+	}
+	if len(failed) == 0 {
+		return len(published), nil
+	}
+
+	r.metrics.IncPublishFailure()
+	if batchFailed {
+		return 0, publishErr
+	}
+	r.log.WarnContext(ctx, "Failed to publish some outbox events",
+		slog.Int("published", len(published)), slog.Int("failed", len(failed)), slog.Any("error", eventErrors))
+	return len(published), nil
+}
+
+// Cleanup удаляет события, опубликованные раньше, чем retention назад.
+func (r *OutboxRelay) Cleanup(ctx context.Context) (int, error) {
+	deleted, err := r.repo.CleanupOutbox(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		r.metrics.AddCleaned(deleted)
+		r.log.InfoContext(ctx, "Cleaned up published outbox events", slog.Int("deleted", deleted))
+	}
+	return deleted, nil
+}
+
+// retryDelay — задержка перед попыткой attempts+1: poll_interval, удваиваемый после каждой
+// неудачи, но не больше max_backoff.
+func (r *OutboxRelay) retryDelay(attempts int) time.Duration {
+	delay := time.Duration(0)
+	for range attempts {
+		delay = r.nextBackoff(delay)
+		if delay == r.maxBackoff {
+			break
+		}
+	}
+	return delay
+}
+
+// nextBackoff удваивает задержку, начиная с poll_interval и не превышая max_backoff.
+func (r *OutboxRelay) nextBackoff(current time.Duration) time.Duration {
+	next := 2 * current
+	if next < r.pollInterval {
+		next = r.pollInterval
+	}
+	if next > r.maxBackoff {
+		next = r.maxBackoff
+	}
+	return next
+}
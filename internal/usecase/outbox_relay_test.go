@@ -0,0 +1,210 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order_service/config"
+	"order_service/internal/domain"
+	"order_service/internal/logger"
+	"order_service/internal/mock"
+	"order_service/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var outboxCfg = &config.Config{
+	Outbox: config.Outbox{BatchSize: 2, PollInterval: 5 * time.Millisecond, MaxBackoff: 20 * time.Millisecond, MaxAttempts: 3, Retention: 24 * time.Hour},
+}
+
+func TestOutboxRelayPublishBatch(t *testing.T) {
+	first := domain.OutboxEvent{ID: 1, Type: domain.EventOrderCreated, Key: "order_1", Payload: []byte(`{}`)}
+	second := domain.OutboxEvent{ID: 2, Type: domain.EventOrderCreated, Key: "order_2", Payload: []byte(`{}`), Attempts: 1}
+	brokerDown := errors.New("broker unavailable")
+
+	t.Run("published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		mockPublisher := mock.NewMockEventPublisher(ctrl)
+		mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, 2*time.Minute).Return([]domain.OutboxEvent{first, second}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), []domain.OutboxEvent{first, second}).Return(nil)
+		mockRepo.EXPECT().CompleteOutbox(gomock.Any(), []int64{1, 2}, []domain.OutboxFailure{}).Return(nil)
+		mockMetrics.EXPECT().AddPublished(2)
+
+		published, err := relay.PublishBatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, published)
+	})
+
+	t.Run("one_event_failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		mockPublisher := mock.NewMockEventPublisher(ctrl)
+		mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return([]domain.OutboxEvent{first, second}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(domain.PublishErrors{2: brokerDown})
+		// Вторая попытка второго события: следующая через удвоенный poll_interval
+		mockRepo.EXPECT().CompleteOutbox(gomock.Any(), []int64{1}, []domain.OutboxFailure{
+			{ID: 2, Error: brokerDown.Error(), RetryAfter: 10 * time.Millisecond},
+		}).Return(nil)
+		mockMetrics.EXPECT().AddPublished(1)
+		mockMetrics.EXPECT().IncPublishFailure()
+
+		published, err := relay.PublishBatch(context.Background())
+		require.NoError(t, err, "other events of the batch are published")
+		require.Equal(t, 1, published)
+	})
+
+	t.Run("publish_failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		mockPublisher := mock.NewMockEventPublisher(ctrl)
+		mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return([]domain.OutboxEvent{first}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), []domain.OutboxEvent{first}).Return(brokerDown)
+		mockRepo.EXPECT().CompleteOutbox(gomock.Any(), []int64{}, []domain.OutboxFailure{
+			{ID: 1, Error: brokerDown.Error(), RetryAfter: 5 * time.Millisecond},
+		}).Return(nil)
+		mockMetrics.EXPECT().IncPublishFailure()
+
+		_, err := relay.PublishBatch(context.Background())
+		require.ErrorIs(t, err, brokerDown)
+	})
+
+	t.Run("attempts_exhausted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		mockPublisher := mock.NewMockEventPublisher(ctrl)
+		mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+		last := second
+		last.Attempts = 2
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return([]domain.OutboxEvent{last}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(domain.PublishErrors{2: brokerDown})
+		mockRepo.EXPECT().CompleteOutbox(gomock.Any(), []int64{}, []domain.OutboxFailure{
+			{ID: 2, Error: brokerDown.Error(), RetryAfter: 20 * time.Millisecond, Dead: true},
+		}).Return(nil)
+		mockMetrics.EXPECT().AddDead(1)
+		mockMetrics.EXPECT().IncPublishFailure()
+
+		published, err := relay.PublishBatch(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, published)
+	})
+
+	t.Run("complete_failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		mockPublisher := mock.NewMockEventPublisher(ctrl)
+		mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return([]domain.OutboxEvent{first}, nil)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().CompleteOutbox(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.ErrUnavailable)
+		mockMetrics.EXPECT().IncPublishFailure()
+
+		_, err := relay.PublishBatch(context.Background())
+		require.ErrorIs(t, err, domain.ErrUnavailable)
+	})
+
+	t.Run("empty_outbox", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockOutboxRepository(ctrl)
+		relay := usecase.NewOutboxRelay(mockRepo, mock.NewMockEventPublisher(ctrl), mock.NewMockOutboxMetrics(ctrl), outboxCfg, logger.Discard())
+
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return(nil, nil)
+
+		published, err := relay.PublishBatch(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, published)
+	})
+}
+
+func TestOutboxRelayRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockOutboxRepository(ctrl)
+	mockPublisher := mock.NewMockEventPublisher(ctrl)
+	mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+	relay := usecase.NewOutboxRelay(mockRepo, mockPublisher, mockMetrics, outboxCfg, logger.Discard())
+
+	events := []domain.OutboxEvent{
+		{ID: 1, Key: "order_1"}, {ID: 2, Key: "order_2"}, {ID: 3, Key: "order_1"},
+	}
+	done := make(chan struct{})
+
+	// Первая пачка не публикуется и выдается повторно, затем полная пачка тянет
+	// следующую сразу, а пустой outbox завершает проверку
+	var published []domain.OutboxEvent
+	mockMetrics.EXPECT().IncPublishFailure().Times(2)
+	mockMetrics.EXPECT().AddPublished(gomock.Any()).Times(2)
+	mockRepo.EXPECT().CompleteOutbox(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	gomock.InOrder(
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return(events[:2], nil).Times(3),
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return(events[2:], nil),
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).DoAndReturn(
+			func(context.Context, int, time.Duration) ([]domain.OutboxEvent, error) {
+				close(done)
+				return nil, nil
+			}),
+		mockRepo.EXPECT().ClaimOutbox(gomock.Any(), 2, gomock.Any()).Return(nil, nil).AnyTimes(),
+	)
+	gomock.InOrder(
+		mockPublisher.EXPECT().Publish(gomock.Any(), events[:2]).Return(errors.New("broker unavailable")).Times(2),
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, batch []domain.OutboxEvent) error {
+				published = append(published, batch...)
+				return nil
+			}).Times(2),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not drain the outbox")
+	}
+	cancel()
+	<-stopped
+
+	require.Equal(t, events, published, "events must be published once, in outbox order")
+}
+
+func TestOutboxRelayCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockOutboxRepository(ctrl)
+	mockMetrics := mock.NewMockOutboxMetrics(ctrl)
+	relay := usecase.NewOutboxRelay(mockRepo, mock.NewMockEventPublisher(ctrl), mockMetrics, outboxCfg, logger.Discard())
+
+	mockRepo.EXPECT().CleanupOutbox(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int, error) {
+			require.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+			return 5, nil
+		})
+	mockMetrics.EXPECT().AddCleaned(5)
+
+	deleted, err := relay.Cleanup(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5, deleted)
+
+	mockRepo.EXPECT().CleanupOutbox(gomock.Any(), gomock.Any()).Return(0, domain.ErrUnavailable)
+	_, err = relay.Cleanup(context.Background())
+	require.ErrorIs(t, err, domain.ErrUnavailable)
+}
@@ -3,23 +3,35 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"order_service/config"
 	"order_service/internal/domain"
 	"order_service/internal/logger"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("order_service/internal/usecase")
+
 type OrderRequestService struct {
-	cache domain.OrderCache
-	repo  domain.OrderRepository
+	cache  domain.OrderCache
+	repo   domain.OrderRepository
+	broker *orderBroker
+	log    *slog.Logger
 }
 
 // NewOrderRequestService создает новый сервис заказов с внедренными зависимостями кеша и репозитория.
-func NewOrderRequestService(cache domain.OrderCache, repo domain.OrderRepository) *OrderRequestService {
-	logger.Debug("Initializing OrderRequestService")
+func NewOrderRequestService(cache domain.OrderCache, repo domain.OrderRepository, log *slog.Logger) *OrderRequestService {
+	log.Debug("Initializing OrderRequestService")
 	return &OrderRequestService{
-		cache: cache,
-		repo:  repo,
+		cache:  cache,
+		repo:   repo,
+		broker: newOrderBroker(eventLogSize),
+		log:    log,
 	}
 }
 
@@ -31,45 +43,106 @@ func (s *OrderRequestService) GetOrder(ctx context.Context, orderUID string) (*d
 		err   error
 	)
 
+	ctx, span := tracer.Start(ctx, "OrderRequestService.GetOrder",
+		trace.WithAttributes(attribute.String("order.uid", orderUID)))
+	defer span.End()
+	ctx = logger.WithOrderUID(ctx, orderUID)
+
 	if ctx.Err() != nil {
 		return nil, fmt.Errorf("getting order cancelled: %w", ctx.Err())
 	}
 
-	order, ok = s.cache.GetOrder(orderUID)
+	order, ok = s.getFromCache(ctx, orderUID)
 	if !ok {
 		order, err = s.repo.GetOrder(ctx, orderUID)
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to get order")
 			return nil, fmt.Errorf("failed to get order: %w", err)
 		}
 
 		s.cache.SaveOrder(orderUID, order)
 	}
 
-	logger.InfoLogger.Printf("Successfully received order with orderUID: %s", order.OrderUID)
+	s.log.InfoContext(ctx, "Successfully received order", slog.Bool("cache_hit", ok))
 
 	return order, nil
 }
 
-// SaveOrder сохраняет заказ в кеш и репозиторий.
-func (s *OrderRequestService) SaveOrder(ctx context.Context, order *domain.Order) error {
+// SaveOrder сохраняет заказ в репозиторий и, если он новый, в кеш.
+func (s *OrderRequestService) SaveOrder(ctx context.Context, order *domain.Order) (bool, error) {
+	ctx, span := tracer.Start(ctx, "OrderRequestService.SaveOrder",
+		trace.WithAttributes(attribute.String("order.uid", order.OrderUID)))
+	defer span.End()
+	ctx = logger.WithOrderUID(ctx, order.OrderUID)
+
 	if ctx.Err() != nil {
-		return fmt.Errorf("saving order cancelled: %w", ctx.Err())
+		return false, fmt.Errorf("saving order cancelled: %w", ctx.Err())
 	}
 
+	s.log.DebugContext(ctx, "Saving order", slog.Any("order", order))
+
+	inserted, err := s.repo.SaveOrder(ctx, order)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save order")
+		return false, fmt.Errorf("failed to save order: %w", err)
+	}
+	// Заказ с тем же order_uid уже сохранен: событие о нем уже было, а эта версия могла не совпадать с сохраненной
+	if !inserted {
+		s.log.InfoContext(ctx, "Order already saved, event not published")
+		return false, nil
+	}
+
+	// В кеш попадает только сохраненная версия заказа
 	s.cache.SaveOrder(order.OrderUID, order)
 
-	if err := s.repo.SaveOrder(ctx, order); err != nil {
-		return fmt.Errorf("failed to save order: %v", err)
+	s.log.InfoContext(ctx, "Successfully saved order")
+	s.broker.publish(domain.EventOrderCreated, order)
+
+	return true, nil
+}
+
+// ListOrders возвращает последние limit заказов из БД.
+func (s *OrderRequestService) ListOrders(ctx context.Context, limit int) ([]*domain.Order, error) {
+	ctx, span := tracer.Start(ctx, "OrderRequestService.ListOrders",
+		trace.WithAttributes(attribute.Int("orders.limit", limit)))
+	defer span.End()
+
+	orders, err := s.repo.GetOrders(ctx, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list orders")
+		return nil, fmt.Errorf("failed to list orders: %w", err)
 	}
 
-	logger.InfoLogger.Printf("Successfully saved order with orderUID: %s", order.OrderUID)
+	return orders, nil
+}
+
+// FindOrdersByEmail ищет заказы по email получателя.
+func (s *OrderRequestService) FindOrdersByEmail(ctx context.Context, email string) ([]*domain.Order, error) {
+	ctx, span := tracer.Start(ctx, "OrderRequestService.FindOrdersByEmail")
+	defer span.End()
 
-	return nil
+	orders, err := s.repo.FindOrdersByEmail(ctx, email)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to find orders")
+		return nil, fmt.Errorf("failed to find orders by email: %w", err)
+	}
+
+	return orders, nil
+}
+
+// WatchOrderEvents подписывает на события новых заказов, сохраненных через SaveOrder,
+// начиная после afterID.
+func (s *OrderRequestService) WatchOrderEvents(ctx context.Context, afterID uint64) (<-chan domain.OrderEvent, error) {
+	return s.broker.subscribe(ctx, afterID)
 }
 
 // RestoreCache восстанавливает кеш из БД при запуске приложения.
 func (s *OrderRequestService) RestoreCache(ctx context.Context, cfg *config.Config) error {
-	logger.InfoLogger.Println("Restoring cache...")
+	s.log.InfoContext(ctx, "Restoring cache...")
 
 	cap := cfg.Capacity
 	orders, err := s.repo.GetOrders(ctx, cap)
@@ -78,7 +151,7 @@ func (s *OrderRequestService) RestoreCache(ctx context.Context, cfg *config.Conf
 	}
 
 	if len(orders) == 0 {
-		logger.InfoLogger.Println("No orders found for cache restoration")
+		s.log.InfoContext(ctx, "No orders found for cache restoration")
 		return nil
 	}
 
@@ -89,7 +162,18 @@ func (s *OrderRequestService) RestoreCache(ctx context.Context, cfg *config.Conf
 		s.cache.SaveOrder(order.OrderUID, order)
 	}
 
-	logger.InfoLogger.Println("Successfully restored cache")
+	s.log.InfoContext(ctx, "Successfully restored cache", slog.Int("orders", len(orders)))
 
 	return nil
 }
+
+// getFromCache ищет заказ в кеше в отдельном span'е.
+func (s *OrderRequestService) getFromCache(ctx context.Context, orderUID string) (*domain.Order, bool) {
+	_, span := tracer.Start(ctx, "OrderCache.GetOrder")
+	defer span.End()
+
+	order, ok := s.cache.GetOrder(orderUID)
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+
+	return order, ok
+}
@@ -1,8 +1,11 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"testing"
 
 	"order_service/config"
@@ -12,6 +15,10 @@ import (
 	"order_service/internal/usecase"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 )
 
@@ -108,7 +115,6 @@ var (
 )
 
 func TestGetOrder(t *testing.T) {
-	logger.InitLogger(cfg)
 
 	for i, testCase := range tblForGetOrder {
 		t.Run(fmt.Sprintf("test_'GetOrder'_case №%d", i+1), func(t *testing.T) {
@@ -116,7 +122,7 @@ func TestGetOrder(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockOrderRepo := mock.NewMockOrderRepository(ctrl)
 			mockOrderCache := mock.NewMockOrderCache(ctrl)
-			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo)
+			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
 
 			mockOrderCache.
 				EXPECT().
@@ -150,7 +156,6 @@ func TestGetOrder(t *testing.T) {
 }
 
 func TestSaveOrder(t *testing.T) {
-	logger.InitLogger(cfg)
 
 	for i, testCase := range tblForSaveOrder {
 		t.Run(fmt.Sprintf("test_'SaveOrder'_case №%d", i+1), func(t *testing.T) {
@@ -158,19 +163,23 @@ func TestSaveOrder(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockOrderRepo := mock.NewMockOrderRepository(ctrl)
 			mockOrderCache := mock.NewMockOrderCache(ctrl)
-			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo)
-
-			mockOrderCache.
-				EXPECT().
-				SaveOrder(gomock.Any(), testCase.inputOrderData).
-				Return()
+			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
 
 			mockOrderRepo.
 				EXPECT().
 				SaveOrder(gomock.Any(), testCase.inputOrderData).
-				Return(testCase.outputErr)
+				Return(testCase.outputErr == nil, testCase.outputErr)
+
+			// В кеш попадает только сохраненный заказ
+			if testCase.outputErr == nil {
+				mockOrderCache.
+					EXPECT().
+					SaveOrder(gomock.Any(), testCase.inputOrderData).
+					Return()
+			}
 
-			err := service.SaveOrder(context.TODO(), testCase.inputOrderData)
+			inserted, err := service.SaveOrder(context.TODO(), testCase.inputOrderData)
+			require.Equal(t, testCase.expectedErr == nil, inserted)
 
 			if testCase.expectedErr != nil {
 				require.ErrorContains(t, err, testCase.expectedErr.Error())
@@ -183,7 +192,6 @@ func TestSaveOrder(t *testing.T) {
 
 func TestRestoreOrder(t *testing.T) {
 	cfg.Capacity = 1
-	logger.InitLogger(cfg)
 
 	for i, testCase := range tblForRestoreOrder {
 		t.Run(fmt.Sprintf("test_'RestoreOrder'_case №%d", i+1), func(t *testing.T) {
@@ -191,7 +199,7 @@ func TestRestoreOrder(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockOrderRepo := mock.NewMockOrderRepository(ctrl)
 			mockOrderCache := mock.NewMockOrderCache(ctrl)
-			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo)
+			service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
 
 			mockOrderRepo.
 				EXPECT().
@@ -215,3 +223,241 @@ func TestRestoreOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestTracing(t *testing.T) {
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
+
+	order := &domain.Order{OrderUID: "traced_order"}
+
+	mockOrderCache.EXPECT().GetOrder(order.OrderUID).Return(nil, false)
+	mockOrderRepo.EXPECT().GetOrder(gomock.Any(), order.OrderUID).Return(order, nil)
+	mockOrderCache.EXPECT().SaveOrder(order.OrderUID, order)
+
+	_, err := service.GetOrder(context.TODO(), order.OrderUID)
+	require.NoError(t, err)
+
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), order).Return(false, domain.ErrInternalServer)
+
+	_, err = service.SaveOrder(context.TODO(), order)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// Поиск в кеше — дочерний span GetOrder
+	require.Equal(t, "OrderCache.GetOrder", spans[0].Name())
+	require.Equal(t, "OrderRequestService.GetOrder", spans[1].Name())
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	require.Equal(t, "OrderRequestService.SaveOrder", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.New(&buf, logger.FormatJSON, new(slog.LevelVar))
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, log)
+
+	order := &domain.Order{OrderUID: "logged_order"}
+	mockOrderCache.EXPECT().SaveOrder(order.OrderUID, order)
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), order).Return(true, nil)
+
+	ctx := logger.WithMessage(context.TODO(), 1, 10)
+	_, err = service.SaveOrder(ctx, order)
+	require.NoError(t, err)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "Successfully saved order", record["msg"])
+	require.Equal(t, order.OrderUID, record[logger.KeyOrderUID])
+	require.EqualValues(t, 1, record[logger.KeyPartition])
+	require.EqualValues(t, 10, record[logger.KeyOffset])
+}
+
+func TestLoggingRedactsPII(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	log, err := logger.New(&buf, logger.FormatJSON, level)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, log)
+
+	order := &domain.Order{
+		OrderUID: "pii_order",
+		Delivery: domain.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Address: "Ploshad Mira 15",
+			Email:   "test@gmail.com",
+		},
+	}
+	mockOrderCache.EXPECT().SaveOrder(order.OrderUID, order)
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), order).Return(true, nil)
+
+	_, err = service.SaveOrder(context.TODO(), order)
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "Saving order")
+	for _, raw := range []string{"Test Testov", "+9720000000", "Ploshad Mira 15", "test@gmail.com"} {
+		require.NotContains(t, out, raw)
+	}
+	require.Contains(t, out, "+972*****00")
+	require.Contains(t, out, "t***@gmail.com")
+}
+
+func TestListAndFindOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
+
+	orders := []*domain.Order{{OrderUID: "listed_order"}}
+	mockOrderRepo.EXPECT().GetOrders(gomock.Any(), 10).Return(orders, nil)
+	listed, err := service.ListOrders(context.TODO(), 10)
+	require.NoError(t, err)
+	require.Equal(t, orders, listed)
+
+	mockOrderRepo.EXPECT().FindOrdersByEmail(gomock.Any(), "test@gmail.com").Return(nil, domain.ErrOrdersNotFound)
+	_, err = service.FindOrdersByEmail(context.TODO(), "test@gmail.com")
+	require.ErrorIs(t, err, domain.ErrOrdersNotFound)
+}
+
+func TestWatchOrderEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
+
+	mockOrderCache.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).AnyTimes()
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watched, err := service.WatchOrderEvents(ctx, 0)
+	require.NoError(t, err)
+	slowCtx, slowCancel := context.WithCancel(context.Background())
+	defer slowCancel()
+	slow, err := service.WatchOrderEvents(slowCtx, 0)
+	require.NoError(t, err)
+
+	order := &domain.Order{OrderUID: "watched_order"}
+	_, err = service.SaveOrder(context.TODO(), order)
+	require.NoError(t, err)
+	first := <-watched
+	require.Equal(t, order, first.Order)
+	require.Equal(t, domain.EventOrderCreated, first.Type)
+	require.NotZero(t, first.ID)
+
+	// Подписчик, который не читает, отключается после переполнения буфера
+	for range 100 {
+		_, err = service.SaveOrder(context.TODO(), order)
+		require.NoError(t, err)
+		<-watched
+	}
+	drained := 0
+	for range slow {
+		drained++
+	}
+	require.Less(t, drained, 101)
+
+	// Отключенный подписчик догоняет пропущенное по журналу
+	resumed, err := service.WatchOrderEvents(context.Background(), first.ID)
+	require.NoError(t, err)
+	for i := range uint64(100) {
+		require.Equal(t, first.ID+1+i, (<-resumed).ID)
+	}
+	_, err = service.SaveOrder(context.TODO(), order)
+	require.NoError(t, err)
+	require.Equal(t, first.ID+101, (<-resumed).ID, "replay must be followed by live events")
+	<-watched
+
+	// Подписка с последнего события получает только новые
+	caughtUp, err := service.WatchOrderEvents(context.Background(), first.ID+101)
+	require.NoError(t, err)
+	require.Empty(t, caughtUp)
+
+	// ID из будущего (например, от прошлого процесса) продолжить нельзя
+	_, err = service.WatchOrderEvents(context.Background(), first.ID+1000)
+	require.ErrorIs(t, err, domain.ErrEventsExpired)
+
+	// После отмены контекста канал закрывается
+	cancel()
+	_, ok := <-watched
+	require.False(t, ok)
+}
+
+func TestWatchOrderEventsNotInserted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched, err := service.WatchOrderEvents(ctx, 0)
+	require.NoError(t, err)
+
+	// Заказ с тем же order_uid уже сохранен: событие не публикуется, а присланная версия не попадает в кеш
+	existing := &domain.Order{OrderUID: "existing_order"}
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), existing).Return(false, nil)
+	_, err = service.SaveOrder(context.TODO(), existing)
+	require.NoError(t, err)
+
+	inserted := &domain.Order{OrderUID: "inserted_order"}
+	mockOrderCache.EXPECT().SaveOrder(inserted.OrderUID, inserted)
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), inserted).Return(true, nil)
+	_, err = service.SaveOrder(context.TODO(), inserted)
+	require.NoError(t, err)
+
+	event := <-watched
+	require.Equal(t, inserted, event.Order)
+	require.Empty(t, watched)
+}
+
+func TestWatchOrderEventsExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockOrderCache := mock.NewMockOrderCache(ctrl)
+	service := usecase.NewOrderRequestService(mockOrderCache, mockOrderRepo, logger.Discard())
+
+	mockOrderCache.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).AnyTimes()
+	mockOrderRepo.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched, err := service.WatchOrderEvents(ctx, 0)
+	require.NoError(t, err)
+	_, err = service.SaveOrder(context.TODO(), &domain.Order{OrderUID: "first"})
+	require.NoError(t, err)
+	first := <-watched
+
+	// Журнал ограничен: самые старые события вытесняются
+	for range 1100 {
+		_, err = service.SaveOrder(context.TODO(), &domain.Order{OrderUID: "next"})
+		require.NoError(t, err)
+	}
+
+	_, err = service.WatchOrderEvents(context.Background(), first.ID)
+	require.ErrorIs(t, err, domain.ErrEventsExpired)
+
+	recent, err := service.WatchOrderEvents(context.Background(), first.ID+1100-10)
+	require.NoError(t, err)
+	require.Len(t, recent, 10)
+}
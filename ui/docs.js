@@ -0,0 +1,187 @@
+// Просмотр OpenAPI спецификации сервиса с возможностью отправить запрос из браузера.
+const METHODS = ["get", "put", "post", "patch", "delete"];
+
+function escapeHTML(value) {
+  return String(value ?? "")
+    .replaceAll("&", "&amp;")
+    .replaceAll("<", "&lt;")
+    .replaceAll(">", "&gt;")
+    .replaceAll('"', "&quot;");
+}
+
+// resolve возвращает объект по локальной ссылке "#/components/...".
+function resolve(spec, node) {
+  if (!node || !node.$ref) {
+    return node;
+  }
+  return node.$ref
+    .replace(/^#\//, "")
+    .split("/")
+    .reduce((obj, key) => obj[key], spec);
+}
+
+function refName(node) {
+  return node && node.$ref ? node.$ref.split("/").pop() : "";
+}
+
+function renderParameters(spec, parameters) {
+  if (!parameters || parameters.length === 0) {
+    return "";
+  }
+  const rows = parameters
+    .map((p) => resolve(spec, p))
+    .map(
+      (p) => `<tr>
+        <td><code>${escapeHTML(p.name)}</code>${p.required ? " *" : ""}</td>
+        <td>${escapeHTML(p.in)}</td>
+        <td>${escapeHTML(p.schema && p.schema.type)}</td>
+        <td>${escapeHTML(p.description)}</td>
+      </tr>`
+    )
+    .join("");
+  return `<h4>Parameters</h4><table><tbody>${rows}</tbody></table>`;
+}
+
+function renderResponses(spec, responses) {
+  const rows = Object.entries(responses || {})
+    .map(([status, response]) => {
+      const name = refName(response);
+      response = resolve(spec, response);
+      const types = Object.entries(response.content || {})
+        .map(([type, media]) => `${escapeHTML(type)} ${escapeHTML(refName(media.schema))}`)
+        .join("<br>");
+      return `<tr>
+        <td><strong>${escapeHTML(status)}</strong></td>
+        <td>${escapeHTML(response.description || name)}</td>
+        <td>${types}</td>
+      </tr>`;
+    })
+    .join("");
+  return `<h4>Responses</h4><table><tbody>${rows}</tbody></table>`;
+}
+
+function renderTryIt(spec, id, path, method, operation) {
+  const params = (operation.parameters || [])
+    .map((p) => resolve(spec, p))
+    .filter((p) => p.in === "path" || p.in === "query")
+    .map(
+      (p) =>
+        `<input data-in="${escapeHTML(p.in)}" data-name="${escapeHTML(p.name)}" placeholder="${escapeHTML(p.name)} (${escapeHTML(p.in)})" value="${escapeHTML(p.example ?? "")}" />`
+    )
+    .join("");
+  const body = operation.requestBody
+    ? `<textarea rows="4" data-body placeholder="JSON body"></textarea>`
+    : "";
+  return `<div class="try-it" id="try-${id}">
+      <h4>Try it out</h4>
+      ${params}${body}
+      <button onclick="send('${id}', '${escapeHTML(path)}', '${method}')">Send</button>
+      <pre data-result hidden></pre>
+    </div>`;
+}
+
+function renderOperations(spec) {
+  const container = document.getElementById("operations");
+  let html = "";
+  let id = 0;
+  for (const [path, item] of Object.entries(spec.paths)) {
+    for (const method of METHODS) {
+      const operation = item[method];
+      if (!operation) {
+        continue;
+      }
+      id++;
+      html += `<details class="operation">
+        <summary>
+          <span class="method ${method}">${method.toUpperCase()}</span>
+          <span class="path">${escapeHTML(path)}</span>
+          <span>${escapeHTML(operation.summary)}</span>
+        </summary>
+        <div class="body">
+          <p>${escapeHTML(operation.description)}</p>
+          ${renderParameters(spec, operation.parameters)}
+          ${renderResponses(spec, operation.responses)}
+          ${renderTryIt(spec, id, path, method, operation)}
+        </div>
+      </details>`;
+    }
+  }
+  container.innerHTML = html;
+}
+
+function renderSchemas(spec) {
+  const container = document.getElementById("schemas");
+  container.innerHTML = Object.entries(spec.components.schemas)
+    .map(
+      ([name, schema]) => `<details class="operation">
+        <summary><span class="path">${escapeHTML(name)}</span><span>${escapeHTML(schema.description)}</span></summary>
+        <div class="body"><pre>${escapeHTML(JSON.stringify(schema, null, 2))}</pre></div>
+      </details>`
+    )
+    .join("");
+}
+
+function send(id, path, method) {
+  const block = document.getElementById(`try-${id}`);
+  const query = new URLSearchParams();
+
+  block.querySelectorAll("input[data-in]").forEach((input) => {
+    if (!input.value) {
+      return;
+    }
+    if (input.dataset.in === "path") {
+      path = path.replace(`{${input.dataset.name}}`, encodeURIComponent(input.value));
+    } else {
+      query.set(input.dataset.name, input.value);
+    }
+  });
+
+  const headers = {};
+  const apiKey = document.getElementById("api-key").value;
+  if (apiKey) {
+    headers["X-API-Key"] = apiKey;
+  }
+
+  const options = { method: method.toUpperCase(), headers };
+  const body = block.querySelector("textarea[data-body]");
+  if (body) {
+    headers["Content-Type"] = "application/json";
+    options.body = body.value;
+  }
+
+  const url = query.toString() ? `${path}?${query}` : path;
+  const result = block.querySelector("pre[data-result]");
+  result.hidden = false;
+  result.textContent = "...";
+
+  fetch(url, options)
+    .then(async (response) => {
+      result.textContent = `${response.status} ${response.statusText}\n\n`;
+      // Тело дописывается по мере прихода, чтобы поток событий был виден сразу
+      const reader = response.body.getReader();
+      const decoder = new TextDecoder();
+      for (;;) {
+        const { done, value } = await reader.read();
+        if (done) {
+          break;
+        }
+        result.textContent += decoder.decode(value, { stream: true });
+      }
+    })
+    .catch((error) => {
+      result.textContent = `Ошибка: ${error.message}`;
+    });
+}
+
+fetch("/api/openapi.json")
+  .then((response) => response.json())
+  .then((spec) => {
+    document.getElementById("api-title").textContent = `${spec.info.title} ${spec.info.version}`;
+    document.getElementById("api-description").textContent = spec.info.description;
+    renderOperations(spec);
+    renderSchemas(spec);
+  })
+  .catch((error) => {
+    console.error("Ошибка загрузки спецификации:", error);
+    alert(`Ошибка: ${error.message}`);
+  });